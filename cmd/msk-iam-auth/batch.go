@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Name of the subcommand generating tokens for the clusters of a file.
+	batchCommand = "batch"
+
+	// Default number of tokens generated at the same time in batch mode.
+	defaultBatchParallelism = 8
+)
+
+// Names of the clusters of a batch file, used as token file names.
+var batchClusterName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Clusters file of batch mode, e.g.:
+//
+//	clusters:
+//	  - name: orders
+//	    region: us-east-1
+//	    roleArn: arn:aws:iam::123456789012:role/orders-kafka
+//	  - name: payments
+//	    clusterArn: arn:aws:kafka:eu-west-1:123456789012:cluster/payments/abc-123
+//	    profile: payments
+type batchFile struct {
+	Clusters []batchCluster `yaml:"clusters"`
+}
+
+// A cluster of the batch file and the credentials its token is signed with, from the profile, by assuming the role,
+// or from the default credential chain.
+type batchCluster struct {
+	// Name identifies the cluster and names its token file. It is required and unique.
+	Name string `yaml:"name"`
+
+	// Region of the cluster, taken from ClusterARN when empty.
+	Region string `yaml:"region"`
+
+	// ClusterARN of the cluster, used to find the region.
+	ClusterARN string `yaml:"clusterArn"`
+
+	// Profile is the named profile credentials are loaded from.
+	Profile string `yaml:"profile"`
+
+	// RoleARN is the role assumed to sign the token.
+	RoleARN string `yaml:"roleArn"`
+
+	// SessionName is the session name of the assumed role.
+	SessionName string `yaml:"sessionName"`
+}
+
+// Outcome of generating the token of a cluster.
+type batchResult struct {
+	expirationTimeMs int64
+	err              error
+}
+
+// Parses the batch command line and generates a token for every cluster of the clusters file, writing each to
+// <out-dir>/<name>.token, or <name>.json with -output json. Every cluster is attempted; a line per cluster reports
+// the outcome on stdout and the command exits with ExitError when any token could not be generated.
+func runBatch(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("msk-iam-auth batch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	clustersFile := flags.String("clusters", "", "YAML file listing the clusters to generate tokens for")
+	outDir := flags.String("out-dir", "", "directory the token files are written to")
+	output := flags.String("output", outputToken, "token file format, token or json")
+	parallelism := flags.Int("parallelism", defaultBatchParallelism, "number of tokens generated at the same time")
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if *clustersFile == "" || *outDir == "" {
+		fmt.Fprintln(stderr, "msk-iam-auth batch: -clusters and -out-dir are required")
+		return ExitUsage
+	}
+	if *output != outputToken && *output != outputJSON {
+		fmt.Fprintf(stderr, "msk-iam-auth batch: unknown output format %q\n", *output)
+		return ExitUsage
+	}
+	if *parallelism < 1 {
+		fmt.Fprintln(stderr, "msk-iam-auth batch: -parallelism must be at least 1")
+		return ExitUsage
+	}
+
+	clusters, err := loadBatchFile(*clustersFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "msk-iam-auth batch: %v\n", err)
+		return ExitUsage
+	}
+	if err := os.MkdirAll(*outDir, 0o700); err != nil {
+		fmt.Fprintf(stderr, "msk-iam-auth batch: failed to create the output directory: %v\n", err)
+		return ExitError
+	}
+
+	results := generateBatch(ctx, clusters, *parallelism, func(ctx context.Context, cluster batchCluster) batchResult {
+		generate := newGenerateFunc(cluster.Region, cluster.Profile, cluster.RoleARN, cluster.SessionName)
+		token, expirationTimeMs, err := generate(ctx)
+		if err == nil {
+			err = writeTokenFile(*outDir, cluster, *output, token, expirationTimeMs)
+		}
+		return batchResult{expirationTimeMs: expirationTimeMs, err: err}
+	})
+
+	status := ExitOK
+	for i, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(stdout, "%s: failed: %v\n", clusters[i].Name, result.err)
+			status = ExitError
+			continue
+		}
+		expiresAt := time.UnixMilli(result.expirationTimeMs).UTC().Format(time.RFC3339)
+		fmt.Fprintf(stdout, "%s: ok, expires %s\n", clusters[i].Name, expiresAt)
+	}
+	return status
+}
+
+// Reads and validates the clusters of the batch file, resolving their regions.
+func loadBatchFile(path string) ([]batchCluster, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the clusters file: %w", err)
+	}
+
+	var file batchFile
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse the clusters file %s: %w", path, err)
+	}
+	if len(file.Clusters) == 0 {
+		return nil, fmt.Errorf("the clusters file %s lists no clusters", path)
+	}
+
+	names := make(map[string]bool, len(file.Clusters))
+	for i := range file.Clusters {
+		cluster := &file.Clusters[i]
+		if err := resolveBatchCluster(cluster); err != nil {
+			return nil, fmt.Errorf("cluster %d of %s: %w", i+1, path, err)
+		}
+		if names[cluster.Name] {
+			return nil, fmt.Errorf("cluster %d of %s: duplicate name %q", i+1, path, cluster.Name)
+		}
+		names[cluster.Name] = true
+	}
+	return file.Clusters, nil
+}
+
+// Validates the cluster and resolves its region from the cluster ARN when not set.
+func resolveBatchCluster(cluster *batchCluster) error {
+	if !batchClusterName.MatchString(cluster.Name) {
+		return fmt.Errorf("name %q must be made of letters, digits, dots, dashes and underscores", cluster.Name)
+	}
+	if cluster.Profile != "" && cluster.RoleARN != "" {
+		return fmt.Errorf("%s: profile and roleArn cannot be used together", cluster.Name)
+	}
+	if cluster.Region == "" && cluster.ClusterARN != "" {
+		region, err := signer.RegionFromClusterARN(cluster.ClusterARN)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cluster.Name, err)
+		}
+		cluster.Region = region
+	}
+	if cluster.Region == "" {
+		return fmt.Errorf("%s: region or clusterArn is required", cluster.Name)
+	}
+	return nil
+}
+
+// Generates the results of the clusters, at most parallelism at a time, in the order of the clusters.
+func generateBatch(
+	ctx context.Context, clusters []batchCluster, parallelism int,
+	generate func(ctx context.Context, cluster batchCluster) batchResult,
+) []batchResult {
+	results := make([]batchResult, len(clusters))
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, cluster batchCluster) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = generate(ctx, cluster)
+		}(i, cluster)
+	}
+	wg.Wait()
+	return results
+}
+
+// Writes the token of the cluster to its file of the output directory in the output format, replacing the file
+// atomically so readers never see a partial token.
+func writeTokenFile(outDir string, cluster batchCluster, output string, token string, expirationTimeMs int64) error {
+	var content bytes.Buffer
+	if err := writeToken(&content, output, cluster.Region, token, expirationTimeMs); err != nil {
+		return err
+	}
+
+	extension := ".token"
+	if output == outputJSON {
+		extension = ".json"
+	}
+	path := filepath.Join(outDir, cluster.Name+extension)
+	tmp, err := os.CreateTemp(outDir, "."+cluster.Name+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write token file %s: %w", path, err)
+	}
+	return nil
+}
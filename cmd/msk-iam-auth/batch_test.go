@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Writes the clusters file to a temporary directory, returning its path.
+func writeClustersFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "clusters.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRunBatch(t *testing.T) {
+	setTestConfig(t)
+	clusters := writeClustersFile(t, `
+clusters:
+  - name: orders
+    region: us-west-2
+    profile: static
+  - name: payments
+    clusterArn: arn:aws:kafka:eu-west-1:123456789012:cluster/payments/abc-123
+    profile: static
+`)
+	outDir := filepath.Join(t.TempDir(), "tokens")
+
+	status, stdout, stderr := runCommand("batch", "-clusters", clusters, "-out-dir", outDir)
+
+	assert.Equal(t, ExitOK, status, stderr)
+	assert.Regexp(t, `^orders: ok, expires \S+\npayments: ok, expires \S+\n$`, stdout)
+	for name, region := range map[string]string{"orders": "us-west-2", "payments": "eu-west-1"} {
+		content, err := os.ReadFile(filepath.Join(outDir, name+".token"))
+		assert.NoError(t, err)
+		params, err := signer.ParseToken(string(content[:len(content)-1]))
+		assert.NoError(t, err)
+		assert.Equal(t, region, params.Region)
+	}
+}
+
+func TestRunBatchWithJSONOutput(t *testing.T) {
+	setTestConfig(t)
+	clusters := writeClustersFile(t, "clusters:\n  - {name: orders, region: us-west-2, profile: static}\n")
+	outDir := t.TempDir()
+
+	status, _, _ := runCommand("batch", "-clusters", clusters, "-out-dir", outDir, "-output", "json")
+
+	assert.Equal(t, ExitOK, status)
+	content, err := os.ReadFile(filepath.Join(outDir, "orders.json"))
+	assert.NoError(t, err)
+	var token signer.Token
+	assert.NoError(t, json.Unmarshal(content, &token))
+	assert.Equal(t, "us-west-2", token.Region)
+}
+
+func TestRunBatchReportsFailedClusters(t *testing.T) {
+	setTestConfig(t)
+	clusters := writeClustersFile(t, `
+clusters:
+  - {name: missing, region: us-west-2, profile: missing}
+  - {name: orders, region: us-west-2, profile: static}
+`)
+	outDir := t.TempDir()
+
+	status, stdout, _ := runCommand("batch", "-clusters", clusters, "-out-dir", outDir, "-parallelism", "1")
+
+	assert.Equal(t, ExitError, status)
+	assert.Contains(t, stdout, "missing: failed:")
+	assert.Contains(t, stdout, "orders: ok")
+	assert.FileExists(t, filepath.Join(outDir, "orders.token"))
+	assert.NoFileExists(t, filepath.Join(outDir, "missing.token"))
+}
+
+func TestRunBatchUsageErrors(t *testing.T) {
+	setTestConfig(t)
+	outDir := t.TempDir()
+
+	for _, test := range []struct {
+		clusters string
+		message  string
+	}{
+		{"clusters: []", "lists no clusters"},
+		{"clusters:\n  - {name: ../orders, region: us-west-2}", "must be made of letters"},
+		{"clusters:\n  - {name: orders}", "orders: region or clusterArn is required"},
+		{"clusters:\n  - {name: orders, clusterArn: arn:aws:s3:::bucket}", "orders:"},
+		{"clusters:\n  - {name: a, region: us-west-2}\n  - {name: a, region: us-west-2}", `duplicate name "a"`},
+		{"clusters:\n  - {name: a, region: us-west-2, profile: p, roleArn: r}", "cannot be used together"},
+		{"clusters:\n  - {name: a, region: us-west-2, cluster: b}", "field cluster not found"},
+	} {
+		status, _, stderr := runCommand("batch", "-clusters", writeClustersFile(t, test.clusters), "-out-dir", outDir)
+
+		assert.Equal(t, ExitUsage, status, test.clusters)
+		assert.Contains(t, stderr, test.message, test.clusters)
+	}
+
+	for _, args := range [][]string{
+		{"batch"},
+		{"batch", "-clusters", "clusters.yaml"},
+		{"batch", "-clusters", "clusters.yaml", "-out-dir", outDir, "-output", "yaml"},
+		{"batch", "-clusters", "clusters.yaml", "-out-dir", outDir, "-parallelism", "0"},
+	} {
+		status, _, stderr := runCommand(args...)
+
+		assert.Equal(t, ExitUsage, status, args)
+		assert.NotEmpty(t, stderr, args)
+	}
+}
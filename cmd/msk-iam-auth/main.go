@@ -0,0 +1,155 @@
+// Command msk-iam-auth generates MSK IAM auth tokens from the command line, for scripts and Kafka clients that are
+// not written in Go.
+//
+//	msk-iam-auth -region us-west-2 -profile kafka-dev
+//	msk-iam-auth -region us-west-2 -role-arn arn:aws:iam::123456789012:role/kafka -output json
+//	msk-iam-auth -region us-west-2 -watch -metrics-addr 127.0.0.1:9464
+//	msk-iam-auth batch -clusters clusters.yaml -out-dir ./tokens
+//	msk-iam-auth probe -broker b-1.demo.abc123.c2.kafka.us-west-2.amazonaws.com:9098
+//
+// In watch mode a new token is written as a line each time the previous one is due for refresh, until the command is
+// interrupted, and -metrics-addr serves the token age and refresh failures on /metrics in the OpenMetrics format.
+//
+// The batch subcommand generates tokens for the clusters listed in a YAML file in parallel, writing each to a file of
+// the output directory named after the cluster, see runBatch.
+//
+// The probe subcommand authenticates to a broker with a token, verifying connectivity and IAM setup without a Kafka
+// client, and exits with ExitAuthenticationFailed when the broker rejects the token, see runProbe.
+//
+// The token is written to stdout. Profiles backed by AWS IAM Identity Center (SSO) are supported; when their SSO login
+// has expired the command tells which "aws sso login" command refreshes it and exits with ExitSSOLoginRequired.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+)
+
+const (
+	ExitOK                   = 0 // ExitOK is the exit status of a successful run.
+	ExitError                = 1 // ExitError is the exit status when the token cannot be generated.
+	ExitUsage                = 2 // ExitUsage is the exit status of invalid command lines.
+	ExitSSOLoginRequired     = 3 // ExitSSOLoginRequired is the exit status when the SSO login of the profile expired.
+	ExitAuthenticationFailed = 4 // ExitAuthenticationFailed is the exit status when a probed broker rejected the token.
+)
+
+// Output formats of the token.
+const (
+	outputToken = "token"
+	outputJSON  = "json"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	status := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(status)
+}
+
+// Generates a token value and its expiration time in epoch millis.
+type generateFunc func(ctx context.Context) (string, int64, error)
+
+// Parses the command line and writes the token, returning the exit status.
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == batchCommand {
+		return runBatch(ctx, args[1:], stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == probeCommand {
+		return runProbe(ctx, args[1:], stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("msk-iam-auth", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	region := flags.String("region", os.Getenv("AWS_REGION"), "AWS region of the MSK cluster, defaults to AWS_REGION")
+	profile := flags.String("profile", "", "named profile to load credentials from, including SSO profiles")
+	roleARN := flags.String("role-arn", "", "ARN of the role to assume")
+	sessionName := flags.String("session-name", "", "session name of the assumed role")
+	output := flags.String("output", outputToken, "output format, token or json")
+	watchMode := flags.Bool("watch", false, "keep running, writing a new token each time the previous one is refreshed")
+	metricsAddr := flags.String("metrics-addr", "", "address serving OpenMetrics on /metrics in watch mode")
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if *region == "" {
+		fmt.Fprintln(stderr, "msk-iam-auth: -region or AWS_REGION is required")
+		return ExitUsage
+	}
+	if *profile != "" && *roleARN != "" {
+		fmt.Fprintln(stderr, "msk-iam-auth: -profile and -role-arn cannot be used together")
+		return ExitUsage
+	}
+	if *output != outputToken && *output != outputJSON {
+		fmt.Fprintf(stderr, "msk-iam-auth: unknown output format %q\n", *output)
+		return ExitUsage
+	}
+	if *metricsAddr != "" && !*watchMode {
+		fmt.Fprintln(stderr, "msk-iam-auth: -metrics-addr requires -watch")
+		return ExitUsage
+	}
+
+	generate := newGenerateFunc(*region, *profile, *roleARN, *sessionName)
+	write := func(token string, expirationTimeMs int64) error {
+		return writeToken(stdout, *output, *region, token, expirationTimeMs)
+	}
+
+	if *watchMode {
+		return runWatch(ctx, generate, write, *metricsAddr, stderr)
+	}
+
+	token, expirationTimeMs, err := generate(ctx)
+	if err != nil {
+		return reportError(stderr, err)
+	}
+	if err := write(token, expirationTimeMs); err != nil {
+		return reportError(stderr, err)
+	}
+	return ExitOK
+}
+
+// Returns the function generating tokens for the region from the profile, by assuming the role, or from the default
+// credential chain.
+func newGenerateFunc(region string, profile string, roleARN string, sessionName string) generateFunc {
+	return func(ctx context.Context) (string, int64, error) {
+		switch {
+		case profile != "":
+			return signer.GenerateAuthTokenFromProfile(ctx, region, profile)
+		case roleARN != "":
+			return signer.GenerateAuthTokenFromRole(ctx, region, roleARN, sessionName)
+		default:
+			return signer.GenerateAuthToken(ctx, region)
+		}
+	}
+}
+
+// Writes the token as a line in the output format.
+func writeToken(w io.Writer, output string, region string, token string, expirationTimeMs int64) error {
+	if output == outputJSON {
+		doc, err := json.Marshal(signer.Token{Value: token, ExpirationTimeMs: expirationTimeMs, Region: region})
+		if err != nil {
+			return err
+		}
+		token = string(doc)
+	}
+	_, err := fmt.Fprintln(w, token)
+	return err
+}
+
+// Writes the error to stderr and returns the exit status it maps to.
+func reportError(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "msk-iam-auth: %v\n", err)
+	var ssoErr *signer.SSOProfileError
+	if errors.As(err, &ssoErr) && ssoErr.LoginRequired() {
+		return ExitSSOLoginRequired
+	}
+	return ExitError
+}
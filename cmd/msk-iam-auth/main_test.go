@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfig = `
+[profile static]
+aws_access_key_id = AKIDEXAMPLE
+aws_secret_access_key = wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY
+
+[profile sso-dev]
+sso_session = corp
+sso_account_id = 123456789012
+sso_role_name = Kafka
+
+[sso-session corp]
+sso_region = us-east-1
+sso_start_url = https://corp.awsapps.com/start
+`
+
+// Points the shared config at the test config, with an empty SSO token cache.
+func setTestConfig(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	assert.NoError(t, os.WriteFile(configFile, []byte(testConfig), 0o600))
+	t.Setenv("HOME", dir)
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "")
+}
+
+// Runs the command, returning its exit status, stdout and stderr.
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(context.Background(), args, &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestRunWithProfile(t *testing.T) {
+	setTestConfig(t)
+
+	status, stdout, _ := runCommand("-region", "us-west-2", "-profile", "static")
+
+	assert.Equal(t, ExitOK, status)
+	assert.NotEmpty(t, stdout)
+}
+
+func TestRunWithJSONOutput(t *testing.T) {
+	setTestConfig(t)
+
+	status, stdout, _ := runCommand("-region", "us-west-2", "-profile", "static", "-output", "json")
+
+	assert.Equal(t, ExitOK, status)
+	var token signer.Token
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &token))
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, "us-west-2", token.Region)
+}
+
+func TestRunWithExpiredSSOLogin(t *testing.T) {
+	setTestConfig(t)
+
+	status, _, stderr := runCommand("-region", "us-west-2", "-profile", "sso-dev")
+
+	assert.Equal(t, ExitSSOLoginRequired, status)
+	assert.Contains(t, stderr, "aws sso login --sso-session corp")
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	setTestConfig(t)
+
+	for _, args := range [][]string{
+		{},
+		{"-region", "us-west-2", "-profile", "static", "-role-arn", "arn:aws:iam::123456789012:role/kafka"},
+		{"-region", "us-west-2", "-output", "yaml"},
+		{"-unknown"},
+	} {
+		status, _, stderr := runCommand(args...)
+
+		assert.Equal(t, ExitUsage, status, args)
+		assert.NotEmpty(t, stderr, args)
+	}
+}
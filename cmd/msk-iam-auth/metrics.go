@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	metricsPath        = "/metrics"
+	openMetricsContent = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// Tracks the tokens generated in watch mode, served in the OpenMetrics text format.
+type watchMetrics struct {
+	mu        sync.Mutex
+	issuedAt  time.Time
+	expiresAt time.Time
+	refreshes uint64
+	failures  uint64
+	now       func() time.Time
+}
+
+// Returns metrics with no token generated yet.
+func newWatchMetrics() *watchMetrics {
+	return &watchMetrics{now: time.Now}
+}
+
+// Records a token generated at issuedAt.
+func (m *watchMetrics) observeToken(issuedAt time.Time, expiresAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.issuedAt, m.expiresAt = issuedAt, expiresAt
+	m.refreshes++
+}
+
+// Records a failed refresh.
+func (m *watchMetrics) observeFailure() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures++
+}
+
+// Writes the metrics in the OpenMetrics text format. The age and ttl are omitted until a token is generated.
+func (m *watchMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	if !m.issuedAt.IsZero() {
+		now := m.now()
+		b.WriteString("# TYPE msk_iam_auth_token_age_seconds gauge\n")
+		b.WriteString("# UNIT msk_iam_auth_token_age_seconds seconds\n")
+		b.WriteString("# HELP msk_iam_auth_token_age_seconds Time since the current token was generated.\n")
+		fmt.Fprintf(&b, "msk_iam_auth_token_age_seconds %g\n", now.Sub(m.issuedAt).Seconds())
+		b.WriteString("# TYPE msk_iam_auth_token_ttl_seconds gauge\n")
+		b.WriteString("# UNIT msk_iam_auth_token_ttl_seconds seconds\n")
+		b.WriteString("# HELP msk_iam_auth_token_ttl_seconds Remaining lifetime of the current token.\n")
+		fmt.Fprintf(&b, "msk_iam_auth_token_ttl_seconds %g\n", max(m.expiresAt.Sub(now).Seconds(), 0))
+	}
+	b.WriteString("# TYPE msk_iam_auth_token_refreshes counter\n")
+	b.WriteString("# HELP msk_iam_auth_token_refreshes Number of tokens generated.\n")
+	fmt.Fprintf(&b, "msk_iam_auth_token_refreshes_total %d\n", m.refreshes)
+	b.WriteString("# TYPE msk_iam_auth_token_refresh_failures counter\n")
+	b.WriteString("# HELP msk_iam_auth_token_refresh_failures Number of failed token refreshes.\n")
+	fmt.Fprintf(&b, "msk_iam_auth_token_refresh_failures_total %d\n", m.failures)
+	b.WriteString("# EOF\n")
+
+	w.Header().Set("Content-Type", openMetricsContent)
+	_, _ = w.Write([]byte(b.String()))
+}
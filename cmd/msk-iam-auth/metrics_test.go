@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchMetrics(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	metrics := newWatchMetrics()
+	metrics.now = func() time.Time { return now }
+	metrics.observeToken(now.Add(-30*time.Second), now.Add(14*time.Minute+30*time.Second))
+	metrics.observeFailure()
+	rec := httptest.NewRecorder()
+
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+
+	assert.Equal(t, openMetricsContent, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "msk_iam_auth_token_age_seconds 30\n")
+	assert.Contains(t, rec.Body.String(), "msk_iam_auth_token_ttl_seconds 870\n")
+	assert.Contains(t, rec.Body.String(), "msk_iam_auth_token_refreshes_total 1\n")
+	assert.Contains(t, rec.Body.String(), "msk_iam_auth_token_refresh_failures_total 1\n")
+	assert.True(t, strings.HasSuffix(rec.Body.String(), "# EOF\n"))
+}
+
+func TestWatchMetricsBeforeFirstToken(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	newWatchMetrics().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+
+	assert.NotContains(t, rec.Body.String(), "msk_iam_auth_token_age_seconds")
+	assert.Contains(t, rec.Body.String(), "msk_iam_auth_token_refresh_failures_total 0\n")
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+)
+
+// Name of the subcommand authenticating to a broker with a token.
+const probeCommand = "probe"
+
+// Adapts a generateFunc to the signer.TokenProvider the probe takes.
+type generateTokenProvider generateFunc
+
+// Token generates a new token.
+func (g generateTokenProvider) Token(ctx context.Context) (*signer.Token, error) {
+	value, expirationTimeMs, err := g(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &signer.Token{Value: value, ExpirationTimeMs: expirationTimeMs}, nil
+}
+
+// Parses the probe command line and authenticates to the broker with a token, see signer.Probe, writing how far the
+// probe got to stdout. The command exits with ExitAuthenticationFailed when the broker rejected the token and with
+// ExitError when it could not be reached or the token could not be generated.
+func runProbe(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("msk-iam-auth probe", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	broker := flags.String("broker", "", "address of the broker to authenticate to, host:port")
+	region := flags.String("region", os.Getenv("AWS_REGION"),
+		"AWS region of the MSK cluster, defaults to AWS_REGION or the region of the broker host")
+	profile := flags.String("profile", "", "named profile to load credentials from, including SSO profiles")
+	roleARN := flags.String("role-arn", "", "ARN of the role to assume")
+	sessionName := flags.String("session-name", "", "session name of the assumed role")
+	proxy := flags.String("proxy", "", "http or https proxy url the broker connection is tunneled through")
+	timeout := flags.Duration("timeout", signer.DefaultProbeTimeout, "timeout of the probe")
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if *broker == "" {
+		fmt.Fprintln(stderr, "msk-iam-auth probe: -broker is required")
+		return ExitUsage
+	}
+	if *region == "" {
+		if endpoint, err := signer.ParseBrokerEndpoint(*broker); err == nil {
+			*region = endpoint.Region
+		}
+	}
+	if *region == "" {
+		fmt.Fprintln(stderr, "msk-iam-auth probe: -region or AWS_REGION is required for brokers outside MSK")
+		return ExitUsage
+	}
+	if *profile != "" && *roleARN != "" {
+		fmt.Fprintln(stderr, "msk-iam-auth probe: -profile and -role-arn cannot be used together")
+		return ExitUsage
+	}
+
+	var proxyURL *url.URL
+	if *proxy != "" {
+		u, err := url.Parse(*proxy)
+		if err != nil {
+			fmt.Fprintf(stderr, "msk-iam-auth probe: invalid -proxy: %v\n", err)
+			return ExitUsage
+		}
+		proxyURL = u
+	}
+
+	provider := generateTokenProvider(newGenerateFunc(*region, *profile, *roleARN, *sessionName))
+	result, err := signer.Probe(ctx, *broker, provider, func(o *signer.ProbeOptions) {
+		o.ProxyURL = proxyURL
+		o.Timeout = *timeout
+	})
+	writeProbeResult(stdout, result)
+	if err != nil {
+		return reportProbeError(stderr, err)
+	}
+	return ExitOK
+}
+
+// Writes a line per step the probe got through.
+func writeProbeResult(w io.Writer, result *signer.ProbeResult) {
+	fmt.Fprintf(w, "broker: %s\n", result.Broker)
+	if result.TLSVersion != 0 {
+		fmt.Fprintf(w, "tls: %s\n", tls.VersionName(result.TLSVersion))
+	}
+	if len(result.Mechanisms) > 0 {
+		fmt.Fprintf(w, "sasl mechanisms: %s\n", strings.Join(result.Mechanisms, ", "))
+	}
+	fmt.Fprintf(w, "authenticated: %t\n", result.Authenticated)
+	if result.ErrorCode != 0 {
+		fmt.Fprintf(w, "error code: %d\n", result.ErrorCode)
+	}
+	if result.ErrorMessage != "" {
+		fmt.Fprintf(w, "error message: %s\n", result.ErrorMessage)
+	}
+	if result.SessionLifetime > 0 {
+		fmt.Fprintf(w, "session lifetime: %s\n", result.SessionLifetime)
+	}
+	fmt.Fprintf(w, "duration: %s\n", result.Duration.Round(time.Millisecond))
+}
+
+// Writes the probe error to stderr and returns the exit status it maps to.
+func reportProbeError(stderr io.Writer, err error) int {
+	if errors.Is(err, signer.ErrProbeAuthenticationFailed) {
+		fmt.Fprintf(stderr, "msk-iam-auth probe: %v\n", err)
+		return ExitAuthenticationFailed
+	}
+	return reportError(stderr, err)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the address of a port nothing listens on.
+func closedPort(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+	return addr
+}
+
+func TestRunProbeUnreachableBroker(t *testing.T) {
+	setTestConfig(t)
+
+	status, stdout, stderr := runCommand("probe", "-broker", closedPort(t), "-region", "us-west-2",
+		"-profile", "static")
+
+	assert.Equal(t, ExitError, status)
+	assert.Contains(t, stdout, "authenticated: false")
+	assert.Contains(t, stderr, "failed to connect to broker")
+}
+
+func TestRunProbeRegionFromBroker(t *testing.T) {
+	setTestConfig(t)
+
+	status, _, stderr := runCommand("probe", "-broker", "b-1.demo.abc123.c2.kafka.us-west-2.amazonaws.com:9098",
+		"-profile", "missing", "-timeout", "1s")
+
+	assert.Equal(t, ExitError, status)
+	assert.Contains(t, stderr, "failed to get auth token")
+}
+
+func TestReportProbeError(t *testing.T) {
+	var stderr bytes.Buffer
+
+	status := reportProbeError(&stderr, fmt.Errorf("%w: [58] access denied", signer.ErrProbeAuthenticationFailed))
+
+	assert.Equal(t, ExitAuthenticationFailed, status)
+	assert.Contains(t, stderr.String(), "access denied")
+	assert.Equal(t, ExitError, reportProbeError(&stderr, net.ErrClosed))
+}
+
+func TestRunProbeUsageErrors(t *testing.T) {
+	setTestConfig(t)
+
+	for _, args := range [][]string{
+		{"probe"},
+		{"probe", "-broker", "localhost:9098"},
+		{"probe", "-broker", "localhost:9098", "-region", "us-west-2", "-profile", "p", "-role-arn", "r"},
+		{"probe", "-broker", "localhost:9098", "-region", "us-west-2", "-proxy", "://proxy"},
+	} {
+		status, _, stderr := runCommand(args...)
+
+		assert.Equal(t, ExitUsage, status, args)
+		assert.NotEmpty(t, stderr, args)
+	}
+}
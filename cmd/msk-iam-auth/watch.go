@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+)
+
+// Time to wait before retrying after a failed refresh in watch mode.
+var watchRetryInterval = 10 * time.Second
+
+// Runs watch mode until the context is done, serving metrics on metricsAddr when set.
+func runWatch(
+	ctx context.Context, generate generateFunc, write func(string, int64) error, metricsAddr string, stderr io.Writer,
+) int {
+	metrics := newWatchMetrics()
+
+	if metricsAddr != "" {
+		listener, err := net.Listen("tcp", metricsAddr)
+		if err != nil {
+			fmt.Fprintf(stderr, "msk-iam-auth: failed to serve metrics: %v\n", err)
+			return ExitError
+		}
+		mux := http.NewServeMux()
+		mux.Handle(metricsPath, metrics)
+		srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() { _ = srv.Serve(listener) }()
+		defer srv.Close()
+	}
+
+	if err := watch(ctx, generate, write, metrics, stderr); err != nil {
+		return reportError(stderr, err)
+	}
+	return ExitOK
+}
+
+// Writes a new token each time the previous one is due for refresh, retrying failed refreshes, until the context is
+// done. Failures are written to stderr and counted; an error is only returned when writing a token fails.
+func watch(
+	ctx context.Context, generate generateFunc, write func(string, int64) error, metrics *watchMetrics,
+	stderr io.Writer,
+) error {
+	strategy := signer.FractionRefreshStrategy{Fraction: signer.DefaultRefreshFraction}
+
+	for {
+		issuedAt := time.Now()
+		wait := watchRetryInterval
+
+		value, expirationTimeMs, err := generate(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			metrics.observeFailure()
+			fmt.Fprintf(stderr, "msk-iam-auth: failed to refresh token, retrying in %s: %v\n", wait, err)
+		default:
+			if err := write(value, expirationTimeMs); err != nil {
+				return fmt.Errorf("failed to write token: %w", err)
+			}
+			token := &signer.Token{Value: value, ExpirationTimeMs: expirationTimeMs}
+			metrics.observeToken(issuedAt, token.Expiry(0))
+			wait = time.Until(strategy.RefreshAt(issuedAt, token))
+		}
+
+		timer := time.NewTimer(max(wait, 0))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
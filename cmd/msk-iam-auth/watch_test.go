@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchRetriesAndWritesTokens(t *testing.T) {
+	defer func(interval time.Duration) { watchRetryInterval = interval }(watchRetryInterval)
+	watchRetryInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	generate := func(ctx context.Context) (string, int64, error) {
+		calls++
+		if calls == 1 {
+			return "", 0, errors.New("throttled")
+		}
+		return "token", time.Now().Add(5 * time.Millisecond).UnixMilli(), nil
+	}
+	var written []string
+	write := func(token string, _ int64) error {
+		written = append(written, token)
+		if len(written) == 2 {
+			cancel()
+		}
+		return nil
+	}
+	metrics := newWatchMetrics()
+	var stderr bytes.Buffer
+
+	assert.NoError(t, watch(ctx, generate, write, metrics, &stderr))
+
+	assert.Equal(t, []string{"token", "token"}, written)
+	assert.Equal(t, uint64(2), metrics.refreshes)
+	assert.Equal(t, uint64(1), metrics.failures)
+	assert.Contains(t, stderr.String(), "throttled")
+}
+
+func TestWatchFailsWhenWriteFails(t *testing.T) {
+	generate := func(ctx context.Context) (string, int64, error) {
+		return "token", time.Now().Add(time.Hour).UnixMilli(), nil
+	}
+	write := func(string, int64) error { return errors.New("broken pipe") }
+
+	err := watch(context.Background(), generate, write, newWatchMetrics(), &bytes.Buffer{})
+
+	assert.ErrorContains(t, err, "broken pipe")
+}
+
+func TestRunRejectsMetricsWithoutWatch(t *testing.T) {
+	setTestConfig(t)
+
+	status, _, stderr := runCommand("-region", "us-west-2", "-metrics-addr", "127.0.0.1:0")
+
+	assert.Equal(t, ExitUsage, status)
+	assert.Contains(t, stderr, "-metrics-addr requires -watch")
+}
@@ -0,0 +1,14 @@
+// Package appconfig watches an AWS AppConfig configuration profile holding the signer configuration, so platform
+// teams can rotate identities or migrate clusters across a fleet centrally without restarting consumers.
+//
+// The configuration profile holds the JSON encoding of signer.SignerConfig:
+//
+//	{"region": "us-west-2", "roleArn": "arn:aws:iam::123456789012:role/kafka-client", "expiry": "10m"}
+//
+// A Source polls AppConfig in the background and every token generated through it uses the latest deployed
+// configuration:
+//
+//	source, err := appconfig.NewSource(ctx, appconfigdata.NewFromConfig(cfg), "kafka", "prod", "msk-auth")
+//	defer source.Close()
+//	token, expiryMs, err := signer.GenerateAuthTokenFromConfigSource(ctx, source)
+package appconfig
@@ -0,0 +1,199 @@
+package appconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+)
+
+const (
+	// DefaultPollInterval is how often AppConfig is polled unless it asks for a longer interval.
+	DefaultPollInterval = time.Minute
+
+	// MinRequiredPollInterval is the shortest minimum poll interval AppConfig accepts for a configuration session.
+	MinRequiredPollInterval = 15 * time.Second
+)
+
+// APIClient is the AppConfig data client used to poll the configuration.
+type APIClient interface {
+	StartConfigurationSession(ctx context.Context, params *appconfigdata.StartConfigurationSessionInput,
+		optFns ...func(*appconfigdata.Options)) (*appconfigdata.StartConfigurationSessionOutput, error)
+	GetLatestConfiguration(ctx context.Context, params *appconfigdata.GetLatestConfigurationInput,
+		optFns ...func(*appconfigdata.Options)) (*appconfigdata.GetLatestConfigurationOutput, error)
+}
+
+// Options configures the Source.
+type Options struct {
+	// PollInterval is the minimum interval between polls. DefaultPollInterval is used when zero. AppConfig may ask
+	// for a longer interval, which is then honored. AppConfig is asked for at least MinRequiredPollInterval, the
+	// shortest interval it accepts.
+	PollInterval time.Duration
+
+	// OnPollError is called when a background poll fails. The previous configuration is kept in that case.
+	OnPollError func(err error)
+
+	// OnChange is called with the new configuration whenever a different configuration is deployed.
+	OnChange func(cfg signer.SignerConfig)
+}
+
+// Source is a signer.ConfigSource backed by an AppConfig configuration profile.
+type Source struct {
+	client      APIClient
+	application string
+	environment string
+	profile     string
+	options     Options
+
+	mu     sync.RWMutex
+	config signer.SignerConfig
+
+	// Only accessed by the polling goroutine after construction.
+	token        *string
+	nextInterval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+var _ signer.ConfigSource = (*Source)(nil)
+
+// NewSource starts a configuration session for the application, environment and configuration profile, loads the
+// current configuration and polls for new deployments in the background until Close is called.
+func NewSource(
+	ctx context.Context, client APIClient, application string, environment string, profile string,
+	optFns ...func(*Options),
+) (*Source, error) {
+	options := Options{PollInterval: DefaultPollInterval}
+	for _, fn := range optFns {
+		fn(&options)
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+
+	s := &Source{
+		client:      client,
+		application: application,
+		environment: environment,
+		profile:     profile,
+		options:     options,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	changed, err := s.poll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, fmt.Errorf("appconfig returned no configuration for profile %s", profile)
+	}
+
+	go s.pollPeriodically()
+	return s, nil
+}
+
+// Config returns the most recently deployed signer configuration.
+func (s *Source) Config(ctx context.Context) (signer.SignerConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config, nil
+}
+
+// Close stops polling and waits for an in-flight poll to finish.
+func (s *Source) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// Polls for the latest configuration, starting a new session when there is no valid poll token. Reports whether a new
+// configuration was applied.
+func (s *Source) poll(ctx context.Context) (bool, error) {
+	if s.token == nil {
+		session, err := s.client.StartConfigurationSession(ctx, &appconfigdata.StartConfigurationSessionInput{
+			ApplicationIdentifier:                aws.String(s.application),
+			EnvironmentIdentifier:                aws.String(s.environment),
+			ConfigurationProfileIdentifier:       aws.String(s.profile),
+			RequiredMinimumPollIntervalInSeconds: aws.Int32(requiredPollIntervalSeconds(s.options.PollInterval)),
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to start appconfig configuration session: %w", err)
+		}
+		s.token = session.InitialConfigurationToken
+	}
+
+	output, err := s.client.GetLatestConfiguration(ctx, &appconfigdata.GetLatestConfigurationInput{
+		ConfigurationToken: s.token,
+	})
+	if err != nil {
+		// Poll tokens can only be used once, start over with a new session.
+		s.token = nil
+		return false, fmt.Errorf("failed to get latest appconfig configuration: %w", err)
+	}
+
+	s.token = output.NextPollConfigurationToken
+	s.nextInterval = time.Duration(output.NextPollIntervalInSeconds) * time.Second
+
+	// An empty configuration means it did not change since the previous poll.
+	if len(output.Configuration) == 0 {
+		return false, nil
+	}
+
+	var cfg signer.SignerConfig
+	if err := json.Unmarshal(output.Configuration, &cfg); err != nil {
+		return false, fmt.Errorf("failed to decode appconfig configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return false, fmt.Errorf("invalid appconfig configuration: %w", err)
+	}
+
+	s.mu.Lock()
+	s.config = cfg
+	s.mu.Unlock()
+	return true, nil
+}
+
+// Returns the minimum poll interval of a configuration session in seconds, at least the one AppConfig accepts.
+func requiredPollIntervalSeconds(interval time.Duration) int32 {
+	return int32(max(interval, MinRequiredPollInterval) / time.Second)
+}
+
+// Polls after the interval requested by AppConfig, or the configured one if longer, until the source is closed.
+func (s *Source) pollPeriodically() {
+	defer close(s.done)
+
+	for {
+		interval := s.options.PollInterval
+		if s.nextInterval > interval {
+			interval = s.nextInterval
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		changed, err := s.poll(ctx)
+		cancel()
+		if err != nil && s.options.OnPollError != nil {
+			s.options.OnPollError(err)
+		}
+		if changed && s.options.OnChange != nil {
+			cfg, _ := s.Config(ctx)
+			s.options.OnChange(cfg)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package appconfig
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appconfigdata"
+	"github.com/stretchr/testify/assert"
+)
+
+// Serves the deployed configuration once per change and checks that poll tokens are used only once.
+type mockAppConfigClient struct {
+	mu         sync.Mutex
+	deployed   string
+	served     string
+	tokens     int
+	usedTokens map[string]bool
+	sessions   int
+	intervals  []int32
+	err        error
+}
+
+func (m *mockAppConfigClient) StartConfigurationSession(ctx context.Context,
+	params *appconfigdata.StartConfigurationSessionInput,
+	optFns ...func(*appconfigdata.Options)) (*appconfigdata.StartConfigurationSessionOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions++
+	m.intervals = append(m.intervals, aws.ToInt32(params.RequiredMinimumPollIntervalInSeconds))
+	m.served = ""
+	return &appconfigdata.StartConfigurationSessionOutput{InitialConfigurationToken: m.nextToken()}, nil
+}
+
+func (m *mockAppConfigClient) GetLatestConfiguration(ctx context.Context,
+	params *appconfigdata.GetLatestConfigurationInput,
+	optFns ...func(*appconfigdata.Options)) (*appconfigdata.GetLatestConfigurationOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.usedTokens[*params.ConfigurationToken] {
+		return nil, errors.New("poll token already used")
+	}
+	m.usedTokens[*params.ConfigurationToken] = true
+
+	output := &appconfigdata.GetLatestConfigurationOutput{NextPollConfigurationToken: m.nextToken()}
+	if m.deployed != m.served {
+		output.Configuration = []byte(m.deployed)
+		m.served = m.deployed
+	}
+	return output, nil
+}
+
+func (m *mockAppConfigClient) nextToken() *string {
+	m.tokens++
+	return aws.String(strconv.Itoa(m.tokens))
+}
+
+func (m *mockAppConfigClient) deploy(cfg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deployed = cfg
+}
+
+func newMockAppConfigClient(cfg string) *mockAppConfigClient {
+	return &mockAppConfigClient{deployed: cfg, usedTokens: map[string]bool{}}
+}
+
+func TestSourceLoadsAndHotSwapsConfig(t *testing.T) {
+	client := newMockAppConfigClient(`{"region": "us-west-2", "roleArn": "arn:aws:iam::123456789012:role/a"}`)
+	changes := make(chan signer.SignerConfig, 10)
+
+	source, err := NewSource(context.Background(), client, "kafka", "prod", "msk-auth", func(o *Options) {
+		o.PollInterval = 10 * time.Millisecond
+		o.OnChange = func(cfg signer.SignerConfig) { changes <- cfg }
+	})
+	assert.NoError(t, err)
+	defer source.Close()
+
+	cfg, err := source.Config(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", cfg.Region)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/a", cfg.RoleARN)
+
+	client.deploy(`{"region": "eu-west-1", "roleArn": "arn:aws:iam::123456789012:role/b", "expiry": 600}`)
+
+	changed := <-changes
+	assert.Equal(t, "eu-west-1", changed.Region)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/b", changed.RoleARN)
+	assert.Equal(t, 10*time.Minute, changed.Expiry)
+
+	cfg, err = source.Config(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, changed, cfg)
+}
+
+func TestSourceRestartsSessionAfterPollError(t *testing.T) {
+	client := newMockAppConfigClient(`{"region": "us-west-2"}`)
+	pollErrors := make(chan error, 10)
+
+	source, err := NewSource(context.Background(), client, "kafka", "prod", "msk-auth", func(o *Options) {
+		o.PollInterval = 10 * time.Millisecond
+		o.OnPollError = func(err error) { pollErrors <- err }
+	})
+	assert.NoError(t, err)
+	defer source.Close()
+
+	client.mu.Lock()
+	client.err = errors.New("throttled")
+	client.mu.Unlock()
+	assert.Error(t, <-pollErrors)
+
+	client.mu.Lock()
+	client.err = nil
+	client.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return client.sessions > 1
+	}, time.Second, 5*time.Millisecond)
+
+	cfg, err := source.Config(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", cfg.Region)
+}
+
+func TestNewSourceFailsOnInvalidConfig(t *testing.T) {
+	for name, cfg := range map[string]string{
+		"empty":          ``,
+		"malformed":      `{"region": `,
+		"missing region": `{"roleArn": "arn:aws:iam::123456789012:role/a"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSource(context.Background(), newMockAppConfigClient(cfg), "kafka", "prod", "msk-auth")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSourceRequiresAcceptedPollInterval(t *testing.T) {
+	for _, tc := range []struct {
+		pollInterval time.Duration
+		want         int32
+	}{
+		{10 * time.Millisecond, 15},
+		{15 * time.Second, 15},
+		{0, 60},
+		{5 * time.Minute, 300},
+	} {
+		client := newMockAppConfigClient(`{"region": "us-west-2"}`)
+		source, err := NewSource(context.Background(), client, "kafka", "prod", "msk-auth", func(o *Options) {
+			o.PollInterval = tc.pollInterval
+		})
+		assert.NoError(t, err)
+		source.Close()
+
+		assert.Equal(t, []int32{tc.want}, client.intervals, "poll interval %s", tc.pollInterval)
+	}
+}
@@ -0,0 +1,18 @@
+// Package confluent authenticates confluent-kafka-go clients to MSK with IAM auth tokens over SASL/OAUTHBEARER.
+//
+// Configure the client with sasl.mechanism OAUTHBEARER and answer the OAuthBearerTokenRefresh events librdkafka
+// raises with HandleTokenRefresh:
+//
+//	provider := signer.NewProvider("us-west-2", nil)
+//	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+//		"bootstrap.servers": brokers,
+//		"security.protocol": "SASL_SSL",
+//		"sasl.mechanisms":   "OAUTHBEARER",
+//		"group.id":          "my-group",
+//	})
+//	for event := range consumer.Events() {
+//		if _, ok := event.(kafka.OAuthBearerTokenRefresh); ok {
+//			confluent.HandleTokenRefresh(ctx, consumer, provider)
+//		}
+//	}
+package confluent
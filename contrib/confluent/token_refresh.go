@@ -0,0 +1,31 @@
+package confluent
+
+import (
+	"context"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// TokenSetter receives OAUTHBEARER tokens. The confluent-kafka-go Producer, Consumer and AdminClient implement it.
+type TokenSetter interface {
+	SetOAuthBearerToken(oauthBearerToken kafka.OAuthBearerToken) error
+	SetOAuthBearerTokenFailure(errstr string) error
+}
+
+// HandleTokenRefresh hands the current auth token of provider to the client. When no token can be generated the
+// failure is reported to the client, which raises another refresh event later, and the error is returned.
+func HandleTokenRefresh(ctx context.Context, client TokenSetter, provider signer.TokenProvider) error {
+	token, err := provider.Token(ctx)
+	if err != nil {
+		if failureErr := client.SetOAuthBearerTokenFailure(err.Error()); failureErr != nil {
+			return failureErr
+		}
+		return err
+	}
+
+	return client.SetOAuthBearerToken(kafka.OAuthBearerToken{
+		TokenValue: token.Value,
+		Expiration: token.Expiry(0),
+	})
+}
@@ -0,0 +1,56 @@
+package confluent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+// Records the tokens and failures handed to the client.
+type recordingTokenSetter struct {
+	token   kafka.OAuthBearerToken
+	failure string
+}
+
+func (r *recordingTokenSetter) SetOAuthBearerToken(oauthBearerToken kafka.OAuthBearerToken) error {
+	r.token = oauthBearerToken
+	return nil
+}
+
+func (r *recordingTokenSetter) SetOAuthBearerTokenFailure(errstr string) error {
+	r.failure = errstr
+	return nil
+}
+
+// Token provider failing every call.
+type failingTokenProvider struct{}
+
+func (failingTokenProvider) Token(ctx context.Context) (*signer.Token, error) {
+	return nil, errors.New("no credentials")
+}
+
+func TestHandleTokenRefresh(t *testing.T) {
+	expiry := time.Now().Add(15 * time.Minute)
+	client := &recordingTokenSetter{}
+
+	err := HandleTokenRefresh(context.Background(), client, signer.StaticTokenProvider("TEST-CONFLUENT-TOKEN", expiry))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-CONFLUENT-TOKEN", client.token.TokenValue)
+	assert.Equal(t, expiry.UnixMilli(), client.token.Expiration.UnixMilli())
+	assert.Empty(t, client.failure)
+}
+
+func TestHandleTokenRefreshFailure(t *testing.T) {
+	client := &recordingTokenSetter{}
+
+	err := HandleTokenRefresh(context.Background(), client, failingTokenProvider{})
+
+	assert.EqualError(t, err, "no credentials")
+	assert.Equal(t, "no credentials", client.failure)
+}
@@ -0,0 +1,11 @@
+// Package franzgo authenticates twmb/franz-go Kafka clients to MSK with IAM auth tokens over SASL/OAUTHBEARER.
+//
+// Mechanism returns the SASL mechanism presenting the tokens of a signer.TokenProvider, such as a caching
+// signer.Provider:
+//
+//	client, err := kgo.NewClient(
+//		kgo.SeedBrokers(brokers...),
+//		kgo.DialTLSConfig(new(tls.Config)),
+//		kgo.SASL(franzgo.Mechanism(signer.NewProvider("us-west-2", nil))),
+//	)
+package franzgo
@@ -0,0 +1,21 @@
+package franzgo
+
+import (
+	"context"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/oauth"
+)
+
+// Mechanism returns the SASL/OAUTHBEARER mechanism authenticating with the tokens of provider. A token is requested on
+// every connection, so provider should cache them.
+func Mechanism(provider signer.TokenProvider) sasl.Mechanism {
+	return oauth.Oauth(func(ctx context.Context) (oauth.Auth, error) {
+		token, err := provider.Token(ctx)
+		if err != nil {
+			return oauth.Auth{}, err
+		}
+		return oauth.Auth{Token: token.Value}, nil
+	})
+}
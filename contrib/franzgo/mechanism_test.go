@@ -0,0 +1,38 @@
+package franzgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Token provider failing every call.
+type failingTokenProvider struct{}
+
+func (failingTokenProvider) Token(ctx context.Context) (*signer.Token, error) {
+	return nil, errors.New("no credentials")
+}
+
+func TestMechanism(t *testing.T) {
+	mechanism := Mechanism(signer.StaticTokenProvider("TEST-FRANZ-TOKEN", time.Now().Add(time.Hour)))
+
+	session, clientFirstMessage, err := mechanism.Authenticate(context.Background(), "broker:9098")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "OAUTHBEARER", mechanism.Name())
+	assert.Equal(t, "n,,\x01auth=Bearer TEST-FRANZ-TOKEN\x01\x01", string(clientFirstMessage))
+
+	done, _, err := session.Challenge(nil)
+	assert.NoError(t, err)
+	assert.True(t, done)
+}
+
+func TestMechanismError(t *testing.T) {
+	_, _, err := Mechanism(failingTokenProvider{}).Authenticate(context.Background(), "broker:9098")
+
+	assert.EqualError(t, err, "no credentials")
+}
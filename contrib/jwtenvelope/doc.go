@@ -0,0 +1,14 @@
+// Package jwtenvelope carries MSK IAM auth tokens inside signed JWTs, for internal gateways that expect the token
+// wrapped with metadata claims rather than raw.
+//
+// The auth token is carried in the msk_token claim with its region in msk_region, the JWT expires with the token, and
+// the issuer, subject, audience and extra claims of the options are added:
+//
+//	envelope := jwtenvelope.NewHMAC(secret, func(o *jwtenvelope.Options) {
+//		o.Issuer = "orders-service"
+//		o.Audience = []string{"kafka-gateway"}
+//	})
+//	token, expiryMs, err := signer.GenerateAuthToken(ctx, "us-west-2", signer.WithTokenEnvelope(envelope))
+//
+// The gateway verifies the JWT and recovers the raw token with Unwrap, using the same envelope configuration.
+package jwtenvelope
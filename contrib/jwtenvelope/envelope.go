@@ -0,0 +1,150 @@
+package jwtenvelope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClaimToken  = "msk_token"  // ClaimToken is the claim carrying the raw auth token.
+	ClaimRegion = "msk_region" // ClaimRegion is the claim carrying the region the auth token was signed for.
+)
+
+// ErrNoVerificationKey is returned by Unwrap when the envelope has no key to verify JWTs with.
+var ErrNoVerificationKey = errors.New("jwt envelope has no verification key")
+
+// Options configures the Envelope.
+type Options struct {
+	// Issuer is the iss claim of the JWTs. Unwrap requires it to match when set.
+	Issuer string
+
+	// Subject is the sub claim of the JWTs.
+	Subject string
+
+	// Audience is the aud claim of the JWTs. Unwrap requires one of them when set.
+	Audience []string
+
+	// Claims are extra metadata claims added to the JWTs. They cannot replace the registered claims or the claims
+	// carrying the auth token.
+	Claims map[string]any
+
+	// Leeway is the clock skew tolerated when Unwrap validates the time claims.
+	Leeway time.Duration
+}
+
+// Envelope wraps auth tokens in JWTs signed with a key and unwraps JWTs verified with a key. It is safe for
+// concurrent use.
+type Envelope struct {
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	options   Options
+}
+
+var _ signer.TokenEnvelope = (*Envelope)(nil)
+
+// New returns an envelope signing JWTs with the signing method and key, e.g. jwt.SigningMethodES256 and an
+// *ecdsa.PrivateKey, and verifying them with verifyKey, e.g. the matching *ecdsa.PublicKey. verifyKey may be nil for
+// envelopes that only wrap tokens, and signKey for envelopes that only unwrap them.
+func New(method jwt.SigningMethod, signKey any, verifyKey any, optFns ...func(*Options)) *Envelope {
+	var options Options
+	for _, fn := range optFns {
+		fn(&options)
+	}
+	return &Envelope{method: method, signKey: signKey, verifyKey: verifyKey, options: options}
+}
+
+// NewHMAC returns an envelope signing and verifying JWTs with HMAC-SHA256 and the shared secret.
+func NewHMAC(secret []byte, optFns ...func(*Options)) *Envelope {
+	return New(jwt.SigningMethodHS256, secret, secret, optFns...)
+}
+
+// Wrap returns a JWT carrying the auth token, expiring with it.
+func (e *Envelope) Wrap(ctx context.Context, token *signer.Token) (string, error) {
+	if e.signKey == nil {
+		return "", errors.New("jwt envelope has no signing key")
+	}
+
+	claims := jwt.MapClaims{}
+	for name, value := range e.options.Claims {
+		claims[name] = value
+	}
+	claims["iat"] = jwt.NewNumericDate(time.Now())
+	claims["exp"] = jwt.NewNumericDate(token.Expiry(0))
+	if e.options.Issuer != "" {
+		claims["iss"] = e.options.Issuer
+	}
+	if e.options.Subject != "" {
+		claims["sub"] = e.options.Subject
+	}
+	if len(e.options.Audience) > 0 {
+		claims["aud"] = jwt.ClaimStrings(e.options.Audience)
+	}
+	claims[ClaimToken] = token.Value
+	claims[ClaimRegion] = token.Region
+
+	signed, err := jwt.NewWithClaims(e.method, claims).SignedString(e.signKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign jwt: %w", err)
+	}
+	return signed, nil
+}
+
+// Unwrap verifies the signature, expiry, issuer and audience of the JWT and returns the auth token it carries.
+func (e *Envelope) Unwrap(ctx context.Context, envelope string) (string, error) {
+	if e.verifyKey == nil {
+		return "", ErrNoVerificationKey
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{e.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(e.options.Leeway),
+	}
+	if e.options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(e.options.Issuer))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(envelope, claims, func(*jwt.Token) (any, error) {
+		return e.verifyKey, nil
+	}, parserOptions...)
+	if err != nil {
+		return "", fmt.Errorf("invalid jwt envelope: %w", err)
+	}
+	if err := e.verifyAudience(claims); err != nil {
+		return "", err
+	}
+
+	token, ok := claims[ClaimToken].(string)
+	if !ok || token == "" {
+		return "", fmt.Errorf("invalid jwt envelope: no %s claim", ClaimToken)
+	}
+	return token, nil
+}
+
+// Checks that the JWT is meant for one of the audiences of the options, if any.
+func (e *Envelope) verifyAudience(claims jwt.MapClaims) error {
+	if len(e.options.Audience) == 0 {
+		return nil
+	}
+
+	audience, err := claims.GetAudience()
+	if err != nil {
+		return fmt.Errorf("invalid jwt envelope: %w", err)
+	}
+	for _, expected := range e.options.Audience {
+		for _, actual := range audience {
+			if actual == expected {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("invalid jwt envelope: audience %v is not one of %v", audience, e.options.Audience)
+}
@@ -0,0 +1,101 @@
+package jwtenvelope
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCredentials = aws.NewCredentialsCache(credentialsFunc(func(context.Context) (aws.Credentials, error) {
+	return aws.Credentials{AccessKeyID: "TEST-JWT-ACCESS-KEY", SecretAccessKey: "TEST-JWT-SECRET-KEY"}, nil
+}))
+
+// Adapts a function to aws.CredentialsProvider.
+type credentialsFunc func(ctx context.Context) (aws.Credentials, error)
+
+func (f credentialsFunc) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	return f(ctx)
+}
+
+func TestWrapAndUnwrapGeneratedToken(t *testing.T) {
+	envelope := NewHMAC([]byte("test-secret"), func(o *Options) {
+		o.Issuer = "orders-service"
+		o.Subject = "orders"
+		o.Audience = []string{"kafka-gateway"}
+		o.Claims = map[string]any{"team": "payments", ClaimToken: "overridden"}
+	})
+
+	wrapped, expiryMs, err := signer.GenerateAuthTokenFromCredentialsProvider(context.Background(), "us-west-2",
+		testCredentials, signer.WithTokenEnvelope(envelope))
+	assert.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(wrapped, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", claims["team"])
+	assert.Equal(t, "us-west-2", claims[ClaimRegion])
+	assert.Equal(t, float64(expiryMs/1000), claims["exp"])
+
+	token, err := envelope.Unwrap(context.Background(), wrapped)
+	assert.NoError(t, err)
+	params, err := signer.ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-JWT-ACCESS-KEY", params.AccessKeyID)
+}
+
+func TestUnwrapWithPublicKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	signing := New(jwt.SigningMethodES256, key, nil)
+	verifying := New(jwt.SigningMethodES256, nil, &key.PublicKey)
+	token := &signer.Token{Value: "raw-token", ExpirationTimeMs: time.Now().Add(time.Hour).UnixMilli()}
+
+	wrapped, err := signing.Wrap(context.Background(), token)
+	assert.NoError(t, err)
+	_, err = signing.Unwrap(context.Background(), wrapped)
+	assert.ErrorIs(t, err, ErrNoVerificationKey)
+	_, err = verifying.Wrap(context.Background(), token)
+	assert.ErrorContains(t, err, "no signing key")
+
+	unwrapped, err := verifying.Unwrap(context.Background(), wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, "raw-token", unwrapped)
+}
+
+func TestUnwrapRejectsInvalidEnvelopes(t *testing.T) {
+	envelope := NewHMAC([]byte("test-secret"), func(o *Options) {
+		o.Issuer = "orders-service"
+		o.Audience = []string{"kafka-gateway"}
+	})
+	valid := &signer.Token{Value: "raw-token", ExpirationTimeMs: time.Now().Add(time.Hour).UnixMilli()}
+	expired := &signer.Token{Value: "raw-token", ExpirationTimeMs: time.Now().Add(-time.Hour).UnixMilli()}
+
+	for name, test := range map[string]struct {
+		envelope *Envelope
+		token    *signer.Token
+		message  string
+	}{
+		"signature": {NewHMAC([]byte("other-secret"), func(o *Options) { *o = envelope.options }), valid,
+			"signature is invalid"},
+		"expired":  {envelope, expired, "token is expired"},
+		"issuer":   {NewHMAC([]byte("test-secret"), func(o *Options) { o.Issuer = "other" }), valid, "issuer"},
+		"audience": {NewHMAC([]byte("test-secret"), func(o *Options) { o.Issuer = "orders-service" }), valid, "audience"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			wrapped, err := test.envelope.Wrap(context.Background(), test.token)
+			assert.NoError(t, err)
+
+			_, err = envelope.Unwrap(context.Background(), wrapped)
+
+			assert.ErrorContains(t, err, test.message)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package kafkago authenticates segmentio/kafka-go clients to MSK with IAM auth tokens over SASL/OAUTHBEARER.
+//
+// Mechanism presents the tokens of a signer.TokenProvider, such as a caching signer.Provider:
+//
+//	dialer := &kafka.Dialer{
+//		TLS:           new(tls.Config),
+//		SASLMechanism: &kafkago.Mechanism{Provider: signer.NewProvider("us-west-2", nil)},
+//	}
+package kafkago
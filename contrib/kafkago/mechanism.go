@@ -0,0 +1,45 @@
+package kafkago
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/segmentio/kafka-go/sasl"
+)
+
+// MechanismName is the SASL mechanism name of MSK IAM auth tokens.
+const MechanismName = "OAUTHBEARER"
+
+// Mechanism implements the kafka-go SASL/OAUTHBEARER mechanism with the tokens of Provider. A token is requested on
+// every connection, so Provider should cache them.
+type Mechanism struct {
+	Provider signer.TokenProvider
+}
+
+var _ sasl.Mechanism = (*Mechanism)(nil)
+
+// Name returns the OAUTHBEARER mechanism name.
+func (m *Mechanism) Name() string {
+	return MechanismName
+}
+
+// Start returns the initial client response carrying the auth token, as defined by RFC 7628.
+func (m *Mechanism) Start(ctx context.Context) (sasl.StateMachine, []byte, error) {
+	token, err := m.Provider.Token(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session{}, []byte("n,,\x01auth=Bearer " + token.Value + "\x01\x01"), nil
+}
+
+// Authentication exchange after the initial client response.
+type session struct{}
+
+// Next completes the exchange when the broker accepted the token, otherwise fails with the broker error.
+func (session) Next(ctx context.Context, challenge []byte) (bool, []byte, error) {
+	if len(challenge) != 0 {
+		return false, nil, fmt.Errorf("oauthbearer authentication failed: %s", challenge)
+	}
+	return true, nil, nil
+}
@@ -0,0 +1,50 @@
+package kafkago
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Token provider failing every call.
+type failingTokenProvider struct{}
+
+func (failingTokenProvider) Token(ctx context.Context) (*signer.Token, error) {
+	return nil, errors.New("no credentials")
+}
+
+func TestMechanism(t *testing.T) {
+	mechanism := &Mechanism{Provider: signer.StaticTokenProvider("TEST-KAFKA-GO-TOKEN", time.Now().Add(time.Hour))}
+
+	session, initialResponse, err := mechanism.Start(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, MechanismName, mechanism.Name())
+	assert.Equal(t, "n,,\x01auth=Bearer TEST-KAFKA-GO-TOKEN\x01\x01", string(initialResponse))
+
+	done, response, err := session.Next(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Nil(t, response)
+}
+
+func TestMechanismBrokerRejection(t *testing.T) {
+	mechanism := &Mechanism{Provider: signer.NopProvider{}}
+	session, _, err := mechanism.Start(context.Background())
+	assert.NoError(t, err)
+
+	done, _, err := session.Next(context.Background(), []byte(`{"status":"invalid_token"}`))
+
+	assert.False(t, done)
+	assert.EqualError(t, err, `oauthbearer authentication failed: {"status":"invalid_token"}`)
+}
+
+func TestMechanismTokenError(t *testing.T) {
+	_, _, err := (&Mechanism{Provider: failingTokenProvider{}}).Start(context.Background())
+
+	assert.EqualError(t, err, "no credentials")
+}
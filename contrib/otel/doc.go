@@ -0,0 +1,8 @@
+// Package otel records the steps of MSK IAM auth token generation as OpenTelemetry spans.
+//
+// Pass a Tracer built from a tracer provider to any of the signer Generate functions, or to signer.NewProvider, to
+// see credential retrieval and signing latency in traces:
+//
+//	tracer := otel.NewTracer(otelglobal.GetTracerProvider())
+//	token, expiryMs, err := signer.GenerateAuthToken(ctx, "us-west-2", signer.WithTracer(tracer))
+package otel
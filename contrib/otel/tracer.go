@@ -0,0 +1,43 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// InstrumentationName is the name of the tracer the spans are started with.
+	InstrumentationName = "github.com/aws/aws-msk-iam-sasl-signer-go/contrib/otel"
+
+	// SpanNamePrefix is prepended to the span names reported by the signer.
+	SpanNamePrefix = "MSKIAMAuth."
+)
+
+// Tracer implements signer.Tracer by starting an OpenTelemetry span for each signer span.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ signer.Tracer = Tracer{}
+
+// NewTracer returns a Tracer starting spans with the tracer provider.
+func NewTracer(provider trace.TracerProvider) Tracer {
+	return Tracer{tracer: provider.Tracer(InstrumentationName)}
+}
+
+// StartSpan starts a span as child of the span carried by ctx. The returned function records the error, if any, and
+// ends the span.
+func (t Tracer) StartSpan(ctx context.Context, name string) (context.Context, func(err error)) {
+	ctx, span := t.tracer.Start(ctx, SpanNamePrefix+name, trace.WithSpanKind(trace.SpanKindInternal))
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,49 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Builds a tracer recording its spans in the returned recorder.
+func newRecordingTracer() (Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), recorder
+}
+
+func TestTracerRecordsTokenGeneration(t *testing.T) {
+	tracer, recorder := newRecordingTracer()
+	credentials := aws.Credentials{AccessKeyID: "TEST-OTEL-ACCESS-KEY", SecretAccessKey: "TEST-OTEL-SECRET-KEY"}
+
+	_, _, err := signer.GenerateAuthTokenFromCredentialsProvider(context.Background(), "us-west-2",
+		aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return credentials, nil
+		}), signer.WithTracer(tracer))
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, SpanNamePrefix+signer.SpanLoadCredentials, spans[0].Name())
+	assert.Equal(t, SpanNamePrefix+signer.SpanSignToken, spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestTracerRecordsErrors(t *testing.T) {
+	tracer, recorder := newRecordingTracer()
+
+	_, _, err := signer.GenerateAuthTokenFromCredentialsProvider(context.Background(), "us-west-2",
+		aws.AnonymousCredentials{}, signer.WithTracer(tracer))
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	failed := spans[len(spans)-1]
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Len(t, failed.Events(), 1)
+}
@@ -0,0 +1,50 @@
+package prometheus
+
+import (
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace prefixes the names of the exported metrics.
+const Namespace = "msk_iam_auth"
+
+var (
+	tokenTTLDesc = prom.NewDesc(Namespace+"_token_ttl_seconds",
+		"Remaining lifetime of the cached auth token, zero when none is cached.", nil, nil)
+	stsThrottlesDesc = prom.NewDesc(Namespace+"_sts_throttles_total",
+		"Number of sts call attempts rejected with a throttling error.", nil, nil)
+	stsConsecutiveThrottlesDesc = prom.NewDesc(Namespace+"_sts_consecutive_throttles",
+		"Number of sts call attempts throttled since the last successful sts call.", nil, nil)
+	stsBackoffDesc = prom.NewDesc(Namespace+"_sts_backoff_seconds",
+		"Backoff before retrying the last throttled sts call, zero once an sts call succeeded.", nil, nil)
+)
+
+// Collector implements prometheus.Collector, reading the metrics from a signer.Provider on every scrape.
+type Collector struct {
+	provider *signer.Provider
+}
+
+var _ prom.Collector = (*Collector)(nil)
+
+// NewCollector returns a Collector exporting the metrics of provider.
+func NewCollector(provider *signer.Provider) *Collector {
+	return &Collector{provider: provider}
+}
+
+// Describe sends the descriptors of the exported metrics.
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	ch <- tokenTTLDesc
+	ch <- stsThrottlesDesc
+	ch <- stsConsecutiveThrottlesDesc
+	ch <- stsBackoffDesc
+}
+
+// Collect sends the current values of the exported metrics.
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	stats := c.provider.STSThrottleStats()
+
+	ch <- prom.MustNewConstMetric(tokenTTLDesc, prom.GaugeValue, c.provider.CurrentTokenTTL().Seconds())
+	ch <- prom.MustNewConstMetric(stsThrottlesDesc, prom.CounterValue, float64(stats.Throttles))
+	ch <- prom.MustNewConstMetric(stsConsecutiveThrottlesDesc, prom.GaugeValue, float64(stats.ConsecutiveThrottles))
+	ch <- prom.MustNewConstMetric(stsBackoffDesc, prom.GaugeValue, stats.CurrentBackoff.Seconds())
+}
@@ -0,0 +1,61 @@
+package prometheus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProvider() *signer.Provider {
+	credentials := aws.Credentials{AccessKeyID: "TEST-PROM-ACCESS-KEY", SecretAccessKey: "TEST-PROM-SECRET-KEY"}
+	return signer.NewProvider("us-west-2", aws.CredentialsProviderFunc(
+		func(ctx context.Context) (aws.Credentials, error) {
+			return credentials, nil
+		}))
+}
+
+func TestCollectorBeforeFirstToken(t *testing.T) {
+	collector := NewCollector(newTestProvider())
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP msk_iam_auth_sts_backoff_seconds Backoff before retrying the last throttled sts call, zero once an sts call succeeded.
+# TYPE msk_iam_auth_sts_backoff_seconds gauge
+msk_iam_auth_sts_backoff_seconds 0
+# HELP msk_iam_auth_sts_consecutive_throttles Number of sts call attempts throttled since the last successful sts call.
+# TYPE msk_iam_auth_sts_consecutive_throttles gauge
+msk_iam_auth_sts_consecutive_throttles 0
+# HELP msk_iam_auth_sts_throttles_total Number of sts call attempts rejected with a throttling error.
+# TYPE msk_iam_auth_sts_throttles_total counter
+msk_iam_auth_sts_throttles_total 0
+# HELP msk_iam_auth_token_ttl_seconds Remaining lifetime of the cached auth token, zero when none is cached.
+# TYPE msk_iam_auth_token_ttl_seconds gauge
+msk_iam_auth_token_ttl_seconds 0
+`))
+
+	assert.NoError(t, err)
+}
+
+func TestCollectorTokenTTL(t *testing.T) {
+	provider := newTestProvider()
+	_, err := provider.Token(context.Background())
+	assert.NoError(t, err)
+
+	registry := prom.NewPedanticRegistry()
+	registry.MustRegister(NewCollector(provider))
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+
+	var ttl float64
+	for _, family := range families {
+		if family.GetName() == Namespace+"_token_ttl_seconds" {
+			ttl = family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	assert.InDelta(t, float64(signer.DefaultExpirySeconds), ttl, 5)
+}
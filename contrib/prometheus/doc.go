@@ -0,0 +1,6 @@
+// Package prometheus exports the state of a signer.Provider as Prometheus metrics: the remaining lifetime of the cached
+// auth token and the throttling of the sts calls made while retrieving credentials.
+//
+//	provider := signer.NewProvider("us-west-2", nil)
+//	prom.MustRegister(prometheus.NewCollector(provider))
+package prometheus
@@ -0,0 +1,9 @@
+// Package sarama authenticates IBM/sarama Kafka clients to MSK with IAM auth tokens over SASL/OAUTHBEARER.
+//
+// Configure sets up SASL and TLS on the sarama config with a token provider backed by a signer.TokenProvider, such as
+// a caching signer.Provider:
+//
+//	cfg := ibmsarama.NewConfig()
+//	sarama.Configure(cfg, signer.NewProvider("us-west-2", nil))
+//	client, err := ibmsarama.NewClient(brokers, cfg)
+package sarama
@@ -0,0 +1,49 @@
+package sarama
+
+import (
+	"context"
+	"time"
+
+	ibmsarama "github.com/IBM/sarama"
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+)
+
+// DefaultTimeout bounds how long sarama waits for a token when connecting to a broker.
+const DefaultTimeout = 10 * time.Second
+
+// TokenProvider implements sarama.AccessTokenProvider with the tokens of a signer.TokenProvider.
+type TokenProvider struct {
+	provider signer.TokenProvider
+	timeout  time.Duration
+}
+
+var _ ibmsarama.AccessTokenProvider = (*TokenProvider)(nil)
+
+// NewTokenProvider returns a TokenProvider handing out the tokens of provider, waiting at most timeout for each, or
+// DefaultTimeout when zero.
+func NewTokenProvider(provider signer.TokenProvider, timeout time.Duration) *TokenProvider {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &TokenProvider{provider: provider, timeout: timeout}
+}
+
+// Token returns the current auth token of the provider.
+func (p *TokenProvider) Token() (*ibmsarama.AccessToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	token, err := p.provider.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ibmsarama.AccessToken{Token: token.Value}, nil
+}
+
+// Configure enables TLS and SASL/OAUTHBEARER on the sarama config, authenticating with the tokens of provider.
+func Configure(cfg *ibmsarama.Config, provider signer.TokenProvider) {
+	cfg.Net.TLS.Enable = true
+	cfg.Net.SASL.Enable = true
+	cfg.Net.SASL.Mechanism = ibmsarama.SASLTypeOAuth
+	cfg.Net.SASL.TokenProvider = NewTokenProvider(provider, 0)
+}
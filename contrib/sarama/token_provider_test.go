@@ -0,0 +1,66 @@
+package sarama
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ibmsarama "github.com/IBM/sarama"
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Token provider failing every call.
+type failingTokenProvider struct{}
+
+func (failingTokenProvider) Token(ctx context.Context) (*signer.Token, error) {
+	return nil, errors.New("no credentials")
+}
+
+// Token provider recording the deadline of the context it is called with.
+type deadlineTokenProvider struct {
+	deadline time.Time
+}
+
+func (d *deadlineTokenProvider) Token(ctx context.Context) (*signer.Token, error) {
+	d.deadline, _ = ctx.Deadline()
+	return &signer.Token{Value: "TEST-DEADLINE-TOKEN"}, nil
+}
+
+func TestTokenProvider(t *testing.T) {
+	provider := NewTokenProvider(signer.StaticTokenProvider("TEST-SARAMA-TOKEN", time.Now().Add(time.Hour)), 0)
+
+	token, err := provider.Token()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-SARAMA-TOKEN", token.Token)
+}
+
+func TestTokenProviderTimeout(t *testing.T) {
+	recorder := &deadlineTokenProvider{}
+
+	_, err := NewTokenProvider(recorder, time.Minute).Token()
+
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), recorder.deadline, 5*time.Second)
+}
+
+func TestTokenProviderError(t *testing.T) {
+	token, err := NewTokenProvider(failingTokenProvider{}, 0).Token()
+
+	assert.EqualError(t, err, "no credentials")
+	assert.Nil(t, token)
+}
+
+func TestConfigure(t *testing.T) {
+	cfg := ibmsarama.NewConfig()
+
+	Configure(cfg, signer.NopProvider{})
+
+	assert.True(t, cfg.Net.TLS.Enable)
+	assert.True(t, cfg.Net.SASL.Enable)
+	assert.Equal(t, ibmsarama.SASLMechanism(ibmsarama.SASLTypeOAuth), cfg.Net.SASL.Mechanism)
+	assert.IsType(t, &TokenProvider{}, cfg.Net.SASL.TokenProvider)
+	assert.NoError(t, cfg.Validate())
+}
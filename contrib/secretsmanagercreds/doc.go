@@ -0,0 +1,15 @@
+// Package secretsmanagercreds provides an aws.CredentialsProvider reading the credentials used to sign MSK IAM auth
+// tokens from an AWS Secrets Manager secret.
+//
+// The secret string is a JSON object holding either an access key pair, or a role to assume with the caller supplied
+// sts client:
+//
+//	{"AccessKeyId": "AKIA...", "SecretAccessKey": "...", "SessionToken": "..."}
+//	{"RoleArn": "arn:aws:iam::123456789012:role/kafka-client", "RoleSessionName": "kafka", "ExternalId": "..."}
+//
+// Credentials are reported as expiring after the refresh interval, so wrapping the provider in an
+// aws.CredentialsCache re-reads the secret periodically and picks up rotated keys:
+//
+//	provider := aws.NewCredentialsCache(secretsmanagercreds.New(secretsmanager.NewFromConfig(cfg), "kafka/client"))
+//	token, expiryMs, err := signer.GenerateAuthTokenFromCredentialsProvider(ctx, "us-west-2", provider)
+package secretsmanagercreds
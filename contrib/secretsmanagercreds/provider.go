@@ -0,0 +1,152 @@
+package secretsmanagercreds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+const (
+	ProviderName           = "SecretsManagerProvider" // ProviderName is the source reported on retrieved credentials.
+	DefaultRefreshInterval = 5 * time.Minute          // DefaultRefreshInterval is how long credentials are cached.
+	DefaultSessionName     = "MSKSASLSecretsManager"  // DefaultSessionName is the session name of assumed roles.
+)
+
+// GetSecretValueAPIClient is the Secrets Manager client used to read the secret.
+type GetSecretValueAPIClient interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput,
+		optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// Secret is the JSON document stored in the secret string. It holds either an access key pair or a role to assume.
+type Secret struct {
+	AccessKeyID     string `json:"AccessKeyId,omitempty"`
+	SecretAccessKey string `json:"SecretAccessKey,omitempty"`
+	SessionToken    string `json:"SessionToken,omitempty"`
+	RoleARN         string `json:"RoleArn,omitempty"`
+	RoleSessionName string `json:"RoleSessionName,omitempty"`
+	ExternalID      string `json:"ExternalId,omitempty"`
+}
+
+// Options configures the Provider.
+type Options struct {
+	// RefreshInterval is how long retrieved credentials are valid before the secret is read again.
+	// DefaultRefreshInterval is used when zero.
+	RefreshInterval time.Duration
+
+	// VersionStage is the staging label of the secret version to read. AWSCURRENT is read when empty.
+	VersionStage string
+
+	// STSClient assumes the role of secrets holding a role configuration. It is required for such secrets.
+	STSClient stscreds.AssumeRoleAPIClient
+}
+
+// Provider retrieves credentials from a Secrets Manager secret.
+type Provider struct {
+	client   GetSecretValueAPIClient
+	secretID string
+	options  Options
+}
+
+// New returns a Provider reading the secret identified by secretID, its name or ARN.
+func New(client GetSecretValueAPIClient, secretID string, optFns ...func(*Options)) *Provider {
+	options := Options{RefreshInterval: DefaultRefreshInterval}
+	for _, fn := range optFns {
+		fn(&options)
+	}
+	if options.RefreshInterval <= 0 {
+		options.RefreshInterval = DefaultRefreshInterval
+	}
+
+	return &Provider{client: client, secretID: secretID, options: options}
+}
+
+// Retrieve reads the secret and returns the credentials it holds, assuming its role when it holds a role
+// configuration. The credentials expire after the refresh interval at the latest.
+func (p *Provider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	secret, err := p.readSecret(ctx)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+
+	expires := time.Now().Add(p.options.RefreshInterval)
+
+	switch {
+	case secret.AccessKeyID != "" && secret.SecretAccessKey != "":
+		return aws.Credentials{
+			AccessKeyID:     secret.AccessKeyID,
+			SecretAccessKey: secret.SecretAccessKey,
+			SessionToken:    secret.SessionToken,
+			Source:          ProviderName,
+			CanExpire:       true,
+			Expires:         expires,
+		}, nil
+
+	case secret.RoleARN != "":
+		return p.assumeRole(ctx, secret, expires)
+
+	default:
+		return aws.Credentials{}, fmt.Errorf("secret %s holds neither an access key pair nor a role arn", p.secretID)
+	}
+}
+
+// Reads and decodes the secret string.
+func (p *Provider) readSecret(ctx context.Context) (*Secret, error) {
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String(p.secretID)}
+	if p.options.VersionStage != "" {
+		input.VersionStage = aws.String(p.options.VersionStage)
+	}
+
+	output, err := p.client.GetSecretValue(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret value of %s: %w", p.secretID, err)
+	}
+
+	if output.SecretString == nil {
+		return nil, fmt.Errorf("secret %s has no secret string, binary secrets are not supported", p.secretID)
+	}
+
+	var secret Secret
+	if err := json.Unmarshal([]byte(*output.SecretString), &secret); err != nil {
+		return nil, fmt.Errorf("failed to decode secret %s: %w", p.secretID, err)
+	}
+
+	return &secret, nil
+}
+
+// Assumes the role configured in the secret, capping the credential expiry at the refresh interval.
+func (p *Provider) assumeRole(ctx context.Context, secret *Secret, expires time.Time) (aws.Credentials, error) {
+	if p.options.STSClient == nil {
+		return aws.Credentials{}, errors.New("secret holds a role arn but no sts client was configured")
+	}
+
+	sessionName := secret.RoleSessionName
+	if sessionName == "" {
+		sessionName = DefaultSessionName
+	}
+
+	creds, err := stscreds.NewAssumeRoleProvider(p.options.STSClient, secret.RoleARN,
+		func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = sessionName
+			if secret.ExternalID != "" {
+				o.ExternalID = aws.String(secret.ExternalID)
+			}
+		}).Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to assume role %s from secret %s: %w", secret.RoleARN, p.secretID, err)
+	}
+
+	creds.Source = ProviderName
+	if !creds.CanExpire || creds.Expires.After(expires) {
+		creds.CanExpire = true
+		creds.Expires = expires
+	}
+
+	return creds, nil
+}
@@ -0,0 +1,138 @@
+package secretsmanagercreds
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a fixed secret string and records the requested input.
+type mockSecretsManagerClient struct {
+	secretString *string
+	err          error
+	input        *secretsmanager.GetSecretValueInput
+}
+
+func (m *mockSecretsManagerClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput,
+	optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	m.input = params
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: m.secretString}, nil
+}
+
+// Returns fixed role credentials and records the requested input.
+type mockSTSClient struct {
+	expiration time.Time
+	input      *sts.AssumeRoleInput
+}
+
+func (m *mockSTSClient) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput,
+	optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	m.input = params
+	return &sts.AssumeRoleOutput{Credentials: &types.Credentials{
+		AccessKeyId:     aws.String("TEST-ROLE-ACCESS-KEY"),
+		SecretAccessKey: aws.String("TEST-ROLE-SECRET-KEY"),
+		SessionToken:    aws.String("TEST-ROLE-SESSION-TOKEN"),
+		Expiration:      aws.Time(m.expiration),
+	}}, nil
+}
+
+func TestRetrieveAccessKeyPair(t *testing.T) {
+	client := &mockSecretsManagerClient{secretString: aws.String(
+		`{"AccessKeyId": "TEST-ACCESS-KEY", "SecretAccessKey": "TEST-SECRET-KEY"}`)}
+
+	creds, err := New(client, "kafka/client", func(o *Options) {
+		o.RefreshInterval = time.Minute
+		o.VersionStage = "AWSPENDING"
+	}).Retrieve(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-ACCESS-KEY", creds.AccessKeyID)
+	assert.Equal(t, "TEST-SECRET-KEY", creds.SecretAccessKey)
+	assert.Equal(t, ProviderName, creds.Source)
+	assert.True(t, creds.CanExpire)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), creds.Expires, 5*time.Second)
+	assert.Equal(t, "kafka/client", *client.input.SecretId)
+	assert.Equal(t, "AWSPENDING", *client.input.VersionStage)
+}
+
+func TestRetrieveRole(t *testing.T) {
+	client := &mockSecretsManagerClient{secretString: aws.String(
+		`{"RoleArn": "arn:aws:iam::123456789012:role/kafka", "ExternalId": "test-external-id"}`)}
+	stsClient := &mockSTSClient{expiration: time.Now().Add(time.Hour)}
+
+	creds, err := New(client, "kafka/role", func(o *Options) {
+		o.STSClient = stsClient
+	}).Retrieve(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-ROLE-ACCESS-KEY", creds.AccessKeyID)
+	assert.Equal(t, "TEST-ROLE-SESSION-TOKEN", creds.SessionToken)
+	assert.Equal(t, ProviderName, creds.Source)
+	assert.WithinDuration(t, time.Now().Add(DefaultRefreshInterval), creds.Expires, 5*time.Second)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/kafka", *stsClient.input.RoleArn)
+	assert.Equal(t, DefaultSessionName, *stsClient.input.RoleSessionName)
+	assert.Equal(t, "test-external-id", *stsClient.input.ExternalId)
+}
+
+func TestRetrieveRoleWithoutSTSClient(t *testing.T) {
+	client := &mockSecretsManagerClient{secretString: aws.String(`{"RoleArn": "arn:aws:iam::123456789012:role/kafka"}`)}
+
+	_, err := New(client, "kafka/role").Retrieve(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestRetrieveInvalidSecrets(t *testing.T) {
+	for name, client := range map[string]*mockSecretsManagerClient{
+		"get secret fails": {err: errors.New("access denied")},
+		"binary secret":    {},
+		"malformed json":   {secretString: aws.String(`{"AccessKeyId": `)},
+		"empty secret":     {secretString: aws.String(`{}`)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(client, "kafka/client").Retrieve(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCredentialsCacheRereadsSecretAfterRefreshInterval(t *testing.T) {
+	client := &mockSecretsManagerClient{secretString: aws.String(
+		`{"AccessKeyId": "TEST-OLD-ACCESS-KEY", "SecretAccessKey": "TEST-SECRET-KEY"}`)}
+	cache := aws.NewCredentialsCache(New(client, "kafka/client", func(o *Options) {
+		o.RefreshInterval = time.Millisecond
+	}))
+
+	creds, err := cache.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-OLD-ACCESS-KEY", creds.AccessKeyID)
+
+	client.secretString = aws.String(`{"AccessKeyId": "TEST-NEW-ACCESS-KEY", "SecretAccessKey": "TEST-SECRET-KEY"}`)
+	time.Sleep(5 * time.Millisecond)
+
+	creds, err = cache.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-NEW-ACCESS-KEY", creds.AccessKeyID)
+}
+
+func TestRegistersSource(t *testing.T) {
+	var found bool
+	for _, source := range signer.SupportedSources() {
+		if source.Name == ProviderName {
+			found = true
+			assert.Equal(t, signer.ModulePath+"/contrib/secretsmanagercreds", source.Module)
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,11 @@
+package secretsmanagercreds
+
+import "github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+
+func init() {
+	signer.RegisterSource(signer.SourceInfo{
+		Name:        ProviderName,
+		Module:      signer.ModulePath + "/contrib/secretsmanagercreds",
+		Description: "access keys or role stored in an AWS Secrets Manager secret",
+	})
+}
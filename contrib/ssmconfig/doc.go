@@ -0,0 +1,20 @@
+// Package ssmconfig loads the signer configuration from AWS Systems Manager Parameter Store.
+//
+// The configuration is read from the parameters directly under a path, named after the SignerConfig fields:
+//
+//	/kafka/msk-auth/region           us-west-2
+//	/kafka/msk-auth/role-arn         arn:aws:iam::123456789012:role/kafka-client
+//	/kafka/msk-auth/sts-session-name kafka-client
+//	/kafka/msk-auth/cluster-arn      arn:aws:kafka:us-west-2:123456789012:cluster/demo/...
+//	/kafka/msk-auth/expiry           10m
+//	/kafka/msk-auth/max-expiry       15m
+//
+// A Source loads the configuration when constructed and can reload it periodically, so that fleets managed through
+// Parameter Store pick up changes without a restart:
+//
+//	source, err := ssmconfig.NewSource(ctx, ssm.NewFromConfig(cfg), "/kafka/msk-auth", func(o *ssmconfig.Options) {
+//		o.ReloadInterval = 5 * time.Minute
+//	})
+//	defer source.Close()
+//	token, expiryMs, err := signer.GenerateAuthTokenFromConfigSource(ctx, source)
+package ssmconfig
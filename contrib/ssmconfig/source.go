@@ -0,0 +1,202 @@
+package ssmconfig
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+const (
+	ParameterRegion         = "region"           // ParameterRegion names the parameter holding the region.
+	ParameterRoleARN        = "role-arn"         // ParameterRoleARN names the parameter holding the role arn.
+	ParameterSTSSessionName = "sts-session-name" // ParameterSTSSessionName names the parameter holding the session name.
+	ParameterClusterARN     = "cluster-arn"      // ParameterClusterARN names the parameter holding the cluster arn.
+	ParameterExpiry         = "expiry"           // ParameterExpiry names the parameter holding the token expiry.
+	ParameterMaxExpiry      = "max-expiry"       // ParameterMaxExpiry names the parameter holding the max token expiry.
+)
+
+// Load reads the signer configuration from the parameters directly under path. Parameters that are not recognized are
+// ignored. The expiry and max expiry are either a Go duration such as "10m" or a number of seconds.
+func Load(ctx context.Context, client ssm.GetParametersByPathAPIClient, path string) (signer.SignerConfig, error) {
+	var cfg signer.SignerConfig
+	prefix := strings.TrimSuffix(path, "/") + "/"
+
+	paginator := ssm.NewGetParametersByPathPaginator(client, &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return signer.SignerConfig{}, fmt.Errorf("failed to get parameters under %s: %w", path, err)
+		}
+
+		for _, parameter := range page.Parameters {
+			name := strings.TrimPrefix(aws.ToString(parameter.Name), prefix)
+			if err := setParameter(&cfg, name, aws.ToString(parameter.Value)); err != nil {
+				return signer.SignerConfig{}, fmt.Errorf("invalid parameter %s: %w", aws.ToString(parameter.Name), err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return signer.SignerConfig{}, fmt.Errorf("invalid signer config under %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Sets the signer config field matching the parameter name.
+func setParameter(cfg *signer.SignerConfig, name string, value string) error {
+	switch name {
+	case ParameterRegion:
+		cfg.Region = value
+	case ParameterRoleARN:
+		cfg.RoleARN = value
+	case ParameterSTSSessionName:
+		cfg.STSSessionName = value
+	case ParameterClusterARN:
+		cfg.ClusterARN = value
+	case ParameterExpiry:
+		expiry, err := parseExpiry(value)
+		if err != nil {
+			return err
+		}
+		cfg.Expiry = expiry
+	case ParameterMaxExpiry:
+		maxExpiry, err := parseExpiry(value)
+		if err != nil {
+			return err
+		}
+		cfg.MaxExpiry = maxExpiry
+	}
+	return nil
+}
+
+// Parses an expiry given as a Go duration or as a number of seconds.
+func parseExpiry(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	expiry, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("expiry must be a duration or a number of seconds: %w", err)
+	}
+	return expiry, nil
+}
+
+// Options configures the Source.
+type Options struct {
+	// ReloadInterval is how often the configuration is reloaded in the background. It is never reloaded when zero.
+	ReloadInterval time.Duration
+
+	// OnReloadError is called when a background reload fails. The previous configuration is kept in that case.
+	OnReloadError func(err error)
+}
+
+// Source is a signer.ConfigSource backed by SSM Parameter Store.
+type Source struct {
+	client  ssm.GetParametersByPathAPIClient
+	path    string
+	options Options
+
+	mu     sync.RWMutex
+	config signer.SignerConfig
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+var _ signer.ConfigSource = (*Source)(nil)
+
+// NewSource loads the signer configuration under path and, when a reload interval is configured, starts reloading it
+// in the background until Close is called.
+func NewSource(
+	ctx context.Context, client ssm.GetParametersByPathAPIClient, path string, optFns ...func(*Options),
+) (*Source, error) {
+	var options Options
+	for _, fn := range optFns {
+		fn(&options)
+	}
+
+	cfg, err := Load(ctx, client, path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Source{
+		client:  client,
+		path:    path,
+		options: options,
+		config:  cfg,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if options.ReloadInterval > 0 {
+		go s.reloadPeriodically()
+	} else {
+		close(s.done)
+	}
+
+	return s, nil
+}
+
+// Config returns the most recently loaded signer configuration.
+func (s *Source) Config(ctx context.Context) (signer.SignerConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config, nil
+}
+
+// Reload loads the signer configuration again, keeping the previous one when loading fails.
+func (s *Source) Reload(ctx context.Context) error {
+	cfg, err := Load(ctx, s.client, s.path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.config = cfg
+	s.mu.Unlock()
+	return nil
+}
+
+// Close stops the background reload and waits for an in-flight reload to finish.
+func (s *Source) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+}
+
+// Reloads the configuration on every tick of the reload interval until the source is closed.
+func (s *Source) reloadPeriodically() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.options.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), s.options.ReloadInterval)
+			err := s.Reload(ctx)
+			cancel()
+			if err != nil && s.options.OnReloadError != nil {
+				s.options.OnReloadError(err)
+			}
+		}
+	}
+}
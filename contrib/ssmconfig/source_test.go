@@ -0,0 +1,174 @@
+package ssmconfig
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPath = "/kafka/msk-auth"
+
+// Serves the parameters in pages of one and records the requested inputs.
+type mockSSMClient struct {
+	mu         sync.Mutex
+	parameters map[string]string
+	err        error
+	calls      int
+}
+
+func (m *mockSSMClient) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput,
+	optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var names []string
+	for name := range m.parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return &ssm.GetParametersByPathOutput{}, nil
+	}
+
+	var index int
+	if params.NextToken != nil {
+		for i, name := range names {
+			if name == *params.NextToken {
+				index = i
+			}
+		}
+	}
+
+	output := &ssm.GetParametersByPathOutput{Parameters: []types.Parameter{{
+		Name:  aws.String(*params.Path + "/" + names[index]),
+		Value: aws.String(m.parameters[names[index]]),
+	}}}
+	if index+1 < len(names) {
+		output.NextToken = aws.String(names[index+1])
+	}
+	return output, nil
+}
+
+func (m *mockSSMClient) set(name string, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.parameters[name] = value
+}
+
+func TestLoad(t *testing.T) {
+	client := &mockSSMClient{parameters: map[string]string{
+		ParameterRegion:         "us-west-2",
+		ParameterRoleARN:        "arn:aws:iam::123456789012:role/kafka",
+		ParameterSTSSessionName: "kafka-client",
+		ParameterClusterARN:     "arn:aws:kafka:us-west-2:123456789012:cluster/demo/abc",
+		ParameterExpiry:         "10m",
+		ParameterMaxExpiry:      "12m",
+		"unrelated":             "ignored",
+	}}
+
+	cfg, err := Load(context.Background(), client, testPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", cfg.Region)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/kafka", cfg.RoleARN)
+	assert.Equal(t, "kafka-client", cfg.STSSessionName)
+	assert.Equal(t, "arn:aws:kafka:us-west-2:123456789012:cluster/demo/abc", cfg.ClusterARN)
+	assert.Equal(t, 10*time.Minute, cfg.Expiry)
+	assert.Equal(t, 12*time.Minute, cfg.MaxExpiry)
+}
+
+func TestLoadExpiryInSeconds(t *testing.T) {
+	client := &mockSSMClient{parameters: map[string]string{ParameterRegion: "us-west-2", ParameterExpiry: "300"}}
+
+	cfg, err := Load(context.Background(), client, testPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, cfg.Expiry)
+}
+
+func TestLoadInvalidConfig(t *testing.T) {
+	for name, client := range map[string]*mockSSMClient{
+		"request fails":   {err: errors.New("access denied")},
+		"missing region":  {parameters: map[string]string{ParameterRoleARN: "arn:aws:iam::123456789012:role/kafka"}},
+		"invalid expiry":  {parameters: map[string]string{ParameterRegion: "us-west-2", ParameterExpiry: "soon"}},
+		"negative expiry": {parameters: map[string]string{ParameterRegion: "us-west-2", ParameterExpiry: "-5"}},
+		"expiry over max": {parameters: map[string]string{
+			ParameterRegion: "us-west-2", ParameterExpiry: "10m", ParameterMaxExpiry: "5m",
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(context.Background(), client, testPath)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSourceReloadsPeriodically(t *testing.T) {
+	client := &mockSSMClient{parameters: map[string]string{ParameterRegion: "us-west-2"}}
+
+	source, err := NewSource(context.Background(), client, testPath, func(o *Options) {
+		o.ReloadInterval = 10 * time.Millisecond
+	})
+	assert.NoError(t, err)
+	defer source.Close()
+
+	cfg, err := source.Config(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", cfg.Region)
+
+	client.set(ParameterRegion, "eu-west-1")
+	assert.Eventually(t, func() bool {
+		cfg, _ := source.Config(context.Background())
+		return cfg.Region == "eu-west-1"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSourceKeepsConfigOnReloadError(t *testing.T) {
+	client := &mockSSMClient{parameters: map[string]string{ParameterRegion: "us-west-2"}}
+	reloadErrors := make(chan error, 10)
+
+	source, err := NewSource(context.Background(), client, testPath, func(o *Options) {
+		o.ReloadInterval = 10 * time.Millisecond
+		o.OnReloadError = func(err error) { reloadErrors <- err }
+	})
+	assert.NoError(t, err)
+
+	client.mu.Lock()
+	client.err = errors.New("throttled")
+	client.mu.Unlock()
+
+	assert.Error(t, <-reloadErrors)
+	source.Close()
+
+	cfg, err := source.Config(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", cfg.Region)
+}
+
+func TestNewSourceWithoutReload(t *testing.T) {
+	client := &mockSSMClient{parameters: map[string]string{ParameterRegion: "us-west-2"}}
+
+	source, err := NewSource(context.Background(), client, testPath)
+	assert.NoError(t, err)
+	source.Close()
+
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestNewSourceFailsOnInvalidConfig(t *testing.T) {
+	_, err := NewSource(context.Background(), &mockSSMClient{parameters: map[string]string{}}, testPath)
+
+	assert.Error(t, err)
+}
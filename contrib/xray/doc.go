@@ -0,0 +1,9 @@
+// Package xray records the steps of MSK IAM auth token generation as AWS X-Ray subsegments.
+//
+// Pass a Tracer to any of the signer Generate functions to make credential retrieval and signing latency visible in
+// X-Ray service maps. Subsegments are only recorded when the context carries an X-Ray segment:
+//
+//	ctx, seg := awsxray.BeginSegment(context.Background(), "my-consumer")
+//	defer seg.Close(nil)
+//	token, expiryMs, err := signer.GenerateAuthToken(ctx, "us-west-2", signer.WithTracer(xray.Tracer{}))
+package xray
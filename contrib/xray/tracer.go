@@ -0,0 +1,27 @@
+package xray
+
+import (
+	"context"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	awsxray "github.com/aws/aws-xray-sdk-go/xray"
+)
+
+// SubsegmentNamePrefix is prepended to the span names reported by the signer to build the subsegment names.
+const SubsegmentNamePrefix = "MSKIAMAuth."
+
+// Tracer implements signer.Tracer by starting an X-Ray subsegment for each span.
+type Tracer struct{}
+
+var _ signer.Tracer = Tracer{}
+
+// StartSpan begins a subsegment of the segment carried by ctx. When ctx carries no segment the X-Ray context missing
+// strategy is applied and the returned function does nothing.
+func (Tracer) StartSpan(ctx context.Context, name string) (context.Context, func(err error)) {
+	subCtx, subsegment := awsxray.BeginSubsegment(ctx, SubsegmentNamePrefix+name)
+	if subsegment == nil {
+		return ctx, func(error) {}
+	}
+
+	return subCtx, subsegment.Close
+}
@@ -0,0 +1,36 @@
+package xray
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-xray-sdk-go/strategy/ctxmissing"
+	awsxray "github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartSpanRecordsSubsegment(t *testing.T) {
+	ctx, seg := awsxray.BeginSegment(context.Background(), "test")
+	defer seg.Close(nil)
+
+	spanCtx, end := Tracer{}.StartSpan(ctx, "SignToken")
+	subsegment := awsxray.GetSegment(spanCtx)
+	end(errors.New("signing failed"))
+
+	assert.NotNil(t, subsegment)
+	assert.Equal(t, SubsegmentNamePrefix+"SignToken", subsegment.Name)
+	assert.True(t, subsegment.Fault)
+}
+
+func TestStartSpanWithoutSegment(t *testing.T) {
+	ctx, err := awsxray.ContextWithConfig(context.Background(), awsxray.Config{
+		ContextMissingStrategy: ctxmissing.NewDefaultIgnoreErrorStrategy(),
+	})
+	assert.NoError(t, err)
+
+	spanCtx, end := Tracer{}.StartSpan(ctx, "LoadCredentials")
+
+	assert.Equal(t, ctx, spanCtx)
+	assert.NotPanics(t, func() { end(nil) })
+}
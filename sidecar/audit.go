@@ -0,0 +1,54 @@
+package sidecar
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// One audit log record per token request, written as a JSON line.
+type auditRecord struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id"`
+	ClientID  string    `json:"client_id"`
+	Identity  string    `json:"identity,omitempty"`
+	Status    int       `json:"status"`
+}
+
+// Writes the audit records of token requests, serializing writes.
+type auditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Writes the record as a JSON line. Records are dropped when no writer is configured.
+func (a *auditLog) write(record auditRecord) {
+	if a.w == nil {
+		return
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, _ = a.w.Write(append(line, '\n'))
+}
+
+// Flushes the writer when it buffers records, as bufio.Writer does, or syncs it to storage when it is a file.
+func (a *auditLog) flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch w := a.w.(type) {
+	case interface{ Flush() error }:
+		return w.Flush()
+	case interface{ Sync() error }:
+		return w.Sync()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,34 @@
+package sidecar
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerWritesAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	audit := bufio.NewWriter(&buf)
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.AuditWriter = audit
+		o.ClientID = HeaderClientID
+	})
+	srv, _ := startServer(t, server)
+	getToken(server, map[string]string{RequestIDHeader: "req-1", ClientIDHeader: "client-a"})
+	get(server, HealthzPath)
+	assert.Zero(t, buf.Len())
+
+	assert.NoError(t, server.Shutdown(context.Background(), srv))
+
+	var record auditRecord
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-1", record.RequestID)
+	assert.Equal(t, "client-a", record.ClientID)
+	assert.Equal(t, http.StatusOK, record.Status)
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,73 @@
+package sidecar
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// SPIFFEScheme is the URI scheme of SPIFFE IDs.
+const SPIFFEScheme = "spiffe"
+
+var (
+	// ErrClientCertificateRequired is returned for requests without a verified client certificate when the Server
+	// requires mTLS.
+	ErrClientCertificateRequired = errors.New("client certificate required")
+
+	// ErrClientNotAllowed is returned for requests whose client certificate has no allowed SPIFFE ID.
+	ErrClientNotAllowed = errors.New("client not allowed")
+)
+
+// NewMTLSConfig returns a TLS config presenting cert and requiring client certificates signed by clientCAs, for
+// serving a Server with RequireClientCert set, e.g. through http.Server.TLSConfig.
+func NewMTLSConfig(cert tls.Certificate, clientCAs *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// SPIFFEID returns the SPIFFE ID of the certificate, the spiffe URI in its subject alternative names, or an empty
+// string when it has none.
+func SPIFFEID(cert *x509.Certificate) string {
+	for _, uri := range cert.URIs {
+		if uri.Scheme == SPIFFEScheme {
+			return uri.String()
+		}
+	}
+	return ""
+}
+
+// Returns the leaf certificate the client presented over a verified TLS connection, or nil.
+func peerCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// Checks that the client of the request is allowed to get tokens.
+func (s *Server) authorize(r *http.Request) error {
+	if !s.options.RequireClientCert && len(s.options.AllowedSPIFFEIDs) == 0 {
+		return nil
+	}
+
+	cert := peerCertificate(r)
+	if cert == nil {
+		return ErrClientCertificateRequired
+	}
+	if len(s.options.AllowedSPIFFEIDs) == 0 {
+		return nil
+	}
+
+	spiffeID := SPIFFEID(cert)
+	if spiffeID == "" || !slices.Contains(s.options.AllowedSPIFFEIDs, spiffeID) {
+		return fmt.Errorf("%w: spiffe id %q", ErrClientNotAllowed, spiffeID)
+	}
+	return nil
+}
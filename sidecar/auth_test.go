@@ -0,0 +1,156 @@
+package sidecar
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A certificate authority issuing test certificates.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+// Creates a certificate authority.
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// Issues a certificate for the SPIFFE ID, or without URI SAN when empty.
+func (ca *testCA) issue(t *testing.T, spiffeID string, usage x509.ExtKeyUsage) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{"localhost"},
+	}
+	if spiffeID != "" {
+		uri, err := url.Parse(spiffeID)
+		assert.NoError(t, err)
+		template.URIs = []*url.URL{uri}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// Starts the server over mTLS, returning its url and a client presenting a certificate for the SPIFFE ID, or no
+// certificate when empty.
+func startMTLSServer(t *testing.T, server *Server, clientSPIFFEID string) (string, *http.Client) {
+	ca := newTestCA(t)
+	ts := httptest.NewUnstartedServer(server)
+	ts.TLS = NewMTLSConfig(ca.issue(t, "", x509.ExtKeyUsageServerAuth), ca.pool)
+	ts.TLS.ClientAuth = tls.VerifyClientCertIfGiven
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	clientTLS := &tls.Config{RootCAs: ca.pool, ServerName: "localhost", MinVersion: tls.VersionTLS12}
+	if clientSPIFFEID != "-" {
+		clientTLS.Certificates = []tls.Certificate{ca.issue(t, clientSPIFFEID, x509.ExtKeyUsageClientAuth)}
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+	t.Cleanup(client.CloseIdleConnections)
+	return ts.URL, client
+}
+
+// Gets a token from the server over the client, returning the status code.
+func getTokenStatus(t *testing.T, client *http.Client, serverURL string) int {
+	resp, err := client.Get(serverURL + TokenPath)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestServerAllowsSPIFFEID(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.AllowedSPIFFEIDs = []string{"spiffe://example.org/kafka-producer"}
+	})
+	serverURL, client := startMTLSServer(t, server, "spiffe://example.org/kafka-producer")
+
+	assert.Equal(t, http.StatusOK, getTokenStatus(t, client, serverURL))
+}
+
+func TestServerRejectsOtherSPIFFEID(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.AllowedSPIFFEIDs = []string{"spiffe://example.org/kafka-producer"}
+	})
+	serverURL, client := startMTLSServer(t, server, "spiffe://example.org/other")
+
+	assert.Equal(t, http.StatusForbidden, getTokenStatus(t, client, serverURL))
+}
+
+func TestServerRequiresClientCertificate(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.RequireClientCert = true
+	})
+	serverURL, client := startMTLSServer(t, server, "-")
+
+	assert.Equal(t, http.StatusUnauthorized, getTokenStatus(t, client, serverURL))
+}
+
+func TestServerRequiresClientCertificateWithoutTLS(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.RequireClientCert = true
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, getToken(server, nil).Code)
+}
+
+func TestServerAllowsAnyVerifiedClientCertificate(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.RequireClientCert = true
+	})
+	serverURL, client := startMTLSServer(t, server, "")
+
+	assert.Equal(t, http.StatusOK, getTokenStatus(t, client, serverURL))
+}
+
+func TestSPIFFEID(t *testing.T) {
+	ca := newTestCA(t)
+	withID := ca.issue(t, "spiffe://example.org/app", x509.ExtKeyUsageClientAuth)
+	withoutID := ca.issue(t, "", x509.ExtKeyUsageClientAuth)
+
+	cert, err := x509.ParseCertificate(withID.Certificate[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "spiffe://example.org/app", SPIFFEID(cert))
+
+	cert, err = x509.ParseCertificate(withoutID.Certificate[0])
+	assert.NoError(t, err)
+	assert.Empty(t, SPIFFEID(cert))
+}
@@ -0,0 +1,68 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	HealthzPath = "/healthz" // HealthzPath reports that the sidecar process is up.
+	ReadyzPath  = "/readyz"  // ReadyzPath reports whether the sidecar can mint tokens.
+
+	DefaultReadinessTimeout = 5 * time.Second // DefaultReadinessTimeout bounds the token generation of readiness checks.
+)
+
+// JSON document of health responses.
+type healthJSON struct {
+	Status string `json:"status"`
+}
+
+// Reports that the process is up.
+func (s *Server) serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	jsonResponse(http.StatusOK, healthJSON{Status: "ok"}).write(w)
+}
+
+// Reports whether a token can be minted by the default provider and every route, and never while draining.
+func (s *Server) serveReadyz(w http.ResponseWriter, r *http.Request) {
+	if s.draining.Load() {
+		s.metrics.setReady(false)
+		writeError(w, http.StatusServiceUnavailable, "sidecar is shutting down")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.options.ReadinessTimeout)
+	defer cancel()
+
+	err := s.checkReady(ctx)
+	s.metrics.setReady(err == nil)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	jsonResponse(http.StatusOK, healthJSON{Status: "ready"}).write(w)
+}
+
+// Gets a token from the default provider and from the provider of every route, in identity order.
+func (s *Server) checkReady(ctx context.Context) error {
+	if s.provider != nil {
+		if _, err := s.provider.Token(ctx); err != nil {
+			return fmt.Errorf("failed to get auth token: %w", err)
+		}
+	}
+
+	identities := make([]string, 0, len(s.options.Routes))
+	for identity := range s.options.Routes {
+		identities = append(identities, identity)
+	}
+	sort.Strings(identities)
+
+	for _, identity := range identities {
+		if _, err := s.options.Routes[identity].Token(ctx); err != nil {
+			return fmt.Errorf("failed to get auth token for %s: %w", identity, err)
+		}
+	}
+	return nil
+}
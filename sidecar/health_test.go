@@ -0,0 +1,52 @@
+package sidecar
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Serves a GET request of the path.
+func get(server http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestServerHealthz(t *testing.T) {
+	server := NewServer(&countingTokenProvider{err: errors.New("no credentials")})
+
+	rec := get(server, HealthzPath)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestServerReadyz(t *testing.T) {
+	provider := &countingTokenProvider{}
+	server := NewServer(provider)
+
+	rec := get(server, ReadyzPath)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ready"}`, rec.Body.String())
+	assert.Equal(t, int64(1), provider.calls.Load())
+}
+
+func TestServerReadyzFailsWhenRouteCannotMint(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.Routes = map[string]signer.TokenProvider{
+			"uid:1000": &countingTokenProvider{err: errors.New("access denied")},
+		}
+	})
+
+	rec := get(server, ReadyzPath)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "uid:1000")
+	assert.Contains(t, rec.Body.String(), "access denied")
+}
@@ -0,0 +1,79 @@
+package sidecar
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// A response kept to be replayed to retries of an idempotent request.
+type cachedResponse struct {
+	status  int
+	body    []byte
+	expires time.Time
+}
+
+// Writes the response as JSON.
+func (c cachedResponse) write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(c.status)
+	_, _ = w.Write(c.body)
+}
+
+// A key of the replay cache with the expiry of the response it was put with.
+type replayEntry struct {
+	key     string
+	expires time.Time
+}
+
+// Keeps the responses to idempotent requests for a fixed time, up to a maximum number of responses.
+type replayCache struct {
+	ttl time.Duration
+	max int
+
+	mu        sync.Mutex
+	responses map[string]cachedResponse
+	order     []replayEntry // Keys in the order they were put, oldest first. Replaced keys are skipped.
+}
+
+// Returns a cache replaying up to maxResponses responses for ttl.
+func newReplayCache(ttl time.Duration, maxResponses int) *replayCache {
+	return &replayCache{ttl: ttl, max: maxResponses, responses: map[string]cachedResponse{}}
+}
+
+// Returns the unexpired response kept under key.
+func (c *replayCache) get(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	response, ok := c.responses[key]
+	if !ok || time.Now().After(response.expires) {
+		return cachedResponse{}, false
+	}
+	return response, true
+}
+
+// Keeps the response under key, dropping expired responses and the oldest ones while the cache is full.
+func (c *replayCache) put(key string, response cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	delete(c.responses, key)
+	for len(c.order) > 0 {
+		oldest := c.order[0]
+		kept, ok := c.responses[oldest.key]
+		replaced := !ok || !kept.expires.Equal(oldest.expires)
+		if !replaced && !now.After(oldest.expires) && len(c.responses) < c.max {
+			break
+		}
+		if !replaced {
+			delete(c.responses, oldest.key)
+		}
+		c.order = c.order[1:]
+	}
+
+	response.expires = now.Add(c.ttl)
+	c.responses[key] = response
+	c.order = append(c.order, replayEntry{key: key, expires: response.expires})
+}
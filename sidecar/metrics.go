@@ -0,0 +1,89 @@
+package sidecar
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MetricsPath serves the self-metrics of the sidecar in the Prometheus text format when Options.EnableMetrics is set.
+const MetricsPath = "/metrics"
+
+// Counts the token requests served by the sidecar.
+type serverMetrics struct {
+	mu       sync.Mutex
+	requests map[int]uint64
+	replays  uint64
+	ready    float64
+}
+
+// Returns empty metrics.
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{requests: map[int]uint64{}}
+}
+
+// Counts a token request answered with the status code.
+func (m *serverMetrics) observeRequest(status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[status]++
+}
+
+// Counts a response replayed to the retry of an idempotent request.
+func (m *serverMetrics) observeReplay() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replays++
+}
+
+// Records the result of the last readiness check.
+func (m *serverMetrics) setReady(ready bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ready = 0
+	if ready {
+		m.ready = 1
+	}
+}
+
+// Writes the metrics in the Prometheus text format.
+func (m *serverMetrics) serveHTTP(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP msk_iam_auth_sidecar_token_requests_total Number of token requests by response status.\n")
+	b.WriteString("# TYPE msk_iam_auth_sidecar_token_requests_total counter\n")
+	statuses := make([]int, 0, len(m.requests))
+	for status := range m.requests {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "msk_iam_auth_sidecar_token_requests_total{code=\"%d\"} %d\n", status, m.requests[status])
+	}
+
+	b.WriteString("# HELP msk_iam_auth_sidecar_idempotent_replays_total Number of responses replayed to retries.\n")
+	b.WriteString("# TYPE msk_iam_auth_sidecar_idempotent_replays_total counter\n")
+	fmt.Fprintf(&b, "msk_iam_auth_sidecar_idempotent_replays_total %d\n", m.replays)
+
+	b.WriteString("# HELP msk_iam_auth_sidecar_ready Result of the last readiness check, one when ready.\n")
+	b.WriteString("# TYPE msk_iam_auth_sidecar_ready gauge\n")
+	fmt.Fprintf(&b, "msk_iam_auth_sidecar_ready %g\n", m.ready)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// Records the status code written to the wrapped response writer.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
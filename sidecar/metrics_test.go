@@ -0,0 +1,35 @@
+package sidecar
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerMetrics(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.EnableMetrics = true
+		o.RateLimit = 0.001
+		o.Burst = 1
+	})
+	headers := map[string]string{IdempotencyKeyHeader: "retry-1"}
+	getToken(server, headers)
+	getToken(server, headers)
+	getToken(server, nil)
+	get(server, ReadyzPath)
+
+	rec := get(server, MetricsPath)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `msk_iam_auth_sidecar_token_requests_total{code="200"} 2`)
+	assert.Contains(t, rec.Body.String(), `msk_iam_auth_sidecar_token_requests_total{code="429"} 1`)
+	assert.Contains(t, rec.Body.String(), "msk_iam_auth_sidecar_idempotent_replays_total 1\n")
+	assert.Contains(t, rec.Body.String(), "msk_iam_auth_sidecar_ready 1\n")
+}
+
+func TestServerMetricsDisabled(t *testing.T) {
+	server := NewServer(&countingTokenProvider{})
+
+	assert.Equal(t, http.StatusNotFound, get(server, MetricsPath).Code)
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package sidecar
+
+import (
+	"net"
+	"syscall"
+)
+
+// Returns the uid of the peer of a Unix socket connection, read with SO_PEERCRED.
+func peerUID(c net.Conn) (uint32, bool) {
+	unixConn, ok := c.(*net.UnixConn)
+	if !ok {
+		return 0, false
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return 0, false
+	}
+
+	var cred *syscall.Ucred
+	var credErr error
+	err = rawConn.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil || credErr != nil {
+		return 0, false
+	}
+	return cred.Uid, true
+}
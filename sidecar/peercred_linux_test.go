@@ -0,0 +1,40 @@
+//go:build linux
+
+package sidecar
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerRoutesByPeerUID(t *testing.T) {
+	routed := &countingTokenProvider{}
+	server := NewServer(nil, func(o *Options) {
+		o.Routes = map[string]signer.TokenProvider{UIDIdentityPrefix + strconv.Itoa(os.Getuid()): routed}
+	})
+
+	socket := filepath.Join(t.TempDir(), "sidecar.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	srv := &http.Server{Handler: server, ConnContext: ConnContext}
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	t.Cleanup(client.CloseIdleConnections)
+
+	assert.Equal(t, http.StatusOK, getTokenStatus(t, client, "http://sidecar"))
+	assert.Equal(t, int64(1), routed.calls.Load())
+}
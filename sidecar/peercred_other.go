@@ -0,0 +1,10 @@
+//go:build !linux
+
+package sidecar
+
+import "net"
+
+// Returns false, SO_PEERCRED is not available on this platform.
+func peerUID(c net.Conn) (uint32, bool) {
+	return 0, false
+}
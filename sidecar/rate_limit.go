@@ -0,0 +1,64 @@
+package sidecar
+
+import (
+	"sync"
+	"time"
+)
+
+// Limits the request rate of every client with a token bucket per client.
+type clientLimiter struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// The tokens left to a client and when they were last refilled.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Returns a limiter allowing rate requests per second in bursts of burst requests to every client, or allowing every
+// request when rate is not positive.
+func newClientLimiter(rate float64, burst int) *clientLimiter {
+	return &clientLimiter{rate: rate, burst: float64(max(burst, 1)), buckets: map[string]*bucket{}}
+}
+
+// Takes a request from the bucket of the client, returning how long to wait for the next one when it is empty. Buckets
+// refilled to the burst are dropped when a new client arrives, as a new bucket is full too.
+func (l *clientLimiter) allow(clientID string) (time.Duration, bool) {
+	if l.rate <= 0 {
+		return 0, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[clientID]
+	if !ok {
+		l.evictIdle(now)
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[clientID] = b
+	}
+
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second)), false
+	}
+
+	b.tokens--
+	return 0, true
+}
+
+// Drops the buckets of the clients that have been idle long enough to be refilled to the burst.
+func (l *clientLimiter) evictIdle(now time.Time) {
+	for clientID, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, clientID)
+		}
+	}
+}
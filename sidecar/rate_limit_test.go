@@ -0,0 +1,42 @@
+package sidecar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientLimiterUnlimited(t *testing.T) {
+	limiter := newClientLimiter(0, 0)
+
+	for i := 0; i < 100; i++ {
+		_, ok := limiter.allow("client")
+		assert.True(t, ok)
+	}
+}
+
+func TestClientLimiterRefills(t *testing.T) {
+	limiter := newClientLimiter(1000, 1)
+
+	_, ok := limiter.allow("client")
+	assert.True(t, ok)
+	wait, ok := limiter.allow("client")
+	assert.False(t, ok)
+	assert.Positive(t, wait)
+
+	limiter.buckets["client"].last = limiter.buckets["client"].last.Add(-2 * wait)
+	_, ok = limiter.allow("client")
+	assert.True(t, ok)
+}
+
+func TestClientLimiterEvictsIdleBuckets(t *testing.T) {
+	limiter := newClientLimiter(1000, 1)
+
+	limiter.allow("client-a")
+	limiter.buckets["client-a"].last = limiter.buckets["client-a"].last.Add(-time.Second)
+	limiter.allow("client-b")
+
+	assert.NotContains(t, limiter.buckets, "client-a")
+	assert.Contains(t, limiter.buckets, "client-b")
+}
@@ -0,0 +1,81 @@
+package sidecar
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+)
+
+// UIDIdentityPrefix prefixes the peer uid of Unix socket clients in their identity, e.g. "uid:1000".
+const UIDIdentityPrefix = "uid:"
+
+// Context key of the credentials of the peer of a Unix socket connection.
+type peerCredKey struct{}
+
+// ConnContext records the uid of the peer of Unix socket connections, read with SO_PEERCRED, in the connection
+// context so that Options.Routes can route by it. Set it as the ConnContext of the http.Server serving the Server.
+// It is a no-op on platforms without SO_PEERCRED and for other connections.
+//
+//	srv := &http.Server{Handler: sidecar.NewServer(nil, withRoutes), ConnContext: sidecar.ConnContext}
+//	srv.Serve(unixListener)
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	if uid, ok := peerUID(c); ok {
+		return context.WithValue(ctx, peerCredKey{}, uid)
+	}
+	return ctx
+}
+
+// Returns the peer uid recorded by ConnContext.
+func peerUIDFromContext(ctx context.Context) (uint32, bool) {
+	uid, ok := ctx.Value(peerCredKey{}).(uint32)
+	return uid, ok
+}
+
+// Returns the identities of the client the request is routed by, most specific first: the URI, DNS and email
+// subject alternative names of its verified certificate, then its Unix socket peer uid.
+func clientIdentities(r *http.Request) []string {
+	var identities []string
+
+	if cert := peerCertificate(r); cert != nil {
+		for _, uri := range cert.URIs {
+			identities = append(identities, uri.String())
+		}
+		identities = append(identities, cert.DNSNames...)
+		identities = append(identities, cert.EmailAddresses...)
+	}
+
+	if uid, ok := peerUIDFromContext(r.Context()); ok {
+		identities = append(identities, UIDIdentityPrefix+strconv.FormatUint(uint64(uid), 10))
+	}
+
+	return identities
+}
+
+// Returns the token provider the request is routed to with the identity that matched, or the default provider with
+// an empty identity when no route matches.
+func (s *Server) route(r *http.Request) (signer.TokenProvider, string) {
+	for _, identity := range clientIdentities(r) {
+		if provider, ok := s.options.Routes[identity]; ok {
+			return provider, identity
+		}
+	}
+	return s.provider, ""
+}
+
+// NewRoleRoutes returns routes for Options.Routes generating the tokens of each client identity from the credentials
+// of the role it maps to, assumed with the credentials of the default provider chain as signer.WithRoleARN does, so
+// the sts settings of optFns, such as the sts region, endpoint and retryer, apply. The sts client passed with
+// signer.WithSTSClient, if any, assumes the roles. optFns are applied to every route provider.
+func NewRoleRoutes(ctx context.Context, region string, roleARNs map[string]string, optFns ...signer.Option) (
+	map[string]signer.TokenProvider, error,
+) {
+	routes := make(map[string]signer.TokenProvider, len(roleARNs))
+	for identity, roleARN := range roleARNs {
+		roleOptFns := append(optFns[:len(optFns):len(optFns)], signer.WithRoleARN(roleARN, signer.DefaultSessionName))
+		routes[identity] = signer.NewProvider(region, nil, roleOptFns...)
+	}
+	return routes, nil
+}
@@ -0,0 +1,113 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerRoutesByCertificateSAN(t *testing.T) {
+	routed := &countingTokenProvider{}
+	server := NewServer(nil, func(o *Options) {
+		o.Routes = map[string]signer.TokenProvider{"spiffe://example.org/producer": routed}
+	})
+	serverURL, client := startMTLSServer(t, server, "spiffe://example.org/producer")
+
+	assert.Equal(t, http.StatusOK, getTokenStatus(t, client, serverURL))
+	assert.Equal(t, int64(1), routed.calls.Load())
+}
+
+func TestServerFallsBackToDefaultProvider(t *testing.T) {
+	fallback, routed := &countingTokenProvider{}, &countingTokenProvider{}
+	server := NewServer(fallback, func(o *Options) {
+		o.Routes = map[string]signer.TokenProvider{"spiffe://example.org/producer": routed}
+	})
+	serverURL, client := startMTLSServer(t, server, "spiffe://example.org/consumer")
+
+	assert.Equal(t, http.StatusOK, getTokenStatus(t, client, serverURL))
+	assert.Equal(t, int64(1), fallback.calls.Load())
+	assert.Zero(t, routed.calls.Load())
+}
+
+func TestServerRejectsUnroutedClientWithoutDefaultProvider(t *testing.T) {
+	server := NewServer(nil, func(o *Options) {
+		o.Routes = map[string]signer.TokenProvider{"uid:0": &countingTokenProvider{}}
+	})
+
+	assert.Equal(t, http.StatusForbidden, getToken(server, nil).Code)
+}
+
+// Assumes roles without calling sts, recording the requested role arns.
+type mockSTSClient struct {
+	roleARNs []string
+}
+
+func (m *mockSTSClient) AssumeRole(
+	ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options),
+) (*sts.AssumeRoleOutput, error) {
+	m.roleARNs = append(m.roleARNs, aws.ToString(params.RoleArn))
+	return &sts.AssumeRoleOutput{Credentials: &types.Credentials{
+		AccessKeyId:     aws.String("TEST-ROUTE-ACCESS-KEY"),
+		SecretAccessKey: aws.String("TEST-ROUTE-SECRET-KEY"),
+		SessionToken:    aws.String("TEST-ROUTE-SESSION-TOKEN"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}}, nil
+}
+
+func (m *mockSTSClient) AssumeRoleWithWebIdentity(
+	ctx context.Context, params *sts.AssumeRoleWithWebIdentityInput, optFns ...func(*sts.Options),
+) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	panic("not used")
+}
+
+func TestNewRoleRoutes(t *testing.T) {
+	client := &mockSTSClient{}
+	roleARN := "arn:aws:iam::123456789012:role/producer"
+
+	routes, err := NewRoleRoutes(context.Background(), "us-west-2", map[string]string{"uid:1000": roleARN},
+		signer.WithSTSClient(client))
+	assert.NoError(t, err)
+
+	token, err := routes["uid:1000"].Token(context.Background())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, []string{roleARN}, client.roleARNs)
+}
+
+func TestNewRoleRoutesWithSTSEndpoint(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprint(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials>`+
+			`<AccessKeyId>TEST-ROUTE-ACCESS-KEY</AccessKeyId><SecretAccessKey>TEST-ROUTE-SECRET-KEY</SecretAccessKey>`+
+			`<SessionToken>TEST-ROUTE-SESSION-TOKEN</SessionToken><Expiration>2099-01-01T00:00:00Z</Expiration>`+
+			`</Credentials></AssumeRoleResult></AssumeRoleResponse>`)
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-ENV-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-ENV-SECRET-KEY")
+
+	routes, err := NewRoleRoutes(context.Background(), "us-west-2",
+		map[string]string{"uid:1000": "arn:aws:iam::123456789012:role/producer"}, signer.WithSTSRegion("", server.URL))
+	assert.NoError(t, err)
+
+	token, err := routes["uid:1000"].Token(context.Background())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, int64(1), calls.Load())
+}
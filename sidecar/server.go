@@ -0,0 +1,287 @@
+// Package sidecar serves the MSK IAM auth tokens of a signer.TokenProvider over HTTP, so co-located processes that
+// cannot embed the Go signer, such as non-Go Kafka clients, share one token cache and one set of credentials.
+//
+//	provider := signer.NewProvider("us-west-2", nil)
+//	http.ListenAndServe("127.0.0.1:8089", sidecar.NewServer(provider))
+//
+// Clients GET /token and receive the token as the JSON document of signer.Token.MarshalJSON. Orchestrators probe
+// /healthz for liveness and /readyz, which mints a token, for readiness.
+package sidecar
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+)
+
+const (
+	TokenPath            = "/token"          // TokenPath is the path tokens are served on.
+	RequestIDHeader      = "X-Request-Id"    // RequestIDHeader carries the request id, generated when not supplied.
+	IdempotencyKeyHeader = "Idempotency-Key" // IdempotencyKeyHeader makes retries of a request return the same token.
+	ClientIDHeader       = "X-Client-Id"     // ClientIDHeader identifies the client to HeaderClientID.
+
+	DefaultIdempotencyTTL         = time.Minute // DefaultIdempotencyTTL is how long idempotent responses are replayed.
+	DefaultMaxIdempotentResponses = 10000       // DefaultMaxIdempotentResponses is how many responses are replayed.
+)
+
+// Options configures the Server.
+type Options struct {
+	// RateLimit is the number of token requests per second each client may make. Clients are not rate limited when
+	// zero.
+	RateLimit float64
+
+	// Burst is the number of token requests a client may make at once. It is at least one when rate limiting.
+	Burst int
+
+	// IdempotencyTTL is how long the response to a request carrying an idempotency key is replayed to retries with
+	// the same key. DefaultIdempotencyTTL is used when zero.
+	IdempotencyTTL time.Duration
+
+	// MaxIdempotentResponses is how many idempotent responses are kept for replay. The oldest responses are dropped
+	// first once it is reached. DefaultMaxIdempotentResponses is used when zero.
+	MaxIdempotentResponses int
+
+	// ClientID identifies the client of a request for rate limiting and idempotency. The SPIFFE ID of the client
+	// certificate, the Unix socket peer uid or the remote host, in that order, is used when nil. Clients choose the
+	// X-Client-Id header, so it is only used when set to HeaderClientID, e.g. behind a proxy that sets it.
+	ClientID func(r *http.Request) string
+
+	// RequireClientCert rejects requests that did not present a client certificate verified by the TLS config of the
+	// listener, see NewMTLSConfig.
+	RequireClientCert bool
+
+	// AllowedSPIFFEIDs are the SPIFFE IDs of the client certificates allowed to get tokens. Client certificates are
+	// required and any verified one is allowed when empty and RequireClientCert is set.
+	AllowedSPIFFEIDs []string
+
+	// Routes maps client identities to the token providers of their tokens, e.g. providers of distinct roles built
+	// with NewRoleRoutes, so one sidecar serves workloads with different Kafka permissions. Identities are the URI,
+	// DNS and email subject alternative names of the client certificate, and the peer uid of Unix socket clients
+	// prefixed with UIDIdentityPrefix, see ConnContext. Clients matching no route get the tokens of the provider
+	// passed to NewServer.
+	Routes map[string]signer.TokenProvider
+
+	// ReadinessTimeout bounds the token generation of readiness checks. DefaultReadinessTimeout is used when zero.
+	ReadinessTimeout time.Duration
+
+	// EnableMetrics serves the request counts and readiness of the sidecar on MetricsPath.
+	EnableMetrics bool
+
+	// DrainWindow is how long Shutdown keeps serving tokens after reporting the sidecar as not ready, giving
+	// orchestrators time to stop routing clients to it. Shutdown stops right away when zero.
+	DrainWindow time.Duration
+
+	// AuditWriter receives a JSON line per token request with the request id, client, routed identity and response
+	// status. It is flushed by Shutdown. No audit log is written when nil.
+	AuditWriter io.Writer
+}
+
+// Server is an http.Handler serving the tokens of a signer.TokenProvider. Every response carries the request id
+// supplied by the client or generated by the server. It is safe for concurrent use.
+type Server struct {
+	provider signer.TokenProvider
+	options  Options
+	mux      *http.ServeMux
+	limiter  *clientLimiter
+	replays  *replayCache
+	metrics  *serverMetrics
+	audit    *auditLog
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// NewServer returns a Server serving the tokens of provider to clients matching no route. provider may be nil when
+// only routed clients are served, other clients are then rejected.
+func NewServer(provider signer.TokenProvider, optFns ...func(*Options)) *Server {
+	var options Options
+	for _, fn := range optFns {
+		fn(&options)
+	}
+	if options.IdempotencyTTL <= 0 {
+		options.IdempotencyTTL = DefaultIdempotencyTTL
+	}
+	if options.MaxIdempotentResponses <= 0 {
+		options.MaxIdempotentResponses = DefaultMaxIdempotentResponses
+	}
+	if options.ClientID == nil {
+		options.ClientID = defaultClientID
+	}
+	if options.ReadinessTimeout <= 0 {
+		options.ReadinessTimeout = DefaultReadinessTimeout
+	}
+
+	s := &Server{
+		provider: provider,
+		options:  options,
+		mux:      http.NewServeMux(),
+		limiter:  newClientLimiter(options.RateLimit, options.Burst),
+		replays:  newReplayCache(options.IdempotencyTTL, options.MaxIdempotentResponses),
+		metrics:  newServerMetrics(),
+		audit:    &auditLog{w: options.AuditWriter},
+	}
+	s.mux.HandleFunc(TokenPath, s.serveToken)
+	s.mux.HandleFunc(HealthzPath, s.serveHealthz)
+	s.mux.HandleFunc(ReadyzPath, s.serveReadyz)
+	if options.EnableMetrics {
+		s.mux.HandleFunc(MetricsPath, s.metrics.serveHTTP)
+	}
+	return s
+}
+
+// ServeHTTP tags the request with its request id and dispatches it.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = newRequestID()
+		r.Header.Set(RequestIDHeader, requestID)
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+
+	if r.URL.Path != TokenPath {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(recorder, r)
+	s.metrics.observeRequest(recorder.status)
+
+	_, identity := s.route(r)
+	s.audit.write(auditRecord{
+		Time:      time.Now().UTC(),
+		RequestID: requestID,
+		ClientID:  s.options.ClientID(r),
+		Identity:  identity,
+		Status:    recorder.status,
+	})
+}
+
+// Serves the current token, replaying the response to retries of idempotent requests.
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if err := s.authorize(r); err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, ErrClientCertificateRequired) {
+			status = http.StatusUnauthorized
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+
+	provider, identity := s.route(r)
+	if provider == nil {
+		writeError(w, http.StatusForbidden, ErrClientNotAllowed.Error()+": no route for client")
+		return
+	}
+
+	clientID := s.options.ClientID(r)
+	replayKey := ""
+	if key := r.Header.Get(IdempotencyKeyHeader); key != "" {
+		replayKey = identity + "\x00" + clientID + "\x00" + key
+		if response, ok := s.replays.get(replayKey); ok {
+			s.metrics.observeReplay()
+			response.write(w)
+			return
+		}
+	}
+
+	if wait, ok := s.limiter.allow(clientID); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(wait.Round(time.Second)/time.Second)+1))
+		writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+
+	response := s.tokenResponse(r, provider)
+	if replayKey != "" && response.status == http.StatusOK {
+		s.replays.put(replayKey, response)
+	}
+	response.write(w)
+}
+
+// Builds the response carrying the current token of the provider.
+func (s *Server) tokenResponse(r *http.Request, provider signer.TokenProvider) cachedResponse {
+	token, err := provider.Token(r.Context())
+	s.observeTokenResult(err)
+	if err != nil {
+		return errorResponse(http.StatusServiceUnavailable, "failed to get auth token: "+err.Error())
+	}
+
+	body, err := json.Marshal(token)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, "failed to encode auth token: "+err.Error())
+	}
+	return cachedResponse{status: http.StatusOK, body: body}
+}
+
+// HeaderClientID identifies the client by the X-Client-Id header, or as the default ClientID does when the header is
+// missing. Clients choose the header, so it only limits clients behind a trusted proxy setting it.
+func HeaderClientID(r *http.Request) string {
+	if clientID := r.Header.Get(ClientIDHeader); clientID != "" {
+		return clientID
+	}
+	return defaultClientID(r)
+}
+
+// Identifies the client by the SPIFFE ID of its certificate, its Unix socket peer uid, or its remote host, none of
+// which the client chooses.
+func defaultClientID(r *http.Request) string {
+	if cert := peerCertificate(r); cert != nil {
+		if spiffeID := SPIFFEID(cert); spiffeID != "" {
+			return spiffeID
+		}
+	}
+	if uid, ok := peerUIDFromContext(r.Context()); ok {
+		return UIDIdentityPrefix + strconv.FormatUint(uint64(uid), 10)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// Generates a random request id.
+func newRequestID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// JSON document of error responses.
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// Builds an error response.
+func errorResponse(status int, message string) cachedResponse {
+	return jsonResponse(status, errorJSON{Error: message})
+}
+
+// Builds a response carrying the JSON encoding of v.
+func jsonResponse(status int, v any) cachedResponse {
+	body, _ := json.Marshal(v)
+	return cachedResponse{status: status, body: body}
+}
+
+// Writes an error response.
+func writeError(w http.ResponseWriter, status int, message string) {
+	errorResponse(status, message).write(w)
+}
@@ -0,0 +1,231 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a new token on every call, counting the calls.
+type countingTokenProvider struct {
+	calls atomic.Int64
+	err   error
+}
+
+func (p *countingTokenProvider) Token(ctx context.Context) (*signer.Token, error) {
+	n := p.calls.Add(1)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &signer.Token{
+		Value:            fmt.Sprintf("token-%d", n),
+		ExpirationTimeMs: time.Now().Add(15 * time.Minute).UnixMilli(),
+	}, nil
+}
+
+// Serves a token request carrying the headers.
+func getToken(server http.Handler, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, TokenPath, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	return rec
+}
+
+// Serves a token request from the remote address carrying the headers.
+func getTokenFrom(server http.Handler, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, TokenPath, nil)
+	req.RemoteAddr = remoteAddr
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	return rec
+}
+
+// Decodes the token of a token response.
+func decodeToken(t *testing.T, rec *httptest.ResponseRecorder) signer.Token {
+	var token signer.Token
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &token))
+	return token
+}
+
+func TestServerServesToken(t *testing.T) {
+	server := NewServer(&countingTokenProvider{})
+
+	rec := getToken(server, nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "token-1", decodeToken(t, rec).Value)
+	assert.Len(t, rec.Header().Get(RequestIDHeader), 32)
+}
+
+func TestServerEchoesRequestID(t *testing.T) {
+	server := NewServer(&countingTokenProvider{})
+
+	rec := getToken(server, map[string]string{RequestIDHeader: "req-42"})
+
+	assert.Equal(t, "req-42", rec.Header().Get(RequestIDHeader))
+}
+
+func TestServerRejectsOtherMethods(t *testing.T) {
+	server := NewServer(&countingTokenProvider{})
+	rec := httptest.NewRecorder()
+
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, TokenPath, nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, http.MethodGet, rec.Header().Get("Allow"))
+}
+
+func TestServerReportsProviderError(t *testing.T) {
+	server := NewServer(&countingTokenProvider{err: errors.New("no credentials")})
+
+	rec := getToken(server, nil)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "no credentials")
+}
+
+func TestServerReplaysIdempotentRequests(t *testing.T) {
+	provider := &countingTokenProvider{}
+	server := NewServer(provider, func(o *Options) {
+		o.ClientID = HeaderClientID
+	})
+	headers := map[string]string{IdempotencyKeyHeader: "retry-1", ClientIDHeader: "client-a"}
+
+	first := getToken(server, headers)
+	retry := getToken(server, headers)
+	other := getToken(server, map[string]string{IdempotencyKeyHeader: "retry-1", ClientIDHeader: "client-b"})
+
+	assert.Equal(t, decodeToken(t, first).Value, decodeToken(t, retry).Value)
+	assert.NotEqual(t, decodeToken(t, first).Value, decodeToken(t, other).Value)
+	assert.Equal(t, int64(2), provider.calls.Load())
+}
+
+func TestServerDoesNotReplayErrors(t *testing.T) {
+	provider := &countingTokenProvider{err: errors.New("no credentials")}
+	server := NewServer(provider)
+	headers := map[string]string{IdempotencyKeyHeader: "retry-1"}
+
+	getToken(server, headers)
+	provider.err = nil
+	rec := getToken(server, headers)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestServerExpiresIdempotentResponses(t *testing.T) {
+	provider := &countingTokenProvider{}
+	server := NewServer(provider, func(o *Options) {
+		o.IdempotencyTTL = time.Millisecond
+	})
+	headers := map[string]string{IdempotencyKeyHeader: "retry-1"}
+
+	getToken(server, headers)
+	time.Sleep(5 * time.Millisecond)
+	getToken(server, headers)
+
+	assert.Equal(t, int64(2), provider.calls.Load())
+}
+
+func TestServerDropsOldestIdempotentResponses(t *testing.T) {
+	provider := &countingTokenProvider{}
+	server := NewServer(provider, func(o *Options) {
+		o.MaxIdempotentResponses = 2
+	})
+
+	for _, key := range []string{"retry-1", "retry-2", "retry-3"} {
+		getToken(server, map[string]string{IdempotencyKeyHeader: key})
+	}
+	getToken(server, map[string]string{IdempotencyKeyHeader: "retry-3"})
+	assert.Equal(t, int64(3), provider.calls.Load())
+
+	getToken(server, map[string]string{IdempotencyKeyHeader: "retry-1"})
+	assert.Equal(t, int64(4), provider.calls.Load())
+}
+
+func TestServerRateLimitsPerClient(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.RateLimit = 0.001
+		o.Burst = 2
+	})
+
+	assert.Equal(t, http.StatusOK, getTokenFrom(server, "10.0.0.1:1234", nil).Code)
+	assert.Equal(t, http.StatusOK, getTokenFrom(server, "10.0.0.1:1235", nil).Code)
+	limited := getTokenFrom(server, "10.0.0.1:1236", nil)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, getTokenFrom(server, "10.0.0.2:1234", nil).Code)
+}
+
+func TestServerRateLimitIgnoresClientIDHeader(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.RateLimit = 0.001
+		o.Burst = 2
+	})
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		headers := map[string]string{ClientIDHeader: fmt.Sprintf("client-%d", i)}
+		codes = append(codes, getTokenFrom(server, "10.0.0.1:1234", headers).Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestServerRateLimitsByHeaderClientID(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.RateLimit = 0.001
+		o.Burst = 1
+		o.ClientID = HeaderClientID
+	})
+
+	assert.Equal(t, http.StatusOK, getToken(server, map[string]string{ClientIDHeader: "client-a"}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, getToken(server, map[string]string{ClientIDHeader: "client-a"}).Code)
+	assert.Equal(t, http.StatusOK, getToken(server, map[string]string{ClientIDHeader: "client-b"}).Code)
+}
+
+func TestServerReplaysIdempotentRequestsWhenRateLimited(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.RateLimit = 0.001
+		o.Burst = 1
+	})
+	headers := map[string]string{IdempotencyKeyHeader: "retry-1"}
+
+	first := getToken(server, headers)
+	retry := getToken(server, headers)
+
+	assert.Equal(t, http.StatusOK, retry.Code)
+	assert.Equal(t, decodeToken(t, first).Value, decodeToken(t, retry).Value)
+}
+
+func TestDefaultClientIDUsesRemoteHost(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, TokenPath, nil)
+	req.RemoteAddr = "10.0.0.7:53211"
+
+	assert.Equal(t, "10.0.0.7", defaultClientID(req))
+}
+
+func TestDefaultClientIDIgnoresClientIDHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, TokenPath, nil)
+	req.RemoteAddr = "10.0.0.7:53211"
+	req.Header.Set(ClientIDHeader, "client-a")
+
+	assert.Equal(t, "10.0.0.7", defaultClientID(req))
+	assert.Equal(t, "client-a", HeaderClientID(req))
+}
@@ -0,0 +1,66 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrTokenFailures is returned by Shutdown when the last token request failed to get a token, so the exit status of
+// the sidecar reflects refresh errors.
+var ErrTokenFailures = errors.New("sidecar was failing to get auth tokens")
+
+// Shutdown gracefully stops the sidecar served by srv. It first drains: /readyz reports the sidecar as not ready so
+// orchestrators stop routing new clients to it, while token requests are still served, from the provider cache, for
+// Options.DrainWindow. It then stops accepting connections and waits for in-flight requests, and finally flushes the
+// audit log. The error wraps ErrTokenFailures, with the provider error, when the last token request failed; a
+// non-nil error should make the process exit with a non-zero status.
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+//	defer stop()
+//	go srv.ListenAndServe()
+//	<-ctx.Done()
+//	if err := sidecarServer.Shutdown(context.Background(), srv); err != nil {
+//		log.Print(err)
+//		os.Exit(1)
+//	}
+func (s *Server) Shutdown(ctx context.Context, srv *http.Server) error {
+	s.draining.Store(true)
+
+	if s.options.DrainWindow > 0 {
+		timer := time.NewTimer(s.options.DrainWindow)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	var errs []error
+	if err := srv.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down sidecar: %w", err))
+	}
+	if err := s.audit.flush(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush audit log: %w", err))
+	}
+	if err := s.lastTokenError(); err != nil {
+		errs = append(errs, fmt.Errorf("%w: %w", ErrTokenFailures, err))
+	}
+	return errors.Join(errs...)
+}
+
+// Records the result of getting a token from a provider.
+func (s *Server) observeTokenResult(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+}
+
+// Returns the error of the last token request, nil when it succeeded.
+func (s *Server) lastTokenError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
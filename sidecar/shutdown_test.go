@@ -0,0 +1,77 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Serves the server on a local listener, returning the http server and its url.
+func startServer(t *testing.T, server *Server) (*http.Server, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := &http.Server{Handler: server}
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv, "http://" + listener.Addr().String()
+}
+
+func TestServerShutdownDrains(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.DrainWindow = 200 * time.Millisecond
+	})
+	srv, serverURL := startServer(t, server)
+
+	done := make(chan error, 1)
+	go func() { done <- server.Shutdown(context.Background(), srv) }()
+	assert.Eventually(t, server.draining.Load, time.Second, time.Millisecond)
+
+	assert.Equal(t, http.StatusServiceUnavailable, get(server, ReadyzPath).Code)
+	assert.Equal(t, http.StatusOK, getTokenStatus(t, http.DefaultClient, serverURL))
+
+	assert.NoError(t, <-done)
+	_, err := http.Get(serverURL + TokenPath)
+	assert.Error(t, err)
+}
+
+func TestServerShutdownReportsTokenFailures(t *testing.T) {
+	providerErr := errors.New("no credentials")
+	server := NewServer(&countingTokenProvider{err: providerErr})
+	srv, _ := startServer(t, server)
+	getToken(server, nil)
+
+	err := server.Shutdown(context.Background(), srv)
+
+	assert.ErrorIs(t, err, ErrTokenFailures)
+	assert.ErrorIs(t, err, providerErr)
+}
+
+func TestServerShutdownIgnoresRecoveredTokenFailures(t *testing.T) {
+	provider := &countingTokenProvider{err: errors.New("no credentials")}
+	server := NewServer(provider)
+	srv, _ := startServer(t, server)
+	getToken(server, nil)
+	provider.err = nil
+	getToken(server, nil)
+
+	assert.NoError(t, server.Shutdown(context.Background(), srv))
+}
+
+func TestServerShutdownStopsDrainOnContextDone(t *testing.T) {
+	server := NewServer(&countingTokenProvider{}, func(o *Options) {
+		o.DrainWindow = time.Hour
+	})
+	srv, _ := startServer(t, server)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_ = server.Shutdown(ctx, srv)
+
+	assert.Less(t, time.Since(start), time.Minute)
+}
@@ -0,0 +1,165 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// ClusterType is the type of MSK cluster a broker belongs to.
+type ClusterType int
+
+const (
+	// ClusterTypeProvisioned is a provisioned MSK cluster, with b-N broker hosts under kafka.<region>.
+	ClusterTypeProvisioned ClusterType = iota + 1
+
+	// ClusterTypeServerless is an MSK Serverless cluster, with boot- bootstrap hosts under kafka-serverless.<region>.
+	ClusterTypeServerless
+)
+
+const (
+	IAMPort       = "9098" // IAMPort is the port brokers accept IAM authenticated clients on within the VPC.
+	PublicIAMPort = "9198" // PublicIAMPort is the port brokers accept IAM authenticated clients on over public access.
+)
+
+// String returns the cluster type name.
+func (t ClusterType) String() string {
+	switch t {
+	case ClusterTypeProvisioned:
+		return "provisioned"
+	case ClusterTypeServerless:
+		return "serverless"
+	default:
+		return fmt.Sprintf("ClusterType(%d)", int(t))
+	}
+}
+
+// BrokerEndpoint is an MSK broker address parsed into its parts.
+type BrokerEndpoint struct {
+	// Host is the broker host name.
+	Host string
+
+	// Port is the broker port, empty when the address has none.
+	Port string
+
+	// Region is the region of the cluster, derived from the host name.
+	Region string
+
+	// ClusterType is the type of cluster the broker belongs to.
+	ClusterType ClusterType
+}
+
+// ParseBrokerEndpoint parses an MSK broker address, such as
+// b-1.mycluster.abc123.c2.kafka.us-east-1.amazonaws.com:9098 for provisioned clusters or
+// boot-abc123.c1.kafka-serverless.us-east-1.amazonaws.com:9098 for serverless ones, and validates that its port
+// accepts IAM authentication. The host must end in the DNS suffix of the partition of its region, e.g.
+// amazonaws.com.cn for the China regions.
+func ParseBrokerEndpoint(address string) (BrokerEndpoint, error) {
+	address = strings.TrimSpace(address)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = address, ""
+	}
+
+	endpoint := BrokerEndpoint{Host: host, Port: port}
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+	for i := 0; i+2 < len(labels); i++ {
+		if labels[i] != "kafka" && labels[i] != "kafka-serverless" {
+			continue
+		}
+		if strings.Join(labels[i+2:], ".") != dnsSuffix(labels[i+1]) {
+			continue
+		}
+		endpoint.Region = labels[i+1]
+		endpoint.ClusterType = ClusterTypeProvisioned
+		if labels[i] == "kafka-serverless" {
+			endpoint.ClusterType = ClusterTypeServerless
+		}
+		break
+	}
+	if endpoint.Region == "" {
+		return BrokerEndpoint{}, fmt.Errorf("%s is not an msk broker address", address)
+	}
+
+	if err := validateIAMPort(endpoint); err != nil {
+		return BrokerEndpoint{}, fmt.Errorf("invalid broker address %s: %w", address, err)
+	}
+	return endpoint, nil
+}
+
+// Validates that the port of the broker accepts IAM authentication.
+func validateIAMPort(endpoint BrokerEndpoint) error {
+	switch {
+	case endpoint.Port == "", endpoint.Port == IAMPort:
+		return nil
+	case endpoint.ClusterType == ClusterTypeServerless:
+		return fmt.Errorf("msk serverless clusters accept iam authentication on port %s only, not %s",
+			IAMPort, endpoint.Port)
+	case endpoint.Port == PublicIAMPort:
+		return nil
+	default:
+		return fmt.Errorf("port %s does not accept iam authentication, use %s or %s for public access",
+			endpoint.Port, IAMPort, PublicIAMPort)
+	}
+}
+
+// ParseBootstrapBrokers parses a comma separated MSK bootstrap broker string, as returned by GetBootstrapBrokers, and
+// validates that all brokers belong to the same region and type of cluster.
+func ParseBootstrapBrokers(bootstrapBrokers string) ([]BrokerEndpoint, error) {
+	var endpoints []BrokerEndpoint
+	for _, address := range strings.Split(bootstrapBrokers, ",") {
+		if strings.TrimSpace(address) == "" {
+			continue
+		}
+
+		endpoint, err := ParseBrokerEndpoint(address)
+		if err != nil {
+			return nil, err
+		}
+		if len(endpoints) > 0 && (endpoint.Region != endpoints[0].Region ||
+			endpoint.ClusterType != endpoints[0].ClusterType) {
+			return nil, fmt.Errorf("bootstrap brokers %s and %s belong to different clusters",
+				endpoints[0].Host, endpoint.Host)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if len(endpoints) == 0 {
+		return nil, errors.New("bootstrap brokers cannot be empty")
+	}
+	return endpoints, nil
+}
+
+// RegionFromBootstrapBrokers returns the region of the cluster of the bootstrap brokers, provisioned or serverless.
+func RegionFromBootstrapBrokers(bootstrapBrokers string) (string, error) {
+	endpoints, err := ParseBootstrapBrokers(bootstrapBrokers)
+	if err != nil {
+		return "", err
+	}
+	return endpoints[0].Region, nil
+}
+
+// RegionFromClusterARN returns the region of the provisioned or serverless MSK cluster identified by the ARN.
+func RegionFromClusterARN(clusterARN string) (string, error) {
+	parsed, err := parseClusterARN(clusterARN)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Region, nil
+}
+
+// Parses the ARN, validating that it identifies an MSK cluster.
+func parseClusterARN(clusterARN string) (arn.ARN, error) {
+	parsed, err := arn.Parse(clusterARN)
+	if err != nil {
+		return arn.ARN{}, fmt.Errorf("invalid cluster arn %s: %w", clusterARN, err)
+	}
+
+	if parsed.Service != "kafka" || !strings.HasPrefix(parsed.Resource, "cluster/") {
+		return arn.ARN{}, fmt.Errorf("arn %s does not identify an msk cluster", clusterARN)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,89 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testProvisionedBrokers = "b-1.demo.abc123.c2.kafka.us-west-2.amazonaws.com:9098," +
+		"b-2.demo.abc123.c2.kafka.us-west-2.amazonaws.com:9098"
+	testServerlessBroker = "boot-abc123.c1.kafka-serverless.eu-west-1.amazonaws.com:9098"
+)
+
+func TestParseBrokerEndpointProvisioned(t *testing.T) {
+	endpoint, err := ParseBrokerEndpoint("b-1-public.demo.abc123.c2.kafka.cn-north-1.amazonaws.com.cn:9198")
+
+	assert.NoError(t, err)
+	assert.Equal(t, BrokerEndpoint{
+		Host:        "b-1-public.demo.abc123.c2.kafka.cn-north-1.amazonaws.com.cn",
+		Port:        PublicIAMPort,
+		Region:      "cn-north-1",
+		ClusterType: ClusterTypeProvisioned,
+	}, endpoint)
+}
+
+func TestParseBrokerEndpointServerless(t *testing.T) {
+	endpoint, err := ParseBrokerEndpoint(testServerlessBroker)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", endpoint.Region)
+	assert.Equal(t, IAMPort, endpoint.Port)
+	assert.Equal(t, ClusterTypeServerless, endpoint.ClusterType)
+	assert.Equal(t, "serverless", endpoint.ClusterType.String())
+}
+
+func TestParseBrokerEndpointWithoutPort(t *testing.T) {
+	endpoint, err := ParseBrokerEndpoint("boot-abc123.c1.kafka-serverless.eu-west-1.amazonaws.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", endpoint.Port)
+	assert.Equal(t, "eu-west-1", endpoint.Region)
+}
+
+func TestParseBrokerEndpointRejectsNonIAMPort(t *testing.T) {
+	_, err := ParseBrokerEndpoint("b-1.demo.abc123.c2.kafka.us-west-2.amazonaws.com:9094")
+	assert.ErrorContains(t, err, "port 9094 does not accept iam authentication")
+
+	_, err = ParseBrokerEndpoint("boot-abc123.c1.kafka-serverless.eu-west-1.amazonaws.com:9198")
+	assert.ErrorContains(t, err, "msk serverless clusters accept iam authentication on port 9098 only")
+}
+
+func TestParseBrokerEndpointRejectsNonMSKHost(t *testing.T) {
+	_, err := ParseBrokerEndpoint("localhost:9092")
+	assert.ErrorContains(t, err, "localhost:9092 is not an msk broker address")
+}
+
+func TestParseBootstrapBrokers(t *testing.T) {
+	endpoints, err := ParseBootstrapBrokers(testProvisionedBrokers + ",")
+
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 2)
+	assert.Equal(t, "b-2.demo.abc123.c2.kafka.us-west-2.amazonaws.com", endpoints[1].Host)
+}
+
+func TestParseBootstrapBrokersRejectsMixedClusters(t *testing.T) {
+	_, err := ParseBootstrapBrokers(testProvisionedBrokers + "," + testServerlessBroker)
+	assert.ErrorContains(t, err, "belong to different clusters")
+
+	_, err = ParseBootstrapBrokers(" , ")
+	assert.ErrorContains(t, err, "bootstrap brokers cannot be empty")
+}
+
+func TestRegionFromBootstrapBrokers(t *testing.T) {
+	region, err := RegionFromBootstrapBrokers(testServerlessBroker)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", region)
+}
+
+func TestRegionFromClusterARN(t *testing.T) {
+	region, err := RegionFromClusterARN(
+		"arn:aws:kafka:eu-west-1:123456789012:cluster/serverless-demo/7e4f5a8b-1234-5678-9abc-def012345678-s1")
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", region)
+
+	_, err = RegionFromClusterARN("arn:aws:kafka:eu-west-1:123456789012:topic/demo/abc/orders")
+	assert.ErrorContains(t, err, "does not identify an msk cluster")
+}
@@ -0,0 +1,76 @@
+package signer
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Encodes the query parameters canonically, so that tokens are byte-comparable whatever the Go version: parameters
+// are sorted by key, then by value, and keys and values are percent-encoded per RFC 3986, leaving only the unreserved
+// characters A-Z, a-z, 0-9, '-', '.', '_' and '~' as is, with uppercase hex digits. This is the canonical query
+// string of aws sig v4, so re-encoding a presigned url does not invalidate its signature.
+func canonicalQueryString(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(percentEncode(key))
+			sb.WriteByte('=')
+			sb.WriteString(percentEncode(value))
+		}
+	}
+	return sb.String()
+}
+
+// Re-encodes the query of the url canonically.
+func canonicalizeURL(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse signed url: %w", err)
+	}
+
+	parsedURL.RawQuery = canonicalQueryString(parsedURL.Query())
+	return parsedURL.String(), nil
+}
+
+// Percent-encodes every byte of s but the RFC 3986 unreserved characters.
+func percentEncode(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreserved(c) {
+			sb.WriteByte(c)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hex[c>>4])
+		sb.WriteByte(hex[c&0xf])
+	}
+	return sb.String()
+}
+
+// Reports whether the byte is an RFC 3986 unreserved character.
+func isUnreserved(c byte) bool {
+	switch {
+	case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		return true
+	case c == '-', c == '.', c == '_', c == '~':
+		return true
+	default:
+		return false
+	}
+}
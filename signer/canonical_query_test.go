@@ -0,0 +1,74 @@
+package signer
+
+import (
+	"encoding/base64"
+	"net/url"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalQueryString(t *testing.T) {
+	query := url.Values{
+		"b":           {"2", "1"},
+		"a":           {"x y"},
+		"Action":      {"kafka-cluster:Connect"},
+		"unreserved":  {"AZaz09-._~"},
+		"reserved":    {"!*'();:@&=+$,/?#[]"},
+		"utf8":        {"é"},
+		"X-Amz-Token": {"abc/def+ghi="},
+	}
+
+	assert.Equal(t,
+		"Action=kafka-cluster%3AConnect&X-Amz-Token=abc%2Fdef%2Bghi%3D&a=x%20y&b=1&b=2"+
+			"&reserved=%21%2A%27%28%29%3B%3A%40%26%3D%2B%24%2C%2F%3F%23%5B%5D&unreserved=AZaz09-._~&utf8=%C3%A9",
+		canonicalQueryString(query))
+}
+
+func TestEncodeAuthTokenIsIndependentOfQueryOrder(t *testing.T) {
+	profile, err := resolveCompatibilityProfile(Options{})
+	assert.NoError(t, err)
+	ordered := "https://kafka.us-west-2.amazonaws.com/?Action=kafka-cluster%3AConnect&X-Amz-Date=20240101T000000Z" +
+		"&X-Amz-Expires=900&X-Amz-Signature=abc"
+	shuffled := "https://kafka.us-west-2.amazonaws.com/?X-Amz-Signature=abc&X-Amz-Expires=900" +
+		"&Action=kafka-cluster:Connect&X-Amz-Date=20240101T000000Z"
+
+	orderedToken, _, err := encodeAuthToken(ordered, profile, Options{TokenEncoding: TokenEncodingRawURL})
+	assert.NoError(t, err)
+	shuffledToken, _, err := encodeAuthToken(shuffled, profile, Options{TokenEncoding: TokenEncodingRawURL})
+	assert.NoError(t, err)
+
+	assert.Equal(t, orderedToken, shuffledToken)
+}
+
+func TestEncodeAuthTokenGolden(t *testing.T) {
+	profile := CompatibilityProfile{
+		ActionKey: ActionType, ActionName: ActionName, ExpiresKey: ExpiresQueryKey, OmitUserAgent: true,
+	}
+	signedURL := "https://kafka.us-west-2.amazonaws.com/?X-Amz-Signature=abc&X-Amz-Expires=900" +
+		"&Action=kafka-cluster:Connect&X-Amz-Date=20240101T000000Z"
+
+	token, expirationTimeMs, err := encodeAuthToken(signedURL, profile, Options{TokenEncoding: TokenEncodingRawURL})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1704068100000), expirationTimeMs)
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://kafka.us-west-2.amazonaws.com/?Action=kafka-cluster%3AConnect"+
+		"&X-Amz-Date=20240101T000000Z&X-Amz-Expires=900&X-Amz-Signature=abc", string(decoded))
+}
+
+func TestGeneratedTokenQueryIsCanonical(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithSignedQueryParameter("zz", "b"), WithSignedQueryParameter("zz", "a b"))
+	assert.NoError(t, err)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	assert.NoError(t, err)
+	parsedURL, err := url.Parse(string(decoded))
+	assert.NoError(t, err)
+
+	assert.Equal(t, canonicalQueryString(parsedURL.Query()), parsedURL.RawQuery)
+	assert.Contains(t, parsedURL.RawQuery, "User-Agent="+LibName+"%2F"+version+"%2F"+url.QueryEscape(runtime.Version()))
+}
@@ -0,0 +1,259 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	// DefaultMaxClockSkew is the difference between the system clock and the trusted time above which the clock skew
+	// policy applies when no maximum is set.
+	DefaultMaxClockSkew = 5 * time.Minute
+
+	// Longest lifetime of the temporary credentials issued by sts, by GetSessionToken. Credentials expiring later are
+	// evidence that the system clock is behind the time they were issued at.
+	maxSTSCredentialLifetime = 36 * time.Hour
+
+	// Timeout of the requests of the default time source.
+	timeSourceTimeout = 5 * time.Second
+
+	// How long the time read from the default time source is reused before it is read again.
+	trustedTimeTTL = 15 * time.Minute
+)
+
+// Earliest plausible system time. A clock before it has lost its time, e.g. a board without a real time clock
+// booted before NTP synchronization.
+var minPlausibleTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrClockSkew is returned when the system clock is too far off the actual time for brokers to accept the tokens it
+// signs.
+var ErrClockSkew = errors.New("system clock is skewed")
+
+// ClockSkewPolicy decides what happens when the system clock is detected to be grossly wrong before signing.
+type ClockSkewPolicy int
+
+const (
+	// ClockSkewIgnore signs with the system clock without checking it. This is the default.
+	ClockSkewIgnore ClockSkewPolicy = iota
+
+	// ClockSkewFail fails token generation with ErrClockSkew when the system clock is grossly wrong.
+	ClockSkewFail
+
+	// ClockSkewCorrect signs with the time of the trusted time source when the system clock is off it by more than
+	// the maximum skew. Token generation fails with ErrClockSkew when the clock is grossly wrong and the time source
+	// cannot be reached.
+	ClockSkewCorrect
+)
+
+// Clock returns the current time tokens are signed at, the wall clock by default. It is read once per token.
+type Clock func() time.Time
+
+// TimeSource returns the current time from a source trusted over the system clock.
+type TimeSource func(ctx context.Context) (time.Time, error)
+
+// HTTPDateTimeSource returns a time source reading the Date header of the response to a HEAD request to the url, with
+// the client or http.DefaultClient when nil. Any response carrying the header is accepted, the request needs no
+// authentication. The header has a precision of one second.
+func HTTPDateTimeSource(client aws.HTTPClient, url string) TimeSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) (time.Time, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to build time request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to request time from %s: %w", url, err)
+		}
+		_ = resp.Body.Close()
+
+		date, err := http.ParseTime(resp.Header.Get("Date"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("no valid Date header in the response of %s: %w", url, err)
+		}
+		return date, nil
+	}
+}
+
+// Environment variables the sts endpoint is resolved from, in addition to the options.
+var stsEndpointEnvVars = [...]string{
+	"AWS_ENDPOINT_URL_STS", "AWS_ENDPOINT_URL", "AWS_IGNORE_CONFIGURED_ENDPOINT_URLS", FIPSEndpointEnvVar,
+	"AWS_USE_DUALSTACK_ENDPOINT", "AWS_PROFILE", "AWS_CONFIG_FILE",
+}
+
+// Identifies the sts endpoint used as default time source by what it is resolved from: the sts region, the settings of
+// the options and the environment.
+type stsEndpointKey struct {
+	region              string
+	endpoint            string
+	fips                aws.FIPSEndpointState
+	disableSharedConfig bool
+	environment         [len(stsEndpointEnvVars)]string
+}
+
+// Returns the key of the sts endpoint of the region and options.
+func newSTSEndpointKey(region string, options Options) stsEndpointKey {
+	key := stsEndpointKey{
+		region:              stsRegion(region, options),
+		endpoint:            options.STSEndpoint,
+		fips:                fipsEndpointState(options),
+		disableSharedConfig: options.DisableSharedConfig,
+	}
+	for i, name := range stsEndpointEnvVars {
+		key.environment[i] = os.Getenv(name)
+	}
+	return key
+}
+
+// Trusted times read from the sts endpoints used as default time source, by endpoint.
+var stsTrustedTimes = &trustedTimeCache{times: map[stsEndpointKey]trustedTimeReading{}}
+
+// A trusted time along with the time it was read at, which carries a monotonic clock reading so that the time elapsed
+// since is not affected by steps of the system clock, e.g. NTP corrections.
+type trustedTimeReading struct {
+	trusted time.Time
+	readAt  time.Time
+}
+
+// Keeps the trusted times read from time sources, so that neither the sts endpoint is resolved nor the trusted time
+// requested for every token.
+type trustedTimeCache struct {
+	mu    sync.Mutex
+	times map[stsEndpointKey]trustedTimeReading
+}
+
+// Returns the current trusted time, advanced from the time read from the endpoint less than trustedTimeTTL ago by
+// the monotonic time elapsed since.
+func (c *trustedTimeCache) get(key stsEndpointKey) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reading, ok := c.times[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	elapsed := time.Since(reading.readAt)
+	if elapsed < 0 || elapsed >= trustedTimeTTL {
+		return time.Time{}, false
+	}
+	return reading.trusted.Add(elapsed), true
+}
+
+// Keeps the trusted time read from the endpoint at readAt, a time.Now reading.
+func (c *trustedTimeCache) put(key stsEndpointKey, trusted time.Time, readAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.times[key] = trustedTimeReading{trusted: trusted, readAt: readAt}
+}
+
+// Returns the trusted time from the time source of the options, or else from the Date header of the sts endpoint
+// roles are assumed with, which is reachable wherever credentials can be retrieved. The time read from the sts
+// endpoint is reused for trustedTimeTTL, advanced by the monotonic time elapsed since it was read.
+func trustedTime(ctx context.Context, region string, options Options) (time.Time, error) {
+	if options.TimeSource != nil {
+		return options.TimeSource(ctx)
+	}
+
+	key := newSTSEndpointKey(region, options)
+	if trusted, ok := stsTrustedTimes.get(key); ok {
+		return trusted, nil
+	}
+
+	url, err := stsEndpointURL(ctx, region, options)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	readAt := time.Now()
+	trusted, err := HTTPDateTimeSource(newCredentialsHTTPClient(options, timeSourceTimeout), url)(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	stsTrustedTimes.put(key, trusted, readAt)
+	return trusted, nil
+}
+
+// Returns the url of the sts endpoint roles are assumed with for the region, resolved as the sts client resolves it:
+// the sts endpoint of the options, the endpoint url of the environment or shared config, else the regional or FIPS
+// endpoint of the sts region.
+func stsEndpointURL(ctx context.Context, region string, options Options) (string, error) {
+	cfg, err := loadConfig(ctx, stsRegion(region, options), options)
+	if err != nil {
+		return "", fmt.Errorf("unable to load SDK config: %w", err)
+	}
+
+	o := sts.NewFromConfig(cfg, roleSTSOptionFns(options)...).Options()
+	endpoint, err := o.EndpointResolverV2.ResolveEndpoint(ctx, sts.EndpointParameters{
+		Region:       aws.String(o.Region),
+		Endpoint:     o.BaseEndpoint,
+		UseFIPS:      aws.Bool(o.EndpointOptions.UseFIPSEndpoint == aws.FIPSEndpointStateEnabled),
+		UseDualStack: aws.Bool(o.EndpointOptions.UseDualStackEndpoint == aws.DualStackEndpointStateEnabled),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the sts endpoint: %w", err)
+	}
+	return endpoint.URI.String(), nil
+}
+
+// Returns the time to sign with under the clock skew policy: the time of the clock of the options, or the system time,
+// unless it is off the trusted time by more than the maximum skew and the policy corrects it.
+func resolveSigningTime(
+	ctx context.Context, region string, credentials *aws.Credentials, options Options,
+) (time.Time, error) {
+	now := time.Now()
+	if options.Clock != nil {
+		now = options.Clock()
+	}
+	now = now.UTC()
+	if options.ClockSkewPolicy == ClockSkewIgnore {
+		return now, nil
+	}
+
+	maxSkew := options.MaxClockSkew
+	if maxSkew <= 0 {
+		maxSkew = DefaultMaxClockSkew
+	}
+
+	trusted, err := trustedTime(ctx, region, options)
+	if err != nil {
+		if skewErr := checkClockPlausible(now, credentials); skewErr != nil {
+			return time.Time{}, fmt.Errorf("%w, and the time source cannot be reached: %w", skewErr, err)
+		}
+		return now, nil
+	}
+
+	skew := now.Sub(trusted)
+	if skew.Abs() <= maxSkew {
+		return now, nil
+	}
+	if options.ClockSkewPolicy == ClockSkewCorrect {
+		return trusted.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: system clock is %s off the trusted time %s, more than %s", ErrClockSkew,
+		skew.Round(time.Second), trusted.UTC().Format(time.RFC3339), maxSkew)
+}
+
+// Checks the system time for gross errors detectable without a trusted time: a time before the signer was released,
+// or credentials expiring later than sts issues them for, meaning they were issued in the future of the clock.
+func checkClockPlausible(now time.Time, credentials *aws.Credentials) error {
+	if now.Before(minPlausibleTime) {
+		return fmt.Errorf("%w: system time %s is before %s", ErrClockSkew, now.Format(time.RFC3339),
+			minPlausibleTime.Format(time.DateOnly))
+	}
+	if credentials.CanExpire && credentials.Expires.Sub(now) > maxSTSCredentialLifetime {
+		return fmt.Errorf("%w: credentials expire at %s, more than %s after the system time %s", ErrClockSkew,
+			credentials.Expires.UTC().Format(time.RFC3339), maxSTSCredentialLifetime, now.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a time source reporting the system time shifted by the offset, counting its calls.
+func shiftedTimeSource(offset time.Duration, calls *int) TimeSource {
+	return func(ctx context.Context) (time.Time, error) {
+		*calls++
+		return time.Now().Add(offset), nil
+	}
+}
+
+// Fails to supply the time.
+func unreachableTimeSource(ctx context.Context) (time.Time, error) {
+	return time.Time{}, errors.New("no route to host")
+}
+
+func TestClockSkewIgnoredByDefault(t *testing.T) {
+	calls := 0
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithTimeSource(shiftedTimeSource(time.Hour, &calls)))
+
+	assert.NoError(t, err)
+	assert.Zero(t, calls)
+}
+
+func TestClockSkewFail(t *testing.T) {
+	calls := 0
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithClockSkewPolicy(ClockSkewFail, 0), WithTimeSource(shiftedTimeSource(-time.Hour, &calls)))
+
+	assert.ErrorIs(t, err, ErrClockSkew)
+	assert.ErrorContains(t, err, "system clock is 1h0m0s off the trusted time")
+	assert.Equal(t, 1, calls)
+}
+
+func TestClockSkewWithinMaxSkew(t *testing.T) {
+	calls := 0
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithClockSkewPolicy(ClockSkewFail, 2*time.Hour), WithTimeSource(shiftedTimeSource(-time.Hour, &calls)))
+
+	assert.NoError(t, err)
+	signingTime, err := time.Parse(SigningTimeFormat, QueryKeyDate.Get(decodeTokenParams(t, token)))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), signingTime, time.Minute)
+}
+
+func TestClockSkewCorrect(t *testing.T) {
+	calls := 0
+	token, expiryMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithClockSkewPolicy(ClockSkewCorrect, 0), WithTimeSource(shiftedTimeSource(-2*time.Hour, &calls)))
+
+	assert.NoError(t, err)
+	signingTime, err := time.Parse(SigningTimeFormat, QueryKeyDate.Get(decodeTokenParams(t, token)))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-2*time.Hour), signingTime, time.Minute)
+	assert.Equal(t, signingTime.Add(DefaultExpirySeconds*time.Second).UnixMilli(), expiryMs)
+}
+
+func TestClockSkewCorrectWithUnreachableTimeSource(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithClockSkewPolicy(ClockSkewCorrect, 0), WithTimeSource(unreachableTimeSource))
+	assert.NoError(t, err)
+
+	futureCredentials := MockCredentialsProvider{credentials: expiringCredentials("TEST-KEY", 48*time.Hour)}
+	_, _, err = GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, futureCredentials,
+		WithClockSkewPolicy(ClockSkewCorrect, 0), WithTimeSource(unreachableTimeSource))
+	assert.ErrorIs(t, err, ErrClockSkew)
+	assert.ErrorContains(t, err, "more than 36h0m0s after the system time")
+	assert.ErrorContains(t, err, "the time source cannot be reached: no route to host")
+}
+
+func TestCheckClockPlausible(t *testing.T) {
+	credentials := &aws.Credentials{AccessKeyID: "TEST-KEY"}
+
+	assert.NoError(t, checkClockPlausible(time.Now(), credentials))
+	assert.ErrorIs(t, checkClockPlausible(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), credentials),
+		ErrClockSkew)
+}
+
+func TestHTTPDateTimeSource(t *testing.T) {
+	date := time.Date(2030, time.June, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		w.Header().Set("Date", date.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	trusted, err := HTTPDateTimeSource(nil, server.URL)(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, date, trusted.UTC())
+}
+
+func TestGenerateAuthTokenWithClock(t *testing.T) {
+	signingTime := time.Date(2099, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return signingTime.In(time.FixedZone("UTC+2", 2*60*60)) }
+
+	token, expiryMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithClock(clock))
+	assert.NoError(t, err)
+	again, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithClock(clock))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "20990102T030405Z", QueryKeyDate.Get(decodeTokenParams(t, token)))
+	assert.Equal(t, signingTime.Add(DefaultExpirySeconds*time.Second).UnixMilli(), expiryMs)
+	assert.Equal(t, token, again)
+}
+
+func TestSTSEndpointURL(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+
+	for _, tc := range []struct {
+		name   string
+		optFns []Option
+		want   string
+	}{
+		{"regional", nil, "https://sts.us-west-2.amazonaws.com"},
+		{"fips", []Option{WithFIPSEndpoint(true)}, "https://sts-fips.us-west-2.amazonaws.com"},
+		{"sts endpoint", []Option{WithSTSRegion("", "https://vpce.sts.internal")}, "https://vpce.sts.internal"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			url, err := stsEndpointURL(Ctx, TestRegion, resolveOptions(tc.optFns))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, url)
+		})
+	}
+}
+
+func TestSTSEndpointURLFromEnvironment(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "https://sts.proxy.internal")
+
+	url, err := stsEndpointURL(Ctx, TestRegion, resolveOptions(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://sts.proxy.internal", url)
+}
+
+func TestClockSkewCachesSTSTrustedTime(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Date", time.Now().Add(-2*time.Hour).UTC().Format(http.TimeFormat))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+
+	for i := 0; i < 2; i++ {
+		token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+			WithClockSkewPolicy(ClockSkewCorrect, 0))
+
+		assert.NoError(t, err)
+		signingTime, err := time.Parse(SigningTimeFormat, QueryKeyDate.Get(decodeTokenParams(t, token)))
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(-2*time.Hour), signingTime, time.Minute)
+	}
+	assert.Equal(t, int64(1), requests.Load())
+}
+
+func TestTrustedTimeCacheAdvancesByElapsedTime(t *testing.T) {
+	cache := &trustedTimeCache{times: map[stsEndpointKey]trustedTimeReading{}}
+	key := newSTSEndpointKey(TestRegion, resolveOptions(nil))
+	trusted := time.Date(2030, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	cache.put(key, trusted, time.Now().Add(-time.Minute))
+	got, ok := cache.get(key)
+	assert.True(t, ok)
+	assert.WithinDuration(t, trusted.Add(time.Minute), got, time.Second)
+
+	cache.put(key, trusted, time.Now().Add(-trustedTimeTTL))
+	_, ok = cache.get(key)
+	assert.False(t, ok)
+}
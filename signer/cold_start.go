@@ -0,0 +1,169 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// DefaultColdStartDeadline is how long a Provider with a cold start store waits for its first token by default.
+const DefaultColdStartDeadline = 2 * time.Second
+
+// ErrColdStartDeadline is returned when the first token of a Provider with a cold start store could not be generated
+// within the cold start deadline and no persisted token could be served instead.
+var ErrColdStartDeadline = errors.New("cold start deadline exceeded")
+
+// ColdStartState is the state a Provider persists to its cold start store after every token generation, so the next
+// instance of a scale-to-zero consumer can serve the token, or sign a new one without retrieving credentials again.
+type ColdStartState struct {
+	// Token is the last generated token.
+	Token *Token `json:"token"`
+
+	// IssuedAt is when generation of the token started, which its refresh time is computed from.
+	IssuedAt time.Time `json:"issuedAt"`
+
+	// Credentials are the cached temporary credentials the token was signed with. Credentials that cannot expire are
+	// never persisted.
+	Credentials *aws.Credentials `json:"credentials,omitempty"`
+}
+
+// ColdStartStore persists the state of a Provider across cold starts, e.g. in a volume or a cache shared by the
+// instances of a consumer. The state holds temporary credentials, so the store must be protected accordingly.
+type ColdStartStore interface {
+	// Load returns the persisted state, or nil when none is persisted.
+	Load(ctx context.Context) (*ColdStartState, error)
+
+	// Save persists the state, replacing the persisted one.
+	Save(ctx context.Context, state *ColdStartState) error
+}
+
+// FileColdStartStore is a ColdStartStore persisting the state as JSON in a file only readable by its owner.
+type FileColdStartStore struct {
+	// Path of the state file.
+	Path string
+}
+
+// Load reads the state file, returning nil when it does not exist.
+func (s FileColdStartStore) Load(ctx context.Context) (*ColdStartState, error) {
+	content, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cold start state: %w", err)
+	}
+
+	var state ColdStartState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse cold start state %s: %w", s.Path, err)
+	}
+	return &state, nil
+}
+
+// Save replaces the state file atomically, so a concurrent Load never reads a partial state.
+func (s FileColdStartStore) Save(ctx context.Context, state *ColdStartState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode cold start state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), "."+filepath.Base(s.Path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cold start state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write cold start state %s: %w", s.Path, err)
+	}
+	return nil
+}
+
+// Restores the token and credentials persisted in the cold start store on the first call, if any. Tokens of another
+// region or that expired are discarded, and so are credentials about to expire. Load failures are logged and treated
+// as an empty store. The caller holds the token generation.
+func (p *Provider) restoreColdStart(ctx context.Context) {
+	if p.options.ColdStartStore == nil || p.coldStartRestored {
+		return
+	}
+	p.coldStartRestored = true
+
+	state, err := p.options.ColdStartStore.Load(ctx)
+	if err != nil {
+		logColdStartStoreFailed(ctx, p.options.Logger, "load", err)
+		return
+	}
+	if state == nil {
+		return
+	}
+
+	if state.Credentials != nil {
+		p.credentials.seed(state.Credentials)
+	}
+	token := state.Token
+	if token == nil || token.Region != p.region || !time.Now().Before(token.Expiry(0)) {
+		return
+	}
+	refreshAt := p.options.RefreshStrategy.RefreshAt(state.IssuedAt, token)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.token, p.restored, p.refreshAt = token, token, refreshAt
+}
+
+// Persists the token generated starting at issuedAt to the cold start store, if any, along with the cached
+// credentials. Save failures are logged.
+func (p *Provider) saveColdStart(ctx context.Context, issuedAt time.Time, token *Token) {
+	if p.options.ColdStartStore == nil {
+		return
+	}
+
+	state := &ColdStartState{Token: token, IssuedAt: issuedAt, Credentials: p.credentials.cached()}
+	if err := p.options.ColdStartStore.Save(ctx, state); err != nil {
+		logColdStartStoreFailed(ctx, p.options.Logger, "save", err)
+	}
+}
+
+// Generates and caches a new token. Until a provider with a cold start store generated its first token, generation is
+// bounded by the cold start deadline, failing with ErrColdStartDeadline once it elapsed. The caller holds the token
+// generation.
+func (p *Provider) newColdStartToken(ctx context.Context) (*Token, error) {
+	p.mu.Lock()
+	refreshed := p.stats.Refreshes > 0
+	p.mu.Unlock()
+	if p.options.ColdStartStore == nil || refreshed {
+		return p.newToken(ctx, p.loadCredentials)
+	}
+
+	deadline := p.options.ColdStartDeadline
+	if deadline <= 0 {
+		deadline = DefaultColdStartDeadline
+	}
+	deadlineCtx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	token, err := p.newToken(deadlineCtx, p.loadCredentials)
+	if err != nil && deadlineCtx.Err() != nil && ctx.Err() == nil {
+		err = fmt.Errorf("%w after %s: %w", ErrColdStartDeadline, deadline, err)
+	}
+	return token, err
+}
+
+// Reports whether the cached token is a restored token that has not expired, which is served when generating a new
+// one fails. The caller holds p.mu.
+func (p *Provider) servesRestoredTokenLocked() bool {
+	return p.token != nil && p.token == p.restored && time.Now().Before(p.token.Expiry(0))
+}
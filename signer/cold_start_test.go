@@ -0,0 +1,160 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Credentials provider blocking until the context is done.
+type blockingCredentialsProvider struct{}
+
+func (blockingCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	<-ctx.Done()
+	return aws.Credentials{}, ctx.Err()
+}
+
+// Returns a cold start store in a temporary directory.
+func newTestColdStartStore(t *testing.T) FileColdStartStore {
+	return FileColdStartStore{Path: filepath.Join(t.TempDir(), "cold-start.json")}
+}
+
+// Returns a token for the test region expiring in expiresIn.
+func coldStartToken(value string, expiresIn time.Duration) *Token {
+	return &Token{Value: value, ExpirationTimeMs: time.Now().Add(expiresIn).UnixMilli(), Region: TestRegion}
+}
+
+func TestColdStartRestoresPersistedToken(t *testing.T) {
+	store := newTestColdStartStore(t)
+	first := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-COLD-START-KEY", time.Hour)},
+	}
+	token, err := NewProvider(TestRegion, first, WithColdStart(store, 0)).Token(Ctx)
+	assert.NoError(t, err)
+
+	second := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-OTHER-KEY", time.Hour)},
+	}
+	restored, err := NewProvider(TestRegion, second, WithColdStart(store, 0)).Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, token.Value, restored.Value)
+	assert.Equal(t, 0, second.calls)
+}
+
+func TestColdStartSignsWithRestoredCredentials(t *testing.T) {
+	store := newTestColdStartStore(t)
+	credentials := expiringCredentials("TEST-RESTORED-KEY", time.Hour)
+	assert.NoError(t, store.Save(Ctx, &ColdStartState{
+		Token:       coldStartToken("stale-token", time.Minute),
+		IssuedAt:    time.Now().Add(-14 * time.Minute),
+		Credentials: &credentials,
+	}))
+	credentialsProvider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-OTHER-KEY", time.Hour)},
+	}
+
+	token, err := NewProvider(TestRegion, credentialsProvider, WithColdStart(store, 0)).Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-RESTORED-KEY", token.KeyID)
+	assert.Equal(t, 0, credentialsProvider.calls)
+
+	state, err := store.Load(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, token.Value, state.Token.Value)
+}
+
+func TestColdStartDeadlineServesRestoredToken(t *testing.T) {
+	store := newTestColdStartStore(t)
+	assert.NoError(t, store.Save(Ctx, &ColdStartState{
+		Token:    coldStartToken("stale-token", time.Minute),
+		IssuedAt: time.Now().Add(-14 * time.Minute),
+	}))
+	provider := NewProvider(TestRegion, blockingCredentialsProvider{}, WithColdStart(store, 50*time.Millisecond))
+
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "stale-token", token.Value)
+	assert.Equal(t, int64(1), provider.Stats().StaleTokensServed)
+}
+
+func TestColdStartDeadlineWithoutRestoredToken(t *testing.T) {
+	provider := NewProvider(TestRegion, blockingCredentialsProvider{},
+		WithColdStart(newTestColdStartStore(t), 50*time.Millisecond))
+
+	start := time.Now()
+	_, err := provider.Token(Ctx)
+
+	assert.ErrorIs(t, err, ErrColdStartDeadline)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestColdStartDiscardsUnusableState(t *testing.T) {
+	tests := map[string]*ColdStartState{
+		"expired token": {Token: coldStartToken("expired-token", -time.Minute)},
+		"other region":  {Token: &Token{Value: "other-token", ExpirationTimeMs: time.Now().Add(time.Hour).UnixMilli()}},
+	}
+
+	for name, state := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := newTestColdStartStore(t)
+			assert.NoError(t, store.Save(Ctx, state))
+			provider, credentialsProvider := newCountingProvider(WithColdStart(store, 0))
+
+			token, err := provider.Token(Ctx)
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, state.Token.Value, token.Value)
+			assert.Equal(t, 1, credentialsProvider.calls)
+		})
+	}
+}
+
+func TestColdStartIgnoresCorruptState(t *testing.T) {
+	store := newTestColdStartStore(t)
+	assert.NoError(t, os.WriteFile(store.Path, []byte("{"), 0o600))
+	provider, _ := newCountingProvider(WithColdStart(store, 0))
+
+	_, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	state, err := store.Load(Ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, state.Token)
+}
+
+func TestColdStartDoesNotPersistStaticCredentials(t *testing.T) {
+	store := newTestColdStartStore(t)
+	provider, _ := newCountingProvider(WithColdStart(store, 0))
+
+	_, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	state, err := store.Load(Ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, state.Credentials)
+}
+
+func TestFileColdStartStore(t *testing.T) {
+	store := newTestColdStartStore(t)
+
+	state, err := store.Load(Ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+
+	assert.NoError(t, store.Save(Ctx, &ColdStartState{Token: coldStartToken("token", time.Hour)}))
+	info, err := os.Stat(store.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	err = FileColdStartStore{Path: filepath.Join(t.TempDir(), "missing", "state.json")}.Save(Ctx, &ColdStartState{})
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompatibilityProfile names the query parameters the signer manages itself, for MSK-compatible brokers, such as
+// self-managed Kafka with an IAM auth plugin, that expect different parameter names or casing. The zero value is the
+// MSK profile. The X-Amz-* parameters of aws sig v4 other than the expiry are not affected.
+type CompatibilityProfile struct {
+	// ActionKey is the query parameter holding the action. ActionType is used when empty.
+	ActionKey string
+
+	// ActionName is the action the token authorizes. ActionName is used when empty.
+	ActionName string
+
+	// ExpiresKey is the query parameter holding the token lifetime in seconds. ExpiresQueryKey is used when empty.
+	ExpiresKey string
+
+	// UserAgentKey is the query parameter holding the user agent. UserAgentKey is used when empty.
+	UserAgentKey string
+
+	// OmitUserAgent leaves the user agent out of the token.
+	OmitUserAgent bool
+}
+
+// Returns the profile with the MSK defaults applied, rejecting profiles whose parameter names clash.
+func resolveCompatibilityProfile(options Options) (CompatibilityProfile, error) {
+	profile := options.Compatibility
+	if profile.ActionKey == "" {
+		profile.ActionKey = ActionType
+	}
+	if profile.ActionName == "" {
+		profile.ActionName = ActionName
+	}
+	if profile.ExpiresKey == "" {
+		profile.ExpiresKey = ExpiresQueryKey
+	}
+	if profile.UserAgentKey == "" {
+		profile.UserAgentKey = UserAgentKey
+	}
+
+	keys := []string{profile.ActionKey, profile.ExpiresKey}
+	if !profile.OmitUserAgent {
+		keys = append(keys, profile.UserAgentKey)
+	}
+	for i, key := range keys {
+		for _, other := range keys[i+1:] {
+			if strings.EqualFold(key, other) {
+				return CompatibilityProfile{}, fmt.Errorf("invalid compatibility profile: query parameter %s is used twice",
+					key)
+			}
+		}
+	}
+	if isSigV4QueryKey(profile.ActionKey) || (!profile.OmitUserAgent && isSigV4QueryKey(profile.UserAgentKey)) {
+		return CompatibilityProfile{}, fmt.Errorf("invalid compatibility profile: X-Amz-* query parameters are reserved")
+	}
+
+	return profile, nil
+}
+
+// Reports whether the query parameter belongs to aws sig v4.
+func isSigV4QueryKey(key string) bool {
+	return strings.HasPrefix(strings.ToLower(key), "x-amz-")
+}
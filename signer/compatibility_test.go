@@ -0,0 +1,56 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAuthTokenWithCompatibilityProfile(t *testing.T) {
+	token, expirationTimeMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion,
+		testQueryCredentialsProvider, WithCompatibilityProfile(CompatibilityProfile{
+			ActionKey:    "action",
+			ActionName:   "kafka:Connect",
+			ExpiresKey:   "x-amz-expires",
+			UserAgentKey: "user-agent",
+		}))
+
+	assert.NoError(t, err)
+	assert.Positive(t, expirationTimeMs)
+	params := decodeTokenParams(t, token)
+	assert.Equal(t, "kafka:Connect", params.Get("action"))
+	assert.Equal(t, "900", params.Get("x-amz-expires"))
+	assert.NotEmpty(t, params.Get("user-agent"))
+	assert.NotContains(t, params, ActionType)
+	assert.NotContains(t, params, UserAgentKey)
+}
+
+func TestGenerateAuthTokenWithoutUserAgent(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithCompatibilityProfile(CompatibilityProfile{OmitUserAgent: true}))
+
+	assert.NoError(t, err)
+	params := decodeTokenParams(t, token)
+	assert.NotContains(t, params, UserAgentKey)
+	assert.Equal(t, ActionName, params.Get(ActionType))
+}
+
+func TestGenerateAuthTokenRejectsInvalidCompatibilityProfiles(t *testing.T) {
+	for _, profile := range []CompatibilityProfile{
+		{ActionKey: "x-amz-expires"},
+		{UserAgentKey: "action"},
+		{ActionKey: "X-Amz-Action"},
+	} {
+		_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+			WithCompatibilityProfile(profile))
+
+		assert.ErrorContains(t, err, "invalid compatibility profile", profile)
+	}
+}
+
+func TestSignedQueryParameterCannotOverrideCompatibilityProfileKeys(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithCompatibilityProfile(CompatibilityProfile{ActionKey: "op"}), WithSignedQueryParameter("OP", "x"))
+
+	assert.ErrorContains(t, err, "managed by the signer")
+}
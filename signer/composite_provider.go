@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoTokenProviders is returned by a CompositeProvider built without token providers.
+var ErrNoTokenProviders = errors.New("composite provider has no token providers")
+
+// CompositeProvider is a TokenProvider trying an ordered list of token providers, highest priority first, and
+// returning the token of the first one to succeed. Failing over is transparent to the Kafka client, which allows
+// migrating between token issuance architectures gradually, e.g. asking a sidecar first and signing locally when it is
+// unavailable. It is safe for concurrent use when its providers are.
+type CompositeProvider struct {
+	providers []TokenProvider
+
+	// OnFailover, when set, is called with the index and error of each provider that failed before another one is
+	// tried.
+	OnFailover func(index int, err error)
+}
+
+var _ TokenProvider = (*CompositeProvider)(nil)
+
+// NewCompositeProvider returns a CompositeProvider trying the providers in the given order.
+func NewCompositeProvider(providers ...TokenProvider) *CompositeProvider {
+	return &CompositeProvider{providers: providers}
+}
+
+// Token returns the token of the first provider to succeed. When all of them fail, the errors of every provider are
+// joined into the returned error. Providers are not tried any further once ctx is done.
+func (c *CompositeProvider) Token(ctx context.Context) (*Token, error) {
+	if len(c.providers) == 0 {
+		return nil, ErrNoTokenProviders
+	}
+
+	var errs []error
+	for i, provider := range c.providers {
+		token, err := provider.Token(ctx)
+		if err == nil {
+			return token, nil
+		}
+
+		errs = append(errs, fmt.Errorf("token provider %d: %w", i, err))
+		if ctx.Err() != nil {
+			break
+		}
+		if c.OnFailover != nil && i < len(c.providers)-1 {
+			c.OnFailover(i, err)
+		}
+	}
+	return nil, fmt.Errorf("all token providers failed: %w", errors.Join(errs...))
+}
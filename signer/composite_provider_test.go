@@ -0,0 +1,75 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Fails with the given error.
+type errorTokenProvider struct {
+	err error
+}
+
+func (e errorTokenProvider) Token(ctx context.Context) (*Token, error) {
+	return nil, e.err
+}
+
+func TestCompositeProviderReturnsFirstToken(t *testing.T) {
+	expiry := time.Now().Add(time.Minute)
+	provider := NewCompositeProvider(StaticTokenProvider("sidecar", expiry), StaticTokenProvider("local", expiry))
+
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sidecar", token.Value)
+}
+
+func TestCompositeProviderFailsOver(t *testing.T) {
+	sidecarErr := errors.New("sidecar unavailable")
+	provider := NewCompositeProvider(errorTokenProvider{sidecarErr}, StaticTokenProvider("local", time.Now()))
+	var failovers []int
+	provider.OnFailover = func(index int, err error) {
+		failovers = append(failovers, index)
+		assert.Equal(t, sidecarErr, err)
+	}
+
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "local", token.Value)
+	assert.Equal(t, []int{0}, failovers)
+}
+
+func TestCompositeProviderJoinsErrors(t *testing.T) {
+	first, second := errors.New("sidecar unavailable"), errors.New("no credentials")
+	provider := NewCompositeProvider(errorTokenProvider{first}, errorTokenProvider{second})
+	provider.OnFailover = func(index int, err error) {
+		assert.Equal(t, 0, index)
+	}
+
+	_, err := provider.Token(Ctx)
+
+	assert.ErrorIs(t, err, first)
+	assert.ErrorIs(t, err, second)
+	assert.ErrorContains(t, err, "token provider 1: no credentials")
+}
+
+func TestCompositeProviderStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(Ctx)
+	cancel()
+	provider := NewCompositeProvider(errorTokenProvider{context.Canceled}, StaticTokenProvider("local", time.Now()))
+
+	_, err := provider.Token(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCompositeProviderWithoutProviders(t *testing.T) {
+	_, err := NewCompositeProvider().Token(Ctx)
+
+	assert.ErrorIs(t, err, ErrNoTokenProviders)
+}
@@ -0,0 +1,129 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ConfigProvider is a TokenProvider generating auth tokens from the current configuration of a ConfigSource. The
+// configuration is read again whenever the cached token is due for refresh, and changes such as a new role or cluster
+// apply from the next generated token on, without restarting the Kafka clients using it. It is safe for concurrent
+// use.
+type ConfigProvider struct {
+	source ConfigSource
+	optFns []Option
+
+	mu       sync.Mutex
+	config   SignerConfig
+	provider *Provider
+
+	// OnConfigError, when set, is called when the configuration cannot be read or is invalid at refresh time. The
+	// previous configuration stays in use in that case.
+	OnConfigError func(err error)
+}
+
+var _ TokenProvider = (*ConfigProvider)(nil)
+
+// NewConfigProvider returns a ConfigProvider for the current configuration of the source. The configured role is
+// assumed when set, otherwise credentials are loaded from the default credentials provider chain. The configured
+// cluster ARN and max expiry are applied before optFns, as with GenerateAuthTokenFromConfigSource.
+func NewConfigProvider(ctx context.Context, source ConfigSource, optFns ...Option) (*ConfigProvider, error) {
+	cfg, err := loadValidConfig(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfigProvider{
+		source:   source,
+		optFns:   optFns,
+		config:   cfg,
+		provider: newConfigTokenProvider(cfg, optFns),
+	}, nil
+}
+
+// Token returns the cached auth token, reading the configuration again and applying changes to it before a new token
+// is generated. A configuration change replaces the provider as a whole, so the region of the token, its endpoint and
+// partition, and the sts client assuming the configured role always belong to the same configuration. A token
+// generated by a provider replaced while the token was being generated is discarded for one of the current provider.
+func (c *ConfigProvider) Token(ctx context.Context) (*Token, error) {
+	return c.tokenFrom(ctx, c.currentProvider(ctx))
+}
+
+// Returns a token of the provider, or of the current provider when the provider was replaced by a configuration
+// change while generating it.
+func (c *ConfigProvider) tokenFrom(ctx context.Context, provider *Provider) (*Token, error) {
+	token, err := provider.Token(ctx)
+
+	c.mu.Lock()
+	current := c.provider
+	c.mu.Unlock()
+	if current == provider {
+		return token, err
+	}
+	return current.Token(ctx)
+}
+
+// Config returns the configuration the current tokens are generated with.
+func (c *ConfigProvider) Config() SignerConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.config
+}
+
+// Returns the provider of the current configuration, replacing it when the cached token is due for refresh and the
+// configuration changed.
+func (c *ConfigProvider) currentProvider(ctx context.Context) *Provider {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.provider.hasFreshToken(time.Now()) {
+		return c.provider
+	}
+
+	cfg, err := loadValidConfig(ctx, c.source)
+	if err != nil {
+		if c.OnConfigError != nil {
+			c.OnConfigError(err)
+		}
+		return c.provider
+	}
+	if cfg != c.config {
+		c.config, c.provider = cfg, newConfigTokenProvider(cfg, c.optFns)
+	}
+	return c.provider
+}
+
+// Loads the configuration of the source and validates it.
+func loadValidConfig(ctx context.Context, source ConfigSource) (SignerConfig, error) {
+	cfg, err := source.Config(ctx)
+	if err != nil {
+		return SignerConfig{}, fmt.Errorf("failed to load signer config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return SignerConfig{}, fmt.Errorf("invalid signer config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Builds the provider generating tokens for the configuration, assuming the configured role when set.
+func newConfigTokenProvider(cfg SignerConfig, optFns []Option) *Provider {
+	provider := NewProvider(cfg.Region, nil, append(configOptions(cfg), optFns...)...)
+	if cfg.RoleARN == "" {
+		return provider
+	}
+
+	sessionName := cfg.STSSessionName
+	if sessionName == "" {
+		sessionName = DefaultSessionName
+	}
+	options := provider.options
+	provider.setCredentialsLoader(func(ctx context.Context, _ bool) (*aws.Credentials, error) {
+		return loadCredentialsFromRoleArn(ctx, cfg.Region, cfg.RoleARN, sessionName, options)
+	}, "assumed role "+cfg.RoleARN)
+	return provider
+}
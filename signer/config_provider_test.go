@@ -0,0 +1,176 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the signer config last set on it, counting reads.
+type mutableConfigSource struct {
+	mu     sync.Mutex
+	config SignerConfig
+	err    error
+	reads  int
+}
+
+func (m *mutableConfigSource) Config(ctx context.Context) (SignerConfig, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reads++
+	return m.config, m.err
+}
+
+func (m *mutableConfigSource) set(config SignerConfig, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config, m.err = config, err
+}
+
+func TestConfigProviderAppliesConfigChangesOnRefresh(t *testing.T) {
+	source := &mutableConfigSource{config: SignerConfig{Region: TestRegion, RoleARN: "arn:aws:iam::123456789012:role/a"}}
+	client := &mockSTSClient{}
+	provider, err := NewConfigProvider(Ctx, source, WithSTSClient(client),
+		WithRefreshStrategy(alwaysRefreshStrategy{}))
+	assert.NoError(t, err)
+
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	source.set(SignerConfig{Region: TestRegion, RoleARN: "arn:aws:iam::123456789012:role/b"}, nil)
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"arn:aws:iam::123456789012:role/a", "arn:aws:iam::123456789012:role/b"},
+		client.roleARNs)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/b", provider.Config().RoleARN)
+}
+
+func TestConfigProviderAppliesExpiryChangesOnRefresh(t *testing.T) {
+	setEnvCredentials(t)
+	source := &mutableConfigSource{config: SignerConfig{Region: TestRegion, Expiry: 10 * time.Minute}}
+	provider, err := NewConfigProvider(Ctx, source, WithRefreshStrategy(alwaysRefreshStrategy{}))
+	assert.NoError(t, err)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "600", decodeTokenParams(t, token.Value).Get(ExpiresQueryKey))
+
+	source.set(SignerConfig{Region: TestRegion, Expiry: 5 * time.Minute, ClusterARN: TestClusterARN}, nil)
+	token, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+	params := decodeTokenParams(t, token.Value)
+	assert.Equal(t, "300", params.Get(ExpiresQueryKey))
+	assert.Equal(t, TestClusterARN, params.Get(ClusterARNQueryKey))
+}
+
+func TestConfigProviderKeepsConfigWhileTokenIsFresh(t *testing.T) {
+	setEnvCredentials(t)
+	source := &mutableConfigSource{config: SignerConfig{Region: TestRegion}}
+	provider, err := NewConfigProvider(Ctx, source)
+	assert.NoError(t, err)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	source.set(SignerConfig{Region: "eu-west-1"}, nil)
+	cached, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Same(t, token, cached)
+	assert.Equal(t, 2, source.reads)
+	assert.Equal(t, TestRegion, provider.Config().Region)
+}
+
+func TestConfigProviderKeepsConfigOnError(t *testing.T) {
+	setEnvCredentials(t)
+	source := &mutableConfigSource{config: SignerConfig{Region: TestRegion}}
+	provider, err := NewConfigProvider(Ctx, source, WithRefreshStrategy(alwaysRefreshStrategy{}))
+	assert.NoError(t, err)
+	var configErrs []error
+	provider.OnConfigError = func(err error) {
+		configErrs = append(configErrs, err)
+	}
+
+	source.set(SignerConfig{}, nil)
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, TestRegion, token.Region)
+	assert.Len(t, configErrs, 1)
+	assert.ErrorContains(t, configErrs[0], "invalid signer config")
+}
+
+func TestNewConfigProviderWithFailingSource(t *testing.T) {
+	_, err := NewConfigProvider(Ctx, staticConfigSource{err: errors.New("unreachable")})
+
+	assert.ErrorContains(t, err, "failed to load signer config: unreachable")
+}
+
+// Points the sts clients created by the signer at a local server assuming roles, recording the region of the
+// credential scope of every request.
+func withSTSAssumeRoleServer(t *testing.T) *[]string {
+	var mu sync.Mutex
+	var regions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := strings.Split(strings.SplitN(r.Header.Get("Authorization"), "Credential=", 2)[1], "/")
+		mu.Lock()
+		regions = append(regions, scope[2])
+		mu.Unlock()
+		fmt.Fprint(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials>`+
+			`<AccessKeyId>TEST-ASSUMED-ACCESS-KEY</AccessKeyId><SecretAccessKey>TEST-ASSUMED-SECRET-KEY</SecretAccessKey>`+
+			`<SessionToken>TEST-ASSUMED-SESSION-TOKEN</SessionToken><Expiration>2099-01-01T00:00:00Z</Expiration>`+
+			`</Credentials></AssumeRoleResult></AssumeRoleResponse>`)
+	}))
+	t.Cleanup(server.Close)
+
+	setEnvCredentials(t)
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+	return &regions
+}
+
+func TestConfigProviderRederivesRegionOnSwitch(t *testing.T) {
+	stsRegions := withSTSAssumeRoleServer(t)
+	roleARN := "arn:aws:iam::123456789012:role/kafka"
+	source := &mutableConfigSource{config: SignerConfig{Region: TestRegion, RoleARN: roleARN}}
+	provider, err := NewConfigProvider(Ctx, source, WithRefreshStrategy(alwaysRefreshStrategy{}))
+	assert.NoError(t, err)
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	source.set(SignerConfig{Region: "eu-west-1", RoleARN: roleARN}, nil)
+	second, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, TestRegion, first.Region)
+	assert.Contains(t, QueryKeyCredential.Get(decodeTokenParams(t, first.Value)), "/"+TestRegion+"/kafka-cluster/")
+	assert.Equal(t, "eu-west-1", second.Region)
+	assert.Contains(t, QueryKeyCredential.Get(decodeTokenParams(t, second.Value)), "/eu-west-1/kafka-cluster/")
+	assert.Equal(t, []string{TestRegion, "eu-west-1"}, *stsRegions)
+}
+
+func TestConfigProviderDiscardsTokenOfReplacedProvider(t *testing.T) {
+	setEnvCredentials(t)
+	source := &mutableConfigSource{config: SignerConfig{Region: TestRegion}}
+	provider, err := NewConfigProvider(Ctx, source, WithRefreshStrategy(alwaysRefreshStrategy{}))
+	assert.NoError(t, err)
+
+	// A refresh starts on the provider of the first region, then a concurrent refresh switches the region before it
+	// generated its token.
+	inFlight := provider.currentProvider(Ctx)
+	source.set(SignerConfig{Region: "eu-west-1"}, nil)
+	provider.currentProvider(Ctx)
+	token, err := provider.tokenFrom(Ctx, inFlight)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", token.Region)
+	assert.Contains(t, QueryKeyCredential.Get(decodeTokenParams(t, token.Value)), "/eu-west-1/kafka-cluster/")
+}
@@ -0,0 +1,51 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Context key of the logger scoped to a context.
+type loggerKey struct{}
+
+// ContextWithLogger returns a context carrying the logger, e.g. one with request attributes attached, that token
+// generation uses for its events when no logger is set with WithLogger.
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext returns the logger carried by the context, or nil.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	logger, _ := ctx.Value(loggerKey{}).(*slog.Logger)
+	return logger
+}
+
+// Returns the options with the logger of the context set when none is configured.
+func withContextLogger(ctx context.Context, options Options) Options {
+	if options.Logger == nil {
+		options.Logger = LoggerFromContext(ctx)
+	}
+	return options
+}
+
+// GenerateAuthTokenWithTimeout generates an auth token from the default credentials provider chain like
+// GenerateAuthToken, giving up after the timeout. Events are logged to the logger of the context, see
+// ContextWithLogger, unless one is set with WithLogger. The error wraps context.DeadlineExceeded on timeout.
+//
+//	ctx = signer.ContextWithLogger(ctx, logger.With("cluster", clusterName))
+//	token, expirationTimeMs, err := signer.GenerateAuthTokenWithTimeout(ctx, "us-west-2", 5*time.Second)
+func GenerateAuthTokenWithTimeout(
+	ctx context.Context, region string, timeout time.Duration, optFns ...Option,
+) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	token, expirationTimeMs, err := GenerateAuthToken(ctx, region, optFns...)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("token generation timed out after %s: %w: %w", timeout, context.DeadlineExceeded, err)
+	}
+	return token, expirationTimeMs, err
+}
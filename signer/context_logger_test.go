@@ -0,0 +1,82 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAuthTokenLogsToContextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := ContextWithLogger(Ctx, slog.New(slog.NewJSONHandler(&buf, nil)).With("cluster", "orders"))
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(ctx, TestRegion, testQueryCredentialsProvider)
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), EventTokenGenerated)
+	assert.Contains(t, buf.String(), `"cluster":"orders"`)
+}
+
+func TestConfiguredLoggerTakesPrecedenceOverContextLogger(t *testing.T) {
+	var contextBuf, configuredBuf bytes.Buffer
+	ctx := ContextWithLogger(Ctx, slog.New(slog.NewJSONHandler(&contextBuf, nil)))
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(ctx, TestRegion, testQueryCredentialsProvider,
+		WithJSONLogging(&configuredBuf))
+
+	assert.NoError(t, err)
+	assert.Empty(t, contextBuf.String())
+	assert.Contains(t, configuredBuf.String(), EventTokenGenerated)
+}
+
+func TestLoggerFromContextWithoutLogger(t *testing.T) {
+	assert.Nil(t, LoggerFromContext(context.Background()))
+}
+
+// Resolves the default credentials from the environment only, with the static test keys.
+func setEnvCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-ENV-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-ENV-SECRET-KEY")
+}
+
+func TestGenerateAuthTokenWithTimeout(t *testing.T) {
+	setEnvCredentials(t)
+
+	token, expirationTimeMs, err := GenerateAuthTokenWithTimeout(Ctx, TestRegion, time.Second)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Positive(t, expirationTimeMs)
+}
+
+func TestGenerateAuthTokenWithTimeoutExpires(t *testing.T) {
+	setEnvCredentials(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL)
+
+	_, _, err := GenerateAuthTokenWithTimeout(Ctx, TestRegion, 50*time.Millisecond)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
@@ -0,0 +1,163 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// The sources of the default credential chain, in the order the SDK considers them.
+const (
+	CredentialSourceEnvironment  = "Environment"  // CredentialSourceEnvironment is the access key environment variables.
+	CredentialSourceWebIdentity  = "WebIdentity"  // CredentialSourceWebIdentity is the web identity token file.
+	CredentialSourceSharedConfig = "SharedConfig" // CredentialSourceSharedConfig is the profile of the shared config.
+	CredentialSourceContainer    = "Container"    // CredentialSourceContainer is the container credentials endpoint.
+	CredentialSourceIMDS         = "IMDS"         // CredentialSourceIMDS is the EC2 instance metadata service.
+)
+
+// CredentialSourceFailure tells why a source of the default credential chain provided no credentials.
+type CredentialSourceFailure struct {
+	// Source is the name of the source, one of the CredentialSource constants.
+	Source string
+
+	// Reason describes why the source provided no credentials, e.g. which environment variables are not set.
+	Reason string
+
+	// Err is the error the source failed with when it was used, nil when it was not configured or not tried.
+	Err error
+}
+
+// CredentialChainError is returned when the default credential chain provided no credentials. The SDK only reports
+// the error of the source it used, so the error records why each source of the chain provided none, in the order
+// they are considered. Use errors.As to retrieve it.
+type CredentialChainError struct {
+	// Failures are the sources of the chain with the reason they provided no credentials.
+	Failures []CredentialSourceFailure
+
+	// Err is the error returned by the SDK.
+	Err error
+}
+
+// Error returns the SDK error followed by the reason of every source.
+func (e *CredentialChainError) Error() string {
+	reasons := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		reasons = append(reasons, failure.Source+": "+failure.Reason)
+	}
+	return fmt.Sprintf("no credentials found in the default credential chain (%s): %s",
+		strings.Join(reasons, "; "), e.Err)
+}
+
+// Unwrap returns the error returned by the SDK.
+func (e *CredentialChainError) Unwrap() error {
+	return e.Err
+}
+
+// A source of the default credential chain, with the check returning why it is not configured, or an empty string
+// when it is.
+type credentialChainSource struct {
+	name          string
+	notConfigured func() string
+}
+
+// Builds the error of a default credential chain that failed with err, recording the reason of every source. The
+// first configured source is the one the SDK used and failed with err, the sources after it were not tried.
+func newCredentialChainError(ctx context.Context, options Options, err error) error {
+	envConfig, envErr := config.NewEnvConfig()
+	if envErr != nil {
+		return err
+	}
+
+	chainErr := &CredentialChainError{Err: err}
+	tried := false
+	for _, source := range credentialChainSources(ctx, options, envConfig) {
+		failure := CredentialSourceFailure{Source: source.name}
+		switch reason := source.notConfigured(); {
+		case tried:
+			failure.Reason = "not tried"
+		case reason != "":
+			failure.Reason = reason
+		default:
+			failure.Reason, failure.Err = "failed to retrieve credentials", err
+			tried = true
+		}
+		chainErr.Failures = append(chainErr.Failures, failure)
+	}
+	return chainErr
+}
+
+// Returns the sources of the default credential chain in the order the SDK considers them. The container and instance
+// metadata sources are only considered when the shared config profile has no credentials.
+func credentialChainSources(
+	ctx context.Context, options Options, envConfig config.EnvConfig,
+) []credentialChainSource {
+	environment := credentialChainSource{CredentialSourceEnvironment, func() string {
+		accessKeyID, secretAccessKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+		switch {
+		case envConfig.Credentials.HasKeys():
+			return ""
+		case accessKeyID != "":
+			return "AWS_SECRET_ACCESS_KEY is not set"
+		case secretAccessKey != "":
+			return "AWS_ACCESS_KEY_ID is not set"
+		default:
+			return "AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set"
+		}
+	}}
+	webIdentity := credentialChainSource{CredentialSourceWebIdentity, func() string {
+		if envConfig.WebIdentityTokenFilePath == "" {
+			return "AWS_WEB_IDENTITY_TOKEN_FILE is not set"
+		}
+		return ""
+	}}
+	sharedConfig := credentialChainSource{CredentialSourceSharedConfig, func() string {
+		return sharedConfigNotConfigured(ctx, options, envConfig)
+	}}
+	container := credentialChainSource{CredentialSourceContainer, func() string {
+		if envConfig.ContainerCredentialsEndpoint == "" && envConfig.ContainerCredentialsRelativePath == "" {
+			return "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI and AWS_CONTAINER_CREDENTIALS_FULL_URI are not set"
+		}
+		return ""
+	}}
+	imds := credentialChainSource{CredentialSourceIMDS, func() string {
+		if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+			return "AWS_EC2_METADATA_DISABLED is true"
+		}
+		return ""
+	}}
+
+	return []credentialChainSource{environment, webIdentity, sharedConfig, container, imds}
+}
+
+// Returns why the shared config profile provides no credentials, or an empty string when it is configured with
+// credentials or a way to retrieve them.
+func sharedConfigNotConfigured(ctx context.Context, options Options, envConfig config.EnvConfig) string {
+	if options.DisableSharedConfig {
+		return "shared config files are disabled"
+	}
+
+	profile := envConfig.SharedConfigProfile
+	if profile == "" {
+		profile = "default"
+	}
+	sharedConfig, err := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		if envConfig.SharedConfigFile != "" {
+			o.ConfigFiles = []string{envConfig.SharedConfigFile}
+		}
+		if envConfig.SharedCredentialsFile != "" {
+			o.CredentialsFiles = []string{envConfig.SharedCredentialsFile}
+		}
+	})
+	if err != nil {
+		return fmt.Sprintf("profile %s not found", profile)
+	}
+
+	if sharedConfig.Credentials.HasKeys() || sharedConfig.RoleARN != "" || sharedConfig.CredentialProcess != "" ||
+		sharedConfig.SSOSessionName != "" || sharedConfig.SSOStartURL != "" || sharedConfig.WebIdentityTokenFile != "" {
+		return ""
+	}
+	return fmt.Sprintf("profile %s has no credentials", profile)
+}
@@ -0,0 +1,81 @@
+package signer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Clears every source of the default credential chain.
+func clearCredentialChain(t *testing.T) {
+	setEnvCredentials(t)
+	for _, envVar := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN",
+		"AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI",
+		"AWS_CONTAINER_CREDENTIALS_FULL_URI"} {
+		t.Setenv(envVar, "")
+	}
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+}
+
+// Returns the credential chain error of the failed token generation.
+func credentialChainError(t *testing.T, err error) *CredentialChainError {
+	var chainErr *CredentialChainError
+	assert.True(t, errors.As(err, &chainErr), "unexpected error: %v", err)
+	return chainErr
+}
+
+func TestCredentialChainErrorWithoutSources(t *testing.T) {
+	clearCredentialChain(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-CHAIN-ACCESS-KEY")
+
+	_, _, err := GenerateAuthToken(Ctx, TestRegion)
+
+	chainErr := credentialChainError(t, err)
+	assert.Equal(t, []CredentialSourceFailure{
+		{Source: CredentialSourceEnvironment, Reason: "AWS_SECRET_ACCESS_KEY is not set"},
+		{Source: CredentialSourceWebIdentity, Reason: "AWS_WEB_IDENTITY_TOKEN_FILE is not set"},
+		{Source: CredentialSourceSharedConfig, Reason: "profile default not found"},
+		{Source: CredentialSourceContainer,
+			Reason: "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI and AWS_CONTAINER_CREDENTIALS_FULL_URI are not set"},
+		{Source: CredentialSourceIMDS, Reason: "AWS_EC2_METADATA_DISABLED is true"},
+	}, chainErr.Failures)
+	assert.ErrorContains(t, err, "Environment: AWS_SECRET_ACCESS_KEY is not set; WebIdentity:")
+}
+
+func TestCredentialChainErrorRecordsFailedSource(t *testing.T) {
+	clearCredentialChain(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL)
+
+	_, _, err := GenerateAuthToken(Ctx, TestRegion, WithoutSharedConfig())
+
+	chainErr := credentialChainError(t, err)
+	assert.Len(t, chainErr.Failures, 5)
+	assert.Equal(t, CredentialSourceFailure{Source: CredentialSourceSharedConfig,
+		Reason: "shared config files are disabled"}, chainErr.Failures[2])
+	assert.Equal(t, CredentialSourceContainer, chainErr.Failures[3].Source)
+	assert.Equal(t, chainErr.Err, chainErr.Failures[3].Err)
+	assert.Equal(t, CredentialSourceFailure{Source: CredentialSourceIMDS, Reason: "not tried"}, chainErr.Failures[4])
+}
+
+func TestCredentialChainErrorWithProfile(t *testing.T) {
+	clearCredentialChain(t)
+	assert.NoError(t, os.WriteFile(os.Getenv("AWS_CONFIG_FILE"),
+		[]byte("[profile failing]\ncredential_process = false\n"), 0o600))
+	t.Setenv("AWS_PROFILE", "failing")
+
+	_, _, err := GenerateAuthToken(Ctx, TestRegion)
+
+	chainErr := credentialChainError(t, err)
+	assert.Equal(t, CredentialSourceSharedConfig, chainErr.Failures[2].Source)
+	assert.Error(t, chainErr.Failures[2].Err)
+	assert.Equal(t, "not tried", chainErr.Failures[3].Reason)
+	assert.Equal(t, "not tried", chainErr.Failures[4].Reason)
+}
@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// DefaultMinCredentialLifetime is the remaining lifetime below which credentials are considered about to expire.
+const DefaultMinCredentialLifetime = time.Minute
+
+// ErrCredentialsExpired is returned when the resolved credentials have already expired, even after retrieving them
+// once more.
+var ErrCredentialsExpired = errors.New("aws credentials have expired")
+
+// ErrCredentialsExpiringSoon is returned when the resolved credentials expire within the minimum credential lifetime
+// and the credential expiry policy does not allow signing with them.
+var ErrCredentialsExpiringSoon = errors.New("aws credentials expire too soon")
+
+// CredentialExpiryPolicy decides what happens when the resolved credentials are about to expire.
+type CredentialExpiryPolicy int
+
+const (
+	// CredentialExpiryIgnore signs with the resolved credentials regardless of their expiry. This is the default.
+	CredentialExpiryIgnore CredentialExpiryPolicy = iota
+
+	// CredentialExpiryFail fails token generation with ErrCredentialsExpiringSoon.
+	CredentialExpiryFail
+
+	// CredentialExpiryRefresh invalidates cached credentials and retrieves them once more, failing with
+	// ErrCredentialsExpiringSoon if the refreshed credentials are still about to expire.
+	CredentialExpiryRefresh
+)
+
+// Invalidates the cached credentials of providers that support it, such as aws.CredentialsCache.
+func invalidateCredentials(credentialsProvider aws.CredentialsProvider) {
+	if invalidator, ok := credentialsProvider.(interface{ Invalidate() }); ok {
+		invalidator.Invalidate()
+	}
+}
+
+// Loads the credentials and applies the configured credential expiry policy to them. Expired credentials are
+// retrieved once more whatever the policy, failing with ErrCredentialsExpired if they are still expired.
+func loadCredentialsWithExpiryPolicy(
+	ctx context.Context, options Options, loadCredentials credentialsLoader,
+) (*aws.Credentials, error) {
+	credentials, err := loadCredentials(ctx, false)
+	if err == nil && expiresWithin(credentials, 0) {
+		credentials, err = loadCredentials(ctx, true)
+		if err == nil && expiresWithin(credentials, 0) {
+			return nil, fmt.Errorf("%w: credentials expired at %s", ErrCredentialsExpired,
+				credentials.Expires.UTC().Format(time.RFC3339))
+		}
+	}
+	if err != nil || options.CredentialExpiryPolicy == CredentialExpiryIgnore {
+		return credentials, err
+	}
+
+	minLifetime := options.MinCredentialLifetime
+	if minLifetime <= 0 {
+		minLifetime = DefaultMinCredentialLifetime
+	}
+
+	if !expiresWithin(credentials, minLifetime) {
+		return credentials, nil
+	}
+
+	if options.CredentialExpiryPolicy == CredentialExpiryRefresh {
+		credentials, err = loadCredentials(ctx, true)
+		if err != nil || !expiresWithin(credentials, minLifetime) {
+			return credentials, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w: credentials expire at %s, within the minimum lifetime of %s",
+		ErrCredentialsExpiringSoon, credentials.Expires.UTC().Format(time.RFC3339), minLifetime)
+}
+
+// Reports whether the credentials expire within the given duration.
+func expiresWithin(credentials *aws.Credentials, d time.Duration) bool {
+	return credentials != nil && credentials.CanExpire && time.Until(credentials.Expires) < d
+}
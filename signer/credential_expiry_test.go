@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the queued credentials in order, repeating the last one, and counts the calls to Retrieve.
+type sequenceCredentialsProvider struct {
+	credentials []aws.Credentials
+	calls       int
+}
+
+func (s *sequenceCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	index := s.calls
+	if index >= len(s.credentials) {
+		index = len(s.credentials) - 1
+	}
+	s.calls++
+	return s.credentials[index], nil
+}
+
+// Builds test credentials expiring after the given duration.
+func expiringCredentials(accessKeyID string, expiresIn time.Duration) aws.Credentials {
+	return aws.Credentials{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: "TEST-EXPIRY-SECRET-KEY",
+		CanExpire:       true,
+		Expires:         time.Now().Add(expiresIn),
+	}
+}
+
+func TestCredentialExpiryIgnoredByDefault(t *testing.T) {
+	provider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-EXPIRING-KEY", 5*time.Second)},
+	}
+
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, provider)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, 1, provider.calls)
+}
+
+func TestCredentialExpiryFail(t *testing.T) {
+	provider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-EXPIRING-KEY", 5*time.Second)},
+	}
+
+	token, expiryMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, provider,
+		WithCredentialExpiryPolicy(CredentialExpiryFail, 0))
+
+	assert.ErrorIs(t, err, ErrCredentialsExpiringSoon)
+	assert.Empty(t, token)
+	assert.Equal(t, int64(0), expiryMs)
+}
+
+func TestCredentialExpiryFailAllowsLongLivedCredentials(t *testing.T) {
+	provider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-VALID-KEY", time.Hour)},
+	}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, provider,
+		WithCredentialExpiryPolicy(CredentialExpiryFail, 10*time.Minute))
+
+	assert.NoError(t, err)
+}
+
+func TestCredentialExpiryRefreshInvalidatesCache(t *testing.T) {
+	provider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{
+			expiringCredentials("TEST-EXPIRING-KEY", 5*time.Second),
+			expiringCredentials("TEST-REFRESHED-KEY", time.Hour),
+		},
+	}
+	cache := aws.NewCredentialsCache(provider)
+	_, err := cache.Retrieve(Ctx)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, _, err = GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, cache,
+		WithCredentialExpiryPolicy(CredentialExpiryRefresh, 0), WithJSONLogging(&buf))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+	assert.Contains(t, buf.String(), "TEST-REFRESHED-KEY")
+}
+
+func TestCredentialExpiryRefreshFailsWhenStillExpiring(t *testing.T) {
+	provider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-EXPIRING-KEY", 5*time.Second)},
+	}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, aws.NewCredentialsCache(provider),
+		WithCredentialExpiryPolicy(CredentialExpiryRefresh, 0))
+
+	assert.ErrorIs(t, err, ErrCredentialsExpiringSoon)
+	assert.Equal(t, 2, provider.calls)
+}
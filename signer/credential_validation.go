@@ -0,0 +1,121 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// EventCredentialValidationFailed is logged when the background validation of the credentials of a Provider fails.
+const EventCredentialValidationFailed = "credential_validation_failed"
+
+// ErrPrincipalChanged is reported by the credential validation of a Provider when its credentials resolve to another
+// principal than at the first validation.
+var ErrPrincipalChanged = errors.New("credentials principal changed")
+
+// ValidateCredentials validates the credentials of the provider with sts GetCallerIdentity right away and then every
+// interval until ctx is done, so revoked or rotated-away credentials are noticed before the next token refresh fails.
+// Failures, including ErrPrincipalChanged when the credentials resolve to another principal than at the first
+// validation, are logged as EventCredentialValidationFailed and reported to onFailure, unless nil. Validation uses the
+// cached credentials of the provider and doesn't change its cached token. The credentials are only validated once
+// when interval is not positive.
+func (p *Provider) ValidateCredentials(ctx context.Context, interval time.Duration, onFailure func(err error)) {
+	go func() {
+		var principal string
+		validate := func() {
+			if err := p.validateCredentials(ctx, &principal); err != nil && ctx.Err() == nil {
+				logCredentialValidationFailed(ctx, p.options.Logger, err)
+				if onFailure != nil {
+					onFailure(err)
+				}
+			}
+		}
+
+		validate()
+		if interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				validate()
+			}
+		}
+	}()
+}
+
+// Validates the credentials of the provider, recording the principal they resolve to on the first successful
+// validation and comparing it afterwards.
+func (p *Provider) validateCredentials(ctx context.Context, principal *string) error {
+	arn, err := p.callerIdentity(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to validate credentials: %w", err)
+	}
+
+	current := principalOf(arn)
+	if *principal == "" {
+		*principal = current
+	} else if current != *principal {
+		return fmt.Errorf("%w: from %s to %s", ErrPrincipalChanged, *principal, current)
+	}
+	return nil
+}
+
+// Returns the ARN of the caller identity of the cached credentials of the provider.
+func (p *Provider) callerIdentity(ctx context.Context) (string, error) {
+	creds, err := p.loadCredentials(ctx, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	region, err := resolveRegion(ctx, p.region, p.options)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := loadConfig(ctx, region, p.options)
+	if err != nil {
+		return "", fmt.Errorf("unable to load SDK config: %w", err)
+	}
+	cfg.Credentials = credentials.StaticCredentialsProvider{Value: *creds}
+
+	output, err := newSTSClient(cfg, p.options).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.Arn), nil
+}
+
+// Returns the principal of the caller identity ARN, leaving out the session name of assumed roles as it may change
+// every time the role is assumed.
+func principalOf(arn string) string {
+	if i := strings.Index(arn, ":assumed-role/"); i >= 0 {
+		if j := strings.LastIndex(arn, "/"); j > i+len(":assumed-role/") {
+			return arn[:j]
+		}
+	}
+	return arn
+}
+
+// Logs a failed credential validation.
+func logCredentialValidationFailed(ctx context.Context, logger *slog.Logger, err error) {
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelError, "failed to validate aws credentials",
+		slog.String(LogKeyEvent, EventCredentialValidationFailed),
+		slog.String(LogKeyError, err.Error()),
+	)
+}
@@ -0,0 +1,127 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Points the sts clients created by the signer at a local server answering GetCallerIdentity with the ARN returned by
+// arnOf for the nth call, or failing when it returns an empty ARN.
+func withSTSCallerIdentityServer(t *testing.T, arnOf func(call int64) string) *atomic.Int64 {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "GetCallerIdentity", r.Form.Get("Action"))
+		arn := arnOf(calls.Add(1))
+		if arn == "" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<ErrorResponse><Error><Code>InvalidClientTokenId</Code></Error></ErrorResponse>`)
+			return
+		}
+		fmt.Fprintf(w, `<GetCallerIdentityResponse><GetCallerIdentityResult><Arn>%s</Arn>`+
+			`<Account>123456789012</Account></GetCallerIdentityResult></GetCallerIdentityResponse>`, arn)
+	}))
+	t.Cleanup(server.Close)
+
+	setEnvCredentials(t)
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+	return &calls
+}
+
+// Collects the errors reported by the credential validation.
+type validationFailures struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (v *validationFailures) report(err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.errs = append(v.errs, err)
+}
+
+func (v *validationFailures) get() []error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]error(nil), v.errs...)
+}
+
+func TestValidateCredentials(t *testing.T) {
+	calls := withSTSCallerIdentityServer(t, func(call int64) string {
+		return fmt.Sprintf("arn:aws:sts::123456789012:assumed-role/TestRole/session-%d", call)
+	})
+	provider, _ := newCountingProvider()
+	ctx, cancel := context.WithCancel(Ctx)
+	defer cancel()
+	var failures validationFailures
+
+	provider.ValidateCredentials(ctx, time.Millisecond, failures.report)
+
+	assert.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, time.Millisecond)
+	assert.Empty(t, failures.get())
+}
+
+func TestValidateCredentialsReportsPrincipalChange(t *testing.T) {
+	withSTSCallerIdentityServer(t, func(call int64) string {
+		if call == 1 {
+			return "arn:aws:iam::123456789012:user/first"
+		}
+		return "arn:aws:iam::123456789012:user/second"
+	})
+	provider, _ := newCountingProvider()
+	ctx, cancel := context.WithCancel(Ctx)
+	defer cancel()
+	var failures validationFailures
+
+	provider.ValidateCredentials(ctx, time.Millisecond, failures.report)
+
+	assert.Eventually(t, func() bool { return len(failures.get()) > 0 }, time.Second, time.Millisecond)
+	assert.ErrorIs(t, failures.get()[0], ErrPrincipalChanged)
+	assert.ErrorContains(t, failures.get()[0], "from arn:aws:iam::123456789012:user/first to "+
+		"arn:aws:iam::123456789012:user/second")
+}
+
+func TestValidateCredentialsLogsFailure(t *testing.T) {
+	withSTSCallerIdentityServer(t, func(call int64) string { return "" })
+	var logs bytes.Buffer
+	provider, _ := newCountingProvider(WithJSONLogging(&logs))
+	ctx, cancel := context.WithCancel(Ctx)
+	defer cancel()
+	var failures validationFailures
+
+	provider.ValidateCredentials(ctx, time.Hour, failures.report)
+
+	assert.Eventually(t, func() bool { return len(failures.get()) > 0 }, time.Second, time.Millisecond)
+	assert.ErrorContains(t, failures.get()[0], "InvalidClientTokenId")
+	assert.Contains(t, logs.String(), EventCredentialValidationFailed)
+}
+
+func TestValidateCredentialsOnceWithoutInterval(t *testing.T) {
+	calls := withSTSCallerIdentityServer(t, func(call int64) string { return "" })
+	provider, _ := newCountingProvider()
+	ctx, cancel := context.WithCancel(Ctx)
+	defer cancel()
+	var failures validationFailures
+
+	provider.ValidateCredentials(ctx, 0, failures.report)
+
+	assert.Eventually(t, func() bool { return len(failures.get()) > 0 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int64(1), calls.Load())
+	assert.Len(t, failures.get(), 1)
+}
+
+func TestPrincipalOf(t *testing.T) {
+	assert.Equal(t, "arn:aws:sts::123456789012:assumed-role/TestRole",
+		principalOf("arn:aws:sts::123456789012:assumed-role/TestRole/session"))
+	assert.Equal(t, "arn:aws:iam::123456789012:user/test", principalOf("arn:aws:iam::123456789012:user/test"))
+}
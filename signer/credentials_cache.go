@@ -0,0 +1,82 @@
+package signer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// DefaultCredentialsExpiryWindow is how long before they expire a Provider stops reusing cached credentials.
+const DefaultCredentialsExpiryWindow = 5 * time.Minute
+
+// Fraction of the expiry window the credential refreshes of the presets are spread over, so replicas started together
+// do not refresh together.
+const presetCredentialsJitterFrac = 0.1
+
+// Caches the credentials of the provider until a jittered expiry window before they expire, as the Provider presets
+// recommend.
+func newJitteredCredentialsCache(provider aws.CredentialsProvider) *aws.CredentialsCache {
+	return aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = DefaultCredentialsExpiryWindow
+		o.ExpiryWindowJitterFrac = presetCredentialsJitterFrac
+	})
+}
+
+// Caches credentials carrying an expiry time until the expiry window before they expire, so a Provider does not
+// retrieve them for every token. Credentials that cannot expire are retrieved for every token, as their source may
+// rotate them at any time.
+type expiringCredentialsCache struct {
+	loadCredentials credentialsLoader
+	window          time.Duration
+
+	mu          sync.Mutex
+	credentials *aws.Credentials
+}
+
+// Returns a cache of the credentials loaded by the loader, reused until the window before they expire.
+func newExpiringCredentialsCache(loadCredentials credentialsLoader, window time.Duration) *expiringCredentialsCache {
+	return &expiringCredentialsCache{loadCredentials: loadCredentials, window: window}
+}
+
+// Returns the cached credentials while they are valid for longer than the window, otherwise loads new ones. A forced
+// refresh discards the cached credentials.
+func (c *expiringCredentialsCache) load(ctx context.Context, forceRefresh bool) (*aws.Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !forceRefresh && c.credentials != nil && !expiresWithin(c.credentials, c.window) {
+		return c.credentials, nil
+	}
+
+	c.credentials = nil
+	credentials, err := c.loadCredentials(ctx, forceRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	if credentials != nil && credentials.CanExpire {
+		c.credentials = credentials
+	}
+	return credentials, nil
+}
+
+// Caches the credentials, e.g. restored from a previous instance, unless they cannot expire or expire within the
+// window.
+func (c *expiringCredentialsCache) seed(credentials *aws.Credentials) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if credentials.CanExpire && !expiresWithin(credentials, c.window) {
+		c.credentials = credentials
+	}
+}
+
+// Returns the cached credentials, or nil when none are cached.
+func (c *expiringCredentialsCache) cached() *aws.Credentials {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.credentials
+}
@@ -0,0 +1,92 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderReusesExpiringCredentials(t *testing.T) {
+	credentialsProvider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-CACHED-ACCESS-KEY", time.Hour)},
+	}
+	provider := NewProvider(TestRegion, credentialsProvider)
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	provider.refreshAt = time.Now().Add(-time.Second)
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, credentialsProvider.calls)
+}
+
+func TestProviderRetrievesCredentialsNearExpiry(t *testing.T) {
+	credentialsProvider := &sequenceCredentialsProvider{credentials: []aws.Credentials{
+		expiringCredentials("TEST-EXPIRING-ACCESS-KEY", DefaultCredentialsExpiryWindow-time.Minute),
+		expiringCredentials("TEST-RENEWED-ACCESS-KEY", time.Hour),
+	}}
+	provider := NewProvider(TestRegion, credentialsProvider)
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	provider.refreshAt = time.Now().Add(-time.Second)
+	second, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TEST-EXPIRING-ACCESS-KEY", first.KeyID)
+	assert.Equal(t, "TEST-RENEWED-ACCESS-KEY", second.KeyID)
+	assert.Equal(t, 2, credentialsProvider.calls)
+}
+
+func TestProviderRetrievesNonExpiringCredentialsPerToken(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider()
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	provider.refreshAt = time.Now().Add(-time.Second)
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, credentialsProvider.calls)
+}
+
+func TestProviderForceRefreshDiscardsCachedCredentials(t *testing.T) {
+	credentialsProvider := &sequenceCredentialsProvider{credentials: []aws.Credentials{
+		expiringCredentials("TEST-OLD-ACCESS-KEY", time.Hour),
+		expiringCredentials("TEST-ROTATED-ACCESS-KEY", time.Hour),
+	}}
+	provider := NewProvider(TestRegion, credentialsProvider)
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	token, err := provider.ForceRefresh(Ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TEST-ROTATED-ACCESS-KEY", token.KeyID)
+}
+
+func TestGenerateAuthTokenWithExpiredCredentials(t *testing.T) {
+	credentialsProvider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-EXPIRED-ACCESS-KEY", -time.Minute)},
+	}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, credentialsProvider)
+
+	assert.ErrorIs(t, err, ErrCredentialsExpired)
+	assert.Equal(t, 2, credentialsProvider.calls)
+}
+
+func TestGenerateAuthTokenRetrievesExpiredCredentialsAgain(t *testing.T) {
+	credentialsProvider := &sequenceCredentialsProvider{credentials: []aws.Credentials{
+		expiringCredentials("TEST-EXPIRED-ACCESS-KEY", -time.Minute),
+		expiringCredentials("TEST-VALID-ACCESS-KEY", time.Hour),
+	}}
+
+	token, err := GenerateToken(Ctx, TestRegion, credentialsProvider)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-VALID-ACCESS-KEY", token.KeyID)
+}
@@ -0,0 +1,170 @@
+package signer
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Describes the credentials of providers built without a credentials provider.
+const defaultCredentialChain = "default credential chain"
+
+// DebugSnapshot is the state of a Provider for attaching to bug reports. It holds no secrets: token values are left
+// out, access key ids are shortened and only the names of signed query parameters are included.
+type DebugSnapshot struct {
+	LibraryVersion      string           `json:"libraryVersion"`
+	GoVersion           string           `json:"goVersion"`
+	Time                time.Time        `json:"time"`
+	Region              string           `json:"region"`
+	CredentialsProvider string           `json:"credentialsProvider"`
+	Config              DebugConfig      `json:"config"`
+	CachedToken         *DebugToken      `json:"cachedToken,omitempty"`
+	NextToken           *DebugToken      `json:"nextToken,omitempty"`
+	LastError           string           `json:"lastError,omitempty"`
+	LastErrorTime       time.Time        `json:"lastErrorTime"`
+	STSThrottleStats    STSThrottleStats `json:"stsThrottleStats"`
+	Stats               ProviderStats    `json:"stats"`
+}
+
+// DebugConfig describes the options of a Provider.
+type DebugConfig struct {
+	RefreshStrategy        string        `json:"refreshStrategy"`
+	MinRefreshInterval     time.Duration `json:"minRefreshInterval,omitempty"`
+	TokenOverlap           time.Duration `json:"tokenOverlap,omitempty"`
+	MaxExpiry              time.Duration `json:"maxExpiry,omitempty"`
+	MaxExpiryPolicy        string        `json:"maxExpiryPolicy,omitempty"`
+	CredentialExpiryPolicy string        `json:"credentialExpiryPolicy"`
+	MinCredentialLifetime  time.Duration `json:"minCredentialLifetime,omitempty"`
+	DisableSharedConfig    bool          `json:"disableSharedConfig"`
+	DefaultsMode           string        `json:"defaultsMode,omitempty"`
+	ClusterARN             string        `json:"clusterArn,omitempty"`
+	SignedQueryParameters  []string      `json:"signedQueryParameters,omitempty"`
+	StrictRegionValidation bool          `json:"strictRegionValidation"`
+	ExtraRegions           []string      `json:"extraRegions,omitempty"`
+	RequestSigner          string        `json:"requestSigner,omitempty"`
+	STSClient              string        `json:"stsClient,omitempty"`
+	Logging                bool          `json:"logging"`
+	Tracing                bool          `json:"tracing"`
+	EMFNamespace           string        `json:"emfNamespace,omitempty"`
+	TokenWriter            bool          `json:"tokenWriter"`
+}
+
+// DebugToken describes a cached token without its value.
+type DebugToken struct {
+	Region                  string        `json:"region"`
+	KeyID                   string        `json:"keyId,omitempty"`
+	Source                  string        `json:"source,omitempty"`
+	ExpiresAt               time.Time     `json:"expiresAt"`
+	RefreshAt               time.Time     `json:"refreshAt"`
+	TTL                     time.Duration `json:"ttl"`
+	CredentialFetchDuration time.Duration `json:"credentialFetchDuration"`
+	SignDuration            time.Duration `json:"signDuration"`
+}
+
+// DebugSnapshot returns the sanitized state of the provider: its configuration, cached tokens, last error, timings
+// and versions. It is meant to be attached to bug reports as JSON.
+func (p *Provider) DebugSnapshot() DebugSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := DebugSnapshot{
+		LibraryVersion:      version,
+		GoVersion:           runtime.Version(),
+		Time:                time.Now().UTC(),
+		Region:              p.region,
+		CredentialsProvider: p.credentialsProvider,
+		Config:              debugConfig(p.options),
+		CachedToken:         debugToken(p.token, p.refreshAt),
+		NextToken:           debugToken(p.next, p.nextRefreshAt),
+		STSThrottleStats:    p.options.STSThrottleMonitor.Stats(),
+		Stats:               p.stats,
+	}
+	if p.lastErr != nil {
+		snapshot.LastError = p.lastErr.Error()
+		snapshot.LastErrorTime = p.lastErrTime.UTC()
+	}
+	return snapshot
+}
+
+// Describes the options without the values of signed query parameters, which may be sensitive.
+func debugConfig(options Options) DebugConfig {
+	config := DebugConfig{
+		RefreshStrategy:        typeName(options.RefreshStrategy),
+		MinRefreshInterval:     options.MinRefreshInterval,
+		TokenOverlap:           options.TokenOverlap,
+		MaxExpiry:              options.MaxExpiry,
+		CredentialExpiryPolicy: credentialExpiryPolicyName(options.CredentialExpiryPolicy),
+		MinCredentialLifetime:  options.MinCredentialLifetime,
+		DisableSharedConfig:    options.DisableSharedConfig,
+		DefaultsMode:           string(options.DefaultsMode),
+		ClusterARN:             options.ClusterARN,
+		StrictRegionValidation: options.StrictRegionValidation,
+		ExtraRegions:           options.ExtraRegions,
+		RequestSigner:          typeName(options.RequestSigner),
+		STSClient:              typeName(options.STSClient),
+		Logging:                options.Logger != nil,
+		Tracing:                options.Tracer != nil,
+		EMFNamespace:           options.EMFNamespace,
+		TokenWriter:            options.TokenWriter != nil,
+	}
+	if options.MaxExpiry > 0 {
+		config.MaxExpiryPolicy = "reject"
+		if options.MaxExpiryPolicy == MaxExpiryClamp {
+			config.MaxExpiryPolicy = "clamp"
+		}
+	}
+	for key := range options.SignedQueryParameters {
+		config.SignedQueryParameters = append(config.SignedQueryParameters, key)
+	}
+	sort.Strings(config.SignedQueryParameters)
+	return config
+}
+
+// Describes the token without its value, or returns nil when there is no token.
+func debugToken(token *Token, refreshAt time.Time) *DebugToken {
+	if token == nil {
+		return nil
+	}
+
+	return &DebugToken{
+		Region:                  token.Region,
+		KeyID:                   shortenKeyID(token.KeyID),
+		Source:                  token.Source,
+		ExpiresAt:               token.Expiry(0).UTC(),
+		RefreshAt:               refreshAt.UTC(),
+		TTL:                     tokenTTL(token),
+		CredentialFetchDuration: token.CredentialFetchDuration,
+		SignDuration:            token.SignDuration,
+	}
+}
+
+// Keeps the prefix identifying the type of an access key id and its last characters.
+func shortenKeyID(keyID string) string {
+	if len(keyID) <= 8 {
+		return keyID
+	}
+	return keyID[:4] + "..." + keyID[len(keyID)-4:]
+}
+
+// Returns the name of the credential expiry policy.
+func credentialExpiryPolicyName(policy CredentialExpiryPolicy) string {
+	switch policy {
+	case CredentialExpiryIgnore:
+		return "ignore"
+	case CredentialExpiryFail:
+		return "fail"
+	case CredentialExpiryRefresh:
+		return "refresh"
+	default:
+		return "unknown"
+	}
+}
+
+// Returns the type name of the value, or an empty string when it is nil.
+func typeName(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%T", value)
+}
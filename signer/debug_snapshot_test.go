@@ -0,0 +1,63 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderDebugSnapshot(t *testing.T) {
+	provider, _ := newCountingProvider(WithSignedQueryParameter("tenant", "secret-tenant"),
+		WithMaxExpiry(time.Hour, MaxExpiryClamp), WithTokenOverlap(time.Minute))
+
+	snapshot := provider.DebugSnapshot()
+	assert.Equal(t, version, snapshot.LibraryVersion)
+	assert.NotEmpty(t, snapshot.GoVersion)
+	assert.Equal(t, TestRegion, snapshot.Region)
+	assert.Equal(t, "*signer.sequenceCredentialsProvider", snapshot.CredentialsProvider)
+	assert.Equal(t, "signer.FractionRefreshStrategy", snapshot.Config.RefreshStrategy)
+	assert.Equal(t, "clamp", snapshot.Config.MaxExpiryPolicy)
+	assert.Equal(t, []string{"tenant"}, snapshot.Config.SignedQueryParameters)
+	assert.Nil(t, snapshot.CachedToken)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	snapshot = provider.DebugSnapshot()
+	assert.Equal(t, "TEST...-KEY", snapshot.CachedToken.KeyID)
+	assert.Equal(t, token.Expiry(0).UTC(), snapshot.CachedToken.ExpiresAt)
+	assert.Greater(t, snapshot.CachedToken.TTL, 14*time.Minute)
+	assert.Nil(t, snapshot.NextToken)
+	assert.Empty(t, snapshot.LastError)
+
+	data, err := json.Marshal(snapshot)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), token.Value)
+	assert.NotContains(t, string(data), "secret-tenant")
+	assert.NotContains(t, string(data), "TEST-PROVIDER-ACCESS-KEY")
+}
+
+func TestProviderDebugSnapshotLastError(t *testing.T) {
+	failing := aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{}, errors.New("no credentials")
+	})
+	provider := NewProvider(TestRegion, failing)
+
+	_, err := provider.Token(Ctx)
+	assert.Error(t, err)
+
+	snapshot := provider.DebugSnapshot()
+	assert.Contains(t, snapshot.LastError, "no credentials")
+	assert.WithinDuration(t, time.Now(), snapshot.LastErrorTime, 5*time.Second)
+}
+
+func TestDebugSnapshotOfDefaultCredentialChain(t *testing.T) {
+	snapshot := NewProvider(TestRegion, nil).DebugSnapshot()
+	assert.Equal(t, defaultCredentialChain, snapshot.CredentialsProvider)
+	assert.Equal(t, "ignore", snapshot.Config.CredentialExpiryPolicy)
+}
@@ -0,0 +1,32 @@
+package signer
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// Creates the HTTP client the SDK retrieves credentials with, with the request timeout unless zero. Connections are
+// dialed with the dialer of the options when set, e.g. to bind them to the source address of a network interface, to
+// the addresses of the host resolver of the options when set.
+func newCredentialsHTTPClient(options Options, timeout time.Duration) *awshttp.BuildableClient {
+	client := awshttp.NewBuildableClient()
+	if timeout > 0 {
+		client = client.WithTimeout(timeout)
+	}
+	if options.Dialer != nil {
+		dialer := *options.Dialer
+		client = client.WithDialerOptions(func(d *net.Dialer) {
+			*d = dialer
+		})
+	}
+	if options.HostResolver != nil {
+		client = client.WithTransportOptions(func(tr *http.Transport) {
+			dialer := client.GetDialer()
+			tr.DialContext = resolvingDialContext(dialer, options.HostResolver)
+		})
+	}
+	return client
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Serves container credentials and points the container credentials environment variables at the server.
+func withContainerCredentialsServer(t *testing.T) {
+	setEnvCredentials(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"AccessKeyId":     "TEST-DIALER-ACCESS-KEY",
+			"SecretAccessKey": "TEST-DIALER-SECRET-KEY",
+			"Token":           "TEST-DIALER-SESSION-TOKEN",
+			"Expiration":      time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL)
+}
+
+// Returns a dialer counting the connections it dials.
+func newCountingDialer() (*net.Dialer, *atomic.Int64) {
+	var dials atomic.Int64
+	return &net.Dialer{
+		LocalAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)},
+		Control: func(network, address string, c syscall.RawConn) error {
+			dials.Add(1)
+			return nil
+		},
+	}, &dials
+}
+
+func TestGenerateAuthTokenWithDialer(t *testing.T) {
+	withContainerCredentialsServer(t)
+	dialer, dials := newCountingDialer()
+
+	token, _, err := GenerateAuthToken(Ctx, TestRegion, WithDialer(dialer))
+
+	assert.NoError(t, err)
+	assert.Contains(t, QueryKeyCredential.Get(decodeTokenParams(t, token)), "TEST-DIALER-ACCESS-KEY")
+	assert.Equal(t, int64(1), dials.Load())
+}
+
+func TestGenerateAuthTokenWithDialerWithoutSharedConfig(t *testing.T) {
+	withContainerCredentialsServer(t)
+	dialer, dials := newCountingDialer()
+
+	token, _, err := GenerateAuthToken(Ctx, TestRegion, WithDialer(dialer), WithoutSharedConfig())
+
+	assert.NoError(t, err)
+	assert.Contains(t, QueryKeyCredential.Get(decodeTokenParams(t, token)), "TEST-DIALER-ACCESS-KEY")
+	assert.Equal(t, int64(1), dials.Load())
+}
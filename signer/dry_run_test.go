@@ -0,0 +1,36 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRunProvider(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_CONFIG_FILE", "/nonexistent")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/nonexistent")
+	provider := NewDryRunProvider(TestRegion, WithExpiry(5*time.Minute))
+
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.True(t, IsDryRunToken(token.Value))
+	assert.Equal(t, DryRunSource, token.Source)
+	assert.Equal(t, DryRunAccessKeyID, token.KeyID)
+	params, err := ParseToken(token.Value)
+	assert.NoError(t, err)
+	assert.Equal(t, TestRegion, params.Region)
+	assert.Equal(t, ActionName, params.Action)
+	assert.Equal(t, 5*time.Minute, params.Expires)
+	assert.Equal(t, DryRunSource, provider.DebugSnapshot().CredentialsProvider)
+}
+
+func TestIsDryRunToken(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider)
+	assert.NoError(t, err)
+
+	assert.False(t, IsDryRunToken(token))
+	assert.False(t, IsDryRunToken("not a token"))
+}
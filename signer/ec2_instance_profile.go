@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+)
+
+const (
+	// DefaultEC2IMDSTimeout is the timeout of every instance metadata request of the EC2 instance profile preset,
+	// tighter than the SDK default of 5 seconds, as the metadata service answers within milliseconds when reachable.
+	DefaultEC2IMDSTimeout = time.Second
+
+	// DefaultEC2IMDSMaxAttempts is how often the EC2 instance profile preset attempts an instance metadata request.
+	DefaultEC2IMDSMaxAttempts = 3
+)
+
+// NewEC2InstanceProfileProvider returns a Provider configured with the recommended settings for EC2 instances signing
+// with the credentials of their instance profile:
+//
+//   - credentials are retrieved from the instance metadata service with IMDSv2 enforced, never falling back to IMDSv1,
+//     each request timing out after DefaultEC2IMDSTimeout and attempted DefaultEC2IMDSMaxAttempts times,
+//   - credentials are cached until DefaultCredentialsExpiryWindow before they expire, with jitter so instances do not
+//     refresh together, and refreshed once more when about to expire.
+//
+// The AWS_EC2_METADATA_SERVICE_ENDPOINT and AWS_EC2_METADATA_DISABLED environment variables are honored. optFns are
+// applied after the preset and can override it.
+func NewEC2InstanceProfileProvider(region string, optFns ...Option) (*Provider, error) {
+	if region == "" {
+		return nil, errors.New("region cannot be empty")
+	}
+
+	optFns = append([]Option{
+		WithCredentialExpiryPolicy(CredentialExpiryRefresh, DefaultMinCredentialLifetime),
+	}, optFns...)
+
+	options := resolveOptions(optFns)
+	instanceProfile := ec2rolecreds.New(func(o *ec2rolecreds.Options) {
+		o.Client = newEC2IMDSClient(options)
+	})
+	return NewProvider(region, newJitteredCredentialsCache(instanceProfile), optFns...), nil
+}
+
+// Creates the instance metadata client of the EC2 instance profile preset, enforcing IMDSv2 with tight timeouts.
+func newEC2IMDSClient(options Options) *imds.Client {
+	return imds.New(imds.Options{
+		EnableFallback:        aws.FalseTernary,
+		DisableDefaultTimeout: true,
+		HTTPClient:            newCredentialsHTTPClient(options, DefaultEC2IMDSTimeout),
+		APIOptions:            options.APIOptions,
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.MaxAttempts = DefaultEC2IMDSMaxAttempts
+		}),
+	})
+}
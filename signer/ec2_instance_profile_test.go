@@ -0,0 +1,77 @@
+package signer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Starts a fake instance metadata service serving instance profile credentials, requiring IMDSv2 session tokens
+// unless tokens are unsupported.
+func withIMDSServer(t *testing.T, tokensSupported bool) *int {
+	var credentialRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			if !tokensSupported {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "21600")
+			_, _ = w.Write([]byte("imds-session-token"))
+		case r.Header.Get("X-Aws-Ec2-Metadata-Token") != "imds-session-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+			_, _ = w.Write([]byte("kafka-instance-role"))
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/kafka-instance-role":
+			credentialRequests++
+			_, _ = fmt.Fprintf(w, `{"Code": "Success", "AccessKeyId": "TEST-IMDS-ACCESS-KEY", `+
+				`"SecretAccessKey": "TEST-IMDS-SECRET-KEY", "Token": "TEST-IMDS-SESSION-TOKEN", "Expiration": %q}`,
+				time.Now().Add(6*time.Hour).UTC().Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL)
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+	return &credentialRequests
+}
+
+func TestNewEC2InstanceProfileProvider(t *testing.T) {
+	credentialRequests := withIMDSServer(t, true)
+
+	provider, err := NewEC2InstanceProfileProvider(TestRegion)
+	assert.NoError(t, err)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-IMDS-ACCESS-KEY", token.KeyID)
+	assert.Equal(t, CredentialExpiryRefresh, provider.options.CredentialExpiryPolicy)
+
+	provider.refreshAt = time.Now().Add(-time.Second)
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *credentialRequests)
+}
+
+func TestNewEC2InstanceProfileProviderEnforcesIMDSv2(t *testing.T) {
+	credentialRequests := withIMDSServer(t, false)
+
+	provider, err := NewEC2InstanceProfileProvider(TestRegion)
+	assert.NoError(t, err)
+
+	_, err = provider.Token(Ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 0, *credentialRequests)
+}
+
+func TestNewEC2InstanceProfileProviderWithoutRegion(t *testing.T) {
+	_, err := NewEC2InstanceProfileProvider("")
+	assert.ErrorContains(t, err, "region cannot be empty")
+}
@@ -0,0 +1,70 @@
+package signer
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EMFMetricDuration  = "TokenGenerationDuration" // EMFMetricDuration is the metric holding the generation time in millis.
+	EMFMetricSuccess   = "TokenGenerationSuccess"  // EMFMetricSuccess is the metric counting successful generations.
+	EMFMetricFailure   = "TokenGenerationFailure"  // EMFMetricFailure is the metric counting failed generations.
+	EMFDimensionRegion = "Region"                  // EMFDimensionRegion is the dimension holding the signing region.
+)
+
+// Metric definition in the CloudWatch embedded metric format.
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// Metric directive telling CloudWatch which members of the log event are metrics.
+type emfMetricDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+// Metadata member of a CloudWatch embedded metric format log event.
+type emfMetadata struct {
+	Timestamp         int64                `json:"Timestamp"`
+	CloudWatchMetrics []emfMetricDirective `json:"CloudWatchMetrics"`
+}
+
+// Writes the metrics of a token generation as a CloudWatch embedded metric format log event when enabled.
+// Failures to write are ignored since metrics must never fail token generation.
+func emitEMFMetrics(options Options, region string, start time.Time, err error) {
+	if options.EMFNamespace == "" || options.EMFWriter == nil {
+		return
+	}
+
+	success, failure := 1, 0
+	if err != nil {
+		success, failure = 0, 1
+	}
+
+	event := map[string]interface{}{
+		"_aws": emfMetadata{
+			Timestamp: time.Now().UnixMilli(),
+			CloudWatchMetrics: []emfMetricDirective{{
+				Namespace:  options.EMFNamespace,
+				Dimensions: [][]string{{EMFDimensionRegion}},
+				Metrics: []emfMetric{
+					{Name: EMFMetricDuration, Unit: "Milliseconds"},
+					{Name: EMFMetricSuccess, Unit: "Count"},
+					{Name: EMFMetricFailure, Unit: "Count"},
+				},
+			}},
+		},
+		EMFDimensionRegion: region,
+		EMFMetricDuration:  time.Since(start).Milliseconds(),
+		EMFMetricSuccess:   success,
+		EMFMetricFailure:   failure,
+	}
+
+	line, marshalErr := json.Marshal(event)
+	if marshalErr != nil {
+		return
+	}
+	_, _ = options.EMFWriter.Write(append(line, '\n'))
+}
@@ -0,0 +1,64 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Captures embedded metric format events in buf instead of stdout.
+func withEMFBuffer(namespace string, buf *bytes.Buffer) Option {
+	return func(o *Options) {
+		o.EMFNamespace = namespace
+		o.EMFWriter = buf
+	}
+}
+
+func TestEMFMetricsOnTokenGenerated(t *testing.T) {
+	mockCreds := aws.Credentials{
+		AccessKeyID:     "TEST-EMF-ACCESS-KEY",
+		SecretAccessKey: "TEST-EMF-SECRET-KEY",
+	}
+	var buf bytes.Buffer
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion,
+		MockCredentialsProvider{credentials: mockCreds}, withEMFBuffer("MSK/Auth", &buf))
+	assert.NoError(t, err)
+
+	var event map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &event))
+	assert.Equal(t, TestRegion, event[EMFDimensionRegion])
+	assert.Equal(t, float64(1), event[EMFMetricSuccess])
+	assert.Equal(t, float64(0), event[EMFMetricFailure])
+	assert.Contains(t, event, EMFMetricDuration)
+
+	metadata := event["_aws"].(map[string]interface{})
+	assert.NotZero(t, metadata["Timestamp"])
+	directive := metadata["CloudWatchMetrics"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "MSK/Auth", directive["Namespace"])
+	assert.Equal(t, []interface{}{[]interface{}{EMFDimensionRegion}}, directive["Dimensions"])
+	assert.Len(t, directive["Metrics"], 3)
+}
+
+func TestEMFMetricsOnTokenGenerationFailed(t *testing.T) {
+	var buf bytes.Buffer
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, aws.AnonymousCredentials{},
+		withEMFBuffer("MSK/Auth", &buf))
+	assert.Error(t, err)
+
+	var event map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &event))
+	assert.Equal(t, float64(0), event[EMFMetricSuccess])
+	assert.Equal(t, float64(1), event[EMFMetricFailure])
+}
+
+func TestEMFMetricsDisabledByDefault(t *testing.T) {
+	options := resolveOptions(nil)
+
+	assert.Empty(t, options.EMFNamespace)
+	assert.Nil(t, options.EMFWriter)
+}
@@ -0,0 +1,88 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/endpointcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	containerCredentialsHost     = "http://169.254.170.2"                   // Host serving ECS relative credential URIs.
+	containerAuthTokenFileEnvVar = "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE" // File holding the container credentials auth token.
+)
+
+// Loads the SDK config from environment variables only, without reading the shared config and credentials files or
+// honoring AWS_PROFILE. Credentials are resolved from, in order, the access key environment variables, a web identity
+// token file, the container credentials endpoint and the EC2 instance metadata service. The sts throttle monitor of
+// the options is applied to the sts client exchanging a web identity token, and the dialer to every credentials
+// request.
+func loadEnvOnlyConfig(region string, options Options) (aws.Config, error) {
+	envConfig, err := config.NewEnvConfig()
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("unable to load environment config: %w", err)
+	}
+
+	cfg := newEnvSDKConfig(region, envConfig, options)
+	cfg.Credentials = aws.NewCredentialsCache(envCredentialsProvider(cfg, envConfig))
+
+	return cfg, nil
+}
+
+// Selects the credentials provider matching the credential source configured in the environment.
+func envCredentialsProvider(cfg aws.Config, envConfig config.EnvConfig) aws.CredentialsProvider {
+	switch {
+	case envConfig.Credentials.HasKeys():
+		return credentials.StaticCredentialsProvider{Value: envConfig.Credentials}
+
+	case envConfig.WebIdentityTokenFilePath != "":
+		return stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), envConfig.RoleARN,
+			stscreds.IdentityTokenFile(envConfig.WebIdentityTokenFilePath),
+			func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = envConfig.RoleSessionName
+			})
+
+	case envConfig.ContainerCredentialsEndpoint != "" || envConfig.ContainerCredentialsRelativePath != "":
+		endpoint := envConfig.ContainerCredentialsEndpoint
+		if endpoint == "" {
+			endpoint = containerCredentialsHost + envConfig.ContainerCredentialsRelativePath
+		}
+
+		return endpointcreds.New(endpoint, func(o *endpointcreds.Options) {
+			if cfg.HTTPClient != nil {
+				o.HTTPClient = cfg.HTTPClient
+			}
+			o.AuthorizationToken = envConfig.ContainerAuthorizationToken
+			if tokenFile := os.Getenv(containerAuthTokenFileEnvVar); tokenFile != "" {
+				o.AuthorizationTokenProvider = fileAuthTokenProvider(tokenFile)
+			}
+		})
+
+	default:
+		return ec2rolecreds.New(func(o *ec2rolecreds.Options) {
+			if cfg.HTTPClient != nil {
+				o.Client = imds.NewFromConfig(cfg)
+			}
+		})
+	}
+}
+
+// Reads the container credentials authorization token from a file on every request, as the file is rotated.
+type fileAuthTokenProvider string
+
+func (f fileAuthTokenProvider) GetToken() (string, error) {
+	token, err := os.ReadFile(string(f))
+	if err != nil {
+		return "", fmt.Errorf("failed to read container authorization token file: %w", err)
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
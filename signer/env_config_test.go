@@ -0,0 +1,68 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/endpointcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAuthTokenWithoutSharedConfigIgnoresProfile(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "profile-that-does-not-exist")
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-ENV-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-ENV-SECRET-KEY")
+
+	_, _, err := GenerateAuthToken(Ctx, TestRegion)
+	assert.Error(t, err)
+
+	token, expiryMs, err := GenerateAuthToken(Ctx, TestRegion, WithoutSharedConfig())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, int64(0), expiryMs)
+}
+
+func TestGenerateAuthTokenFromProfileWithoutSharedConfig(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromProfile(Ctx, TestRegion, "default", WithoutSharedConfig())
+
+	assert.ErrorIs(t, err, ErrSharedConfigDisabled)
+}
+
+func TestEnvCredentialsProviderSelection(t *testing.T) {
+	cfg := aws.Config{Region: TestRegion}
+
+	provider := envCredentialsProvider(cfg, config.EnvConfig{
+		Credentials: aws.Credentials{AccessKeyID: "TEST-ENV-ACCESS-KEY", SecretAccessKey: "TEST-ENV-SECRET-KEY"},
+	})
+	assert.IsType(t, credentials.StaticCredentialsProvider{}, provider)
+
+	provider = envCredentialsProvider(cfg, config.EnvConfig{
+		WebIdentityTokenFilePath: "/var/run/secrets/token",
+		RoleARN:                  "arn:aws:iam::123456789012:role/test",
+	})
+	assert.IsType(t, &stscreds.WebIdentityRoleProvider{}, provider)
+
+	provider = envCredentialsProvider(cfg, config.EnvConfig{ContainerCredentialsRelativePath: "/v2/credentials"})
+	assert.IsType(t, &endpointcreds.Provider{}, provider)
+
+	provider = envCredentialsProvider(cfg, config.EnvConfig{})
+	assert.IsType(t, &ec2rolecreds.Provider{}, provider)
+}
+
+func TestFileAuthTokenProvider(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("test-auth-token\n"), 0o600))
+
+	token, err := fileAuthTokenProvider(tokenFile).GetToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "test-auth-token", token)
+
+	_, err = fileAuthTokenProvider(filepath.Join(t.TempDir(), "missing")).GetToken()
+	assert.Error(t, err)
+}
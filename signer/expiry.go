@@ -0,0 +1,18 @@
+package signer
+
+import "time"
+
+// ComputeExpiry returns the time until which a token signed at signingTime with the X-Amz-Expires lifetime expiresIn
+// should be used, given a safety margin that accounts for clock skew and in-flight handshakes. The margin is capped at
+// the lifetime, so the expiry is never before the signing time. ComputeExpiry is the single definition of token
+// lifetime shared by the signer, the Provider cache and the client adapters.
+func ComputeExpiry(signingTime time.Time, expiresIn time.Duration, margin time.Duration) time.Time {
+	expiresIn = max(expiresIn, 0)
+	return signingTime.Add(expiresIn - min(max(margin, 0), expiresIn))
+}
+
+// Expiry returns the time until which the token should be used given the safety margin, i.e. the ExpirationTimeMs
+// computed by ComputeExpiry without a margin, minus the margin.
+func (t Token) Expiry(margin time.Duration) time.Time {
+	return time.UnixMilli(t.ExpirationTimeMs).Add(-max(margin, 0))
+}
@@ -0,0 +1,59 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeExpiry(t *testing.T) {
+	signingTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, signingTime.Add(15*time.Minute), ComputeExpiry(signingTime, 15*time.Minute, 0))
+	assert.Equal(t, signingTime.Add(14*time.Minute), ComputeExpiry(signingTime, 15*time.Minute, time.Minute))
+	assert.Equal(t, signingTime.Add(15*time.Minute), ComputeExpiry(signingTime, 15*time.Minute, -time.Minute))
+	assert.Equal(t, signingTime, ComputeExpiry(signingTime, time.Minute, time.Hour))
+	assert.Equal(t, signingTime, ComputeExpiry(signingTime, -time.Minute, 0))
+}
+
+func TestTokenExpiry(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC)
+	token := Token{ExpirationTimeMs: expiresAt.UnixMilli()}
+
+	assert.True(t, expiresAt.Equal(token.Expiry(0)))
+	assert.True(t, expiresAt.Add(-30*time.Second).Equal(token.Expiry(30*time.Second)))
+	assert.True(t, expiresAt.Equal(token.Expiry(-time.Second)))
+}
+
+func TestGeneratedTokenExpiryMatchesComputeExpiry(t *testing.T) {
+	before := time.Now().Truncate(time.Second)
+	token, err := GenerateToken(Ctx, TestRegion, testQueryCredentialsProvider)
+
+	assert.NoError(t, err)
+	assert.WithinRange(t, token.Expiry(0),
+		ComputeExpiry(before, DefaultExpirySeconds*time.Second, 0),
+		ComputeExpiry(time.Now(), DefaultExpirySeconds*time.Second, 0))
+}
+
+func TestGenerateTokenWithExpiryMargin(t *testing.T) {
+	token, err := GenerateToken(Ctx, TestRegion, testQueryCredentialsProvider)
+	assert.NoError(t, err)
+	shaved, err := GenerateToken(Ctx, TestRegion, testQueryCredentialsProvider, WithExpiryMargin(time.Minute))
+	assert.NoError(t, err)
+
+	assert.Equal(t, decodeTokenParams(t, token.Value).Get("X-Amz-Expires"),
+		decodeTokenParams(t, shaved.Value).Get("X-Amz-Expires"))
+	assert.InDelta(t, token.ExpirationTimeMs-time.Minute.Milliseconds(), shaved.ExpirationTimeMs,
+		float64(time.Second.Milliseconds()))
+}
+
+func TestExpiryMarginIsCappedAtTokenLifetime(t *testing.T) {
+	signedURL := "https://kafka.us-west-2.amazonaws.com/?Action=kafka-cluster%3AConnect" +
+		"&X-Amz-Date=20240101T000000Z&X-Amz-Expires=900"
+
+	expirationTimeMs, err := getExpirationTimeMs(signedURL, ExpiresQueryKey, time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), expirationTimeMs)
+}
@@ -0,0 +1,101 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// FaultStep names the step of token generation a FaultInjector is called before.
+type FaultStep string
+
+const (
+	// FaultStepLoadCredentials is the retrieval of the IAM credentials signing the token.
+	FaultStepLoadCredentials FaultStep = SpanLoadCredentials
+
+	// FaultStepSTS is every role assumption call to sts, made by role, web identity and IRSA credentials.
+	FaultStepSTS FaultStep = "STS"
+
+	// FaultStepSignToken is the signing of the auth token, locally or by a RemoteSigner.
+	FaultStepSignToken FaultStep = SpanSignToken
+)
+
+// ErrInjectedFault is returned by the failures of the FailStep fault injector.
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultInjector is called before the token generation steps to chaos test how Kafka clients behave when auth degrades.
+// It can block to delay the step, and fails the step with the error it returns. Fault injectors are meant for tests
+// and game days only, and are never called unless set with WithFaultInjector.
+type FaultInjector func(ctx context.Context, step FaultStep) error
+
+// FailStep returns a fault injector failing the step with ErrInjectedFault.
+func FailStep(step FaultStep) FaultInjector {
+	return func(_ context.Context, faultStep FaultStep) error {
+		if faultStep != step {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrInjectedFault, step)
+	}
+}
+
+// DelayStep returns a fault injector delaying the step by the delay, or failing it with the context error when the
+// context is done first.
+func DelayStep(step FaultStep, delay time.Duration) FaultInjector {
+	return func(ctx context.Context, faultStep FaultStep) error {
+		if faultStep != step {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Calls the fault injector of the options before the step, if any.
+func injectFault(ctx context.Context, options Options, step FaultStep) error {
+	if options.FaultInjector == nil {
+		return nil
+	}
+	return options.FaultInjector(ctx, step)
+}
+
+// Wraps the sts client to call the fault injector of the options before every role assumption.
+func withSTSFaults(client STSAPIClient, options Options) STSAPIClient {
+	if options.FaultInjector == nil {
+		return client
+	}
+	return &faultSTSClient{client: client, options: options}
+}
+
+// Calls the fault injector before delegating role assumption to the wrapped sts client.
+type faultSTSClient struct {
+	client  STSAPIClient
+	options Options
+}
+
+func (c *faultSTSClient) AssumeRole(
+	ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options),
+) (*sts.AssumeRoleOutput, error) {
+	if err := injectFault(ctx, c.options, FaultStepSTS); err != nil {
+		return nil, err
+	}
+	return c.client.AssumeRole(ctx, params, optFns...)
+}
+
+func (c *faultSTSClient) AssumeRoleWithWebIdentity(
+	ctx context.Context, params *sts.AssumeRoleWithWebIdentityInput, optFns ...func(*sts.Options),
+) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	if err := injectFault(ctx, c.options, FaultStepSTS); err != nil {
+		return nil, err
+	}
+	return c.client.AssumeRoleWithWebIdentity(ctx, params, optFns...)
+}
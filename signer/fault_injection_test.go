@@ -0,0 +1,86 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAuthTokenWithFailedLoadCredentials(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithFaultInjector(FailStep(FaultStepLoadCredentials)))
+
+	assert.ErrorIs(t, err, ErrInjectedFault)
+	assert.Empty(t, token)
+
+	var generationErr *TokenGenerationError
+	if assert.ErrorAs(t, err, &generationErr) {
+		assert.Equal(t, SpanLoadCredentials, generationErr.Steps[len(generationErr.Steps)-1].Name)
+	}
+}
+
+func TestGenerateAuthTokenWithFailedSignToken(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithFaultInjector(FailStep(FaultStepSignToken)))
+
+	assert.ErrorIs(t, err, ErrInjectedFault)
+
+	var generationErr *TokenGenerationError
+	if assert.ErrorAs(t, err, &generationErr) {
+		assert.Equal(t, SpanSignToken, generationErr.Steps[len(generationErr.Steps)-1].Name)
+	}
+}
+
+func TestGenerateAuthTokenFromRoleWithFailedSTS(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	client := &mockSTSClient{}
+
+	_, _, err := GenerateAuthTokenFromRole(Ctx, TestRegion, "arn:aws:iam::123456789012:role/kafka", "",
+		WithSTSClient(client), WithFaultInjector(FailStep(FaultStepSTS)))
+
+	assert.ErrorIs(t, err, ErrInjectedFault)
+	assert.Empty(t, client.roleARNs)
+}
+
+func TestGenerateAuthTokenFromWebIdentityWithFailedSTS(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	client := &mockSTSClient{}
+
+	_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, "arn:aws:iam::123456789012:role/kafka", "",
+		func(ctx context.Context) (string, error) {
+			return "web-identity-token", nil
+		}, WithSTSClient(client), WithFaultInjector(FailStep(FaultStepSTS)))
+
+	assert.ErrorIs(t, err, ErrInjectedFault)
+	assert.Empty(t, client.roleARNs)
+}
+
+func TestGenerateAuthTokenWithDelayedLoadCredentials(t *testing.T) {
+	start := time.Now()
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithFaultInjector(DelayStep(FaultStepLoadCredentials, 50*time.Millisecond)))
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestDelayStepHonorsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := DelayStep(FaultStepSTS, time.Hour)(ctx, FaultStepSTS)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFaultInjectorsIgnoreOtherSteps(t *testing.T) {
+	assert.NoError(t, FailStep(FaultStepSTS)(Ctx, FaultStepSignToken))
+	assert.NoError(t, DelayStep(FaultStepSTS, time.Hour)(Ctx, FaultStepSignToken))
+}
+
+func TestWithSTSFaultsWithoutFaultInjector(t *testing.T) {
+	client := &mockSTSClient{}
+	assert.Same(t, client, withSTSFaults(client, resolveOptions(nil)))
+}
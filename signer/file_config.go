@@ -0,0 +1,144 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FileConfigSource is a ConfigSource backed by a JSON file holding a SignerConfig. The file is read when the source is
+// created and again when Reload is called, on signals with ReloadOnSignal or on changes with WatchFile. Combined with
+// a ConfigProvider, edits to the file apply on the next token refresh without restarting consumers.
+type FileConfigSource struct {
+	path string
+
+	mu      sync.RWMutex
+	config  SignerConfig
+	modTime time.Time
+	size    int64
+}
+
+var _ ConfigSource = (*FileConfigSource)(nil)
+
+// NewFileConfigSource loads the signer configuration from the JSON file at path.
+func NewFileConfigSource(path string) (*FileConfigSource, error) {
+	s := &FileConfigSource{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Config returns the most recently loaded signer configuration.
+func (s *FileConfigSource) Config(ctx context.Context) (SignerConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config, nil
+}
+
+// Reload reads the configuration file again, keeping the previous configuration when the file cannot be read or holds
+// an invalid configuration.
+func (s *FileConfigSource) Reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read signer config file: %w", err)
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read signer config file: %w", err)
+	}
+
+	var cfg SignerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("failed to parse signer config file %s: %w", s.path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid signer config file %s: %w", s.path, err)
+	}
+
+	s.mu.Lock()
+	s.config, s.modTime, s.size = cfg, info.ModTime(), info.Size()
+	s.mu.Unlock()
+	return nil
+}
+
+// ReloadOnSignal reloads the configuration file every time the process receives one of the signals, SIGHUP when none
+// are given, until ctx is done. Failed reloads are reported to onError, unless nil, and the previous configuration
+// stays in use.
+func (s *FileConfigSource) ReloadOnSignal(ctx context.Context, onError func(err error), signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	go func() {
+		defer signal.Stop(received)
+		s.reloadOnSignals(ctx, received, onError)
+	}()
+}
+
+// Reloads the configuration file on every received signal until ctx is done.
+func (s *FileConfigSource) reloadOnSignals(ctx context.Context, received <-chan os.Signal, onError func(err error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-received:
+			reportReloadError(s.Reload(), onError)
+		}
+	}
+}
+
+// WatchFile checks the configuration file for changes every interval until ctx is done, reloading it when its
+// modification time or size changed. The file is polled rather than watched with inotify, so it also works on
+// volumes such as mounted Kubernetes config maps. Failed reloads are reported to onError, unless nil. The file is never
+// watched when interval is not positive.
+func (s *FileConfigSource) WatchFile(ctx context.Context, interval time.Duration, onError func(err error)) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if s.changed() {
+					reportReloadError(s.Reload(), onError)
+				}
+			}
+		}
+	}()
+}
+
+// Reports whether the modification time or size of the configuration file differ from the loaded one. A file that
+// cannot be stat'ed counts as changed, so the reload reports the error.
+func (s *FileConfigSource) changed() bool {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !info.ModTime().Equal(s.modTime) || info.Size() != s.size
+}
+
+// Reports the reload error to onError, unless either is nil.
+func reportReloadError(err error, onError func(err error)) {
+	if err != nil && onError != nil {
+		onError(err)
+	}
+}
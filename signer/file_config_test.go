@@ -0,0 +1,129 @@
+package signer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Replaces the signer config file atomically, so watchers never read a partial file, and returns its path.
+func writeConfigFile(t *testing.T, path string, content string) string {
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "signer.json")
+	}
+	assert.NoError(t, os.WriteFile(path+".tmp", []byte(content), 0o600))
+	assert.NoError(t, os.Rename(path+".tmp", path))
+	return path
+}
+
+// Delivers the signal to the reload loop of the source and waits for the loop to finish handling it.
+func reloadOnTestSignal(source *FileConfigSource, onError func(err error)) {
+	ctx, cancel := context.WithCancel(Ctx)
+	received := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		source.reloadOnSignals(ctx, received, onError)
+	}()
+
+	received <- syscall.SIGHUP
+	cancel()
+	<-done
+}
+
+func TestFileConfigSource(t *testing.T) {
+	path := writeConfigFile(t, "", `{"region": "us-west-2", "roleArn": "arn:aws:iam::123456789012:role/a"}`)
+
+	source, err := NewFileConfigSource(path)
+	assert.NoError(t, err)
+	cfg, err := source.Config(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, SignerConfig{Region: TestRegion, RoleARN: "arn:aws:iam::123456789012:role/a"}, cfg)
+}
+
+func TestNewFileConfigSourceFailsOnInvalidConfig(t *testing.T) {
+	_, err := NewFileConfigSource(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read signer config file")
+
+	_, err = NewFileConfigSource(writeConfigFile(t, "", `{"roleArn": "arn:aws:iam::123456789012:role/a"}`))
+	assert.ErrorContains(t, err, "region cannot be empty")
+}
+
+func TestFileConfigSourceReloadsOnSignal(t *testing.T) {
+	path := writeConfigFile(t, "", `{"region": "us-west-2"}`)
+	source, err := NewFileConfigSource(path)
+	assert.NoError(t, err)
+
+	writeConfigFile(t, path, `{"region": "eu-west-1", "expiry": "10m"}`)
+	reloadOnTestSignal(source, func(err error) {
+		t.Errorf("unexpected reload error: %s", err)
+	})
+
+	cfg, _ := source.Config(Ctx)
+	assert.Equal(t, SignerConfig{Region: "eu-west-1", Expiry: 10 * time.Minute}, cfg)
+}
+
+func TestFileConfigSourceKeepsConfigOnFailedReload(t *testing.T) {
+	path := writeConfigFile(t, "", `{"region": "us-west-2"}`)
+	source, err := NewFileConfigSource(path)
+	assert.NoError(t, err)
+
+	writeConfigFile(t, path, `{"region":`)
+	var reloadErr error
+	reloadOnTestSignal(source, func(err error) {
+		reloadErr = err
+	})
+
+	cfg, _ := source.Config(Ctx)
+	assert.Equal(t, TestRegion, cfg.Region)
+	assert.ErrorContains(t, reloadErr, "failed to parse signer config file")
+}
+
+func TestFileConfigSourceWatchFile(t *testing.T) {
+	path := writeConfigFile(t, "", `{"region": "us-west-2"}`)
+	source, err := NewFileConfigSource(path)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(Ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var reloadErrs []error
+	source.WatchFile(ctx, time.Millisecond, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reloadErrs = append(reloadErrs, err)
+	})
+	writeConfigFile(t, path, `{"region": "eu-central-1"}`)
+
+	assert.Eventually(t, func() bool {
+		cfg, _ := source.Config(Ctx)
+		return cfg.Region == "eu-central-1"
+	}, time.Second, time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Empty(t, reloadErrs)
+}
+
+func TestFileConfigSourceWatchFileWithoutInterval(t *testing.T) {
+	path := writeConfigFile(t, "", `{"region": "us-west-2"}`)
+	source, err := NewFileConfigSource(path)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(Ctx)
+	defer cancel()
+
+	source.WatchFile(ctx, 0, nil)
+	source.WatchFile(ctx, -time.Second, nil)
+	writeConfigFile(t, path, `{"region": "eu-central-1"}`)
+	time.Sleep(20 * time.Millisecond)
+
+	cfg, err := source.Config(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", cfg.Region)
+}
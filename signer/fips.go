@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFIPSNotEnabled is returned when FIPS validated crypto is required to sign auth tokens and the process does not
+// use it.
+var ErrFIPSNotEnabled = errors.New("fips validated crypto is not enabled")
+
+// FIPSModule names the FIPS validated crypto implementation signing auth tokens.
+type FIPSModule string
+
+const (
+	// FIPSModuleNone is reported when no FIPS validated crypto is in use.
+	FIPSModuleNone FIPSModule = ""
+
+	// FIPSModuleGo is the Go Cryptographic Module, enabled from Go 1.24 with the fips140 GODEBUG setting or by
+	// building with GOFIPS140.
+	FIPSModuleGo FIPSModule = "go-fips140"
+
+	// FIPSModuleBoringCrypto is BoringCrypto, enabled by building with GOEXPERIMENT=boringcrypto.
+	FIPSModuleBoringCrypto FIPSModule = "boringcrypto"
+)
+
+// FIPSEnabled reports whether auth tokens are signed with FIPS validated crypto. The signing path only uses FIPS
+// approved algorithms, HMAC-SHA256 and SHA-256, so it works unchanged in either module, including when the Go module
+// is set to reject non-approved algorithms with GODEBUG=fips140=only.
+func FIPSEnabled() bool {
+	return ActiveFIPSModule() != FIPSModuleNone
+}
+
+// ActiveFIPSModule returns the FIPS validated crypto implementation in use, or FIPSModuleNone, e.g. to record in
+// compliance attestations.
+func ActiveFIPSModule() FIPSModule {
+	switch {
+	case boringCryptoEnabled():
+		return FIPSModuleBoringCrypto
+	case goFIPS140Enabled():
+		return FIPSModuleGo
+	default:
+		return FIPSModuleNone
+	}
+}
+
+// FIPSRequiredBuild reports whether the signer was built with the fips build tag, which requires FIPS validated
+// crypto for every auth token regardless of the options.
+func FIPSRequiredBuild() bool {
+	return fipsRequiredBuild
+}
+
+// Fails with ErrFIPSNotEnabled when FIPS validated crypto is required by the build or the options and not in use.
+func enforceFIPS(options Options) error {
+	if (!fipsRequiredBuild && !options.RequireFIPS) || FIPSEnabled() {
+		return nil
+	}
+	return fmt.Errorf("%w: build with GOEXPERIMENT=boringcrypto, or run with GODEBUG=fips140=on on Go 1.24 or later",
+		ErrFIPSNotEnabled)
+}
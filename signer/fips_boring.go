@@ -0,0 +1,10 @@
+//go:build boringcrypto
+
+package signer
+
+import "crypto/boring"
+
+// Reports whether BoringCrypto handles the crypto operations.
+func boringCryptoEnabled() bool {
+	return boring.Enabled()
+}
@@ -0,0 +1,32 @@
+package signer
+
+import (
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	// FIPSEndpointEnvVar enables FIPS endpoints when set to true, unless WithFIPSEndpoint decides otherwise.
+	FIPSEndpointEnvVar = "AWS_USE_FIPS_ENDPOINT"
+
+	fipsEndpointURLTemplate = "kafka-fips.%s.%s" // Template of the FIPS variant of the kafka endpoint of a region.
+)
+
+// Resolves whether FIPS endpoints are used: the state set with WithFIPSEndpoint, else enabled when the
+// AWS_USE_FIPS_ENDPOINT environment variable is true, as the SDK reads it.
+func fipsEndpointState(options Options) aws.FIPSEndpointState {
+	if options.FIPSEndpoint != aws.FIPSEndpointStateUnset {
+		return options.FIPSEndpoint
+	}
+
+	switch strings.ToLower(os.Getenv(FIPSEndpointEnvVar)) {
+	case "true":
+		return aws.FIPSEndpointStateEnabled
+	case "false":
+		return aws.FIPSEndpointStateDisabled
+	default:
+		return aws.FIPSEndpointStateUnset
+	}
+}
@@ -0,0 +1,83 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenEndpointWithFIPSEndpoint(t *testing.T) {
+	endpoint, err := tokenEndpoint(TestRegion, resolveOptions([]Option{WithFIPSEndpoint(true)}))
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka-fips.us-west-2.amazonaws.com", endpoint)
+
+	endpoint, err = tokenEndpoint("us-gov-west-1", resolveOptions([]Option{WithFIPSEndpoint(true)}))
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka-fips.us-gov-west-1.amazonaws.com", endpoint)
+
+	endpoint, err = tokenEndpoint(TestRegion, resolveOptions([]Option{WithFIPSEndpoint(false)}))
+	assert.NoError(t, err)
+	assert.Equal(t, TestEndpoint, endpoint)
+}
+
+func TestTokenEndpointWithFIPSEndpointEnvVar(t *testing.T) {
+	t.Setenv(FIPSEndpointEnvVar, "TRUE")
+
+	endpoint, err := tokenEndpoint(TestRegion, resolveOptions(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka-fips.us-west-2.amazonaws.com", endpoint)
+
+	endpoint, err = tokenEndpoint(TestRegion, resolveOptions([]Option{WithFIPSEndpoint(false)}))
+	assert.NoError(t, err)
+	assert.Equal(t, TestEndpoint, endpoint)
+}
+
+func TestTokenEndpointPrefersEndpointOverFIPS(t *testing.T) {
+	endpoint, err := tokenEndpoint(TestRegion, resolveOptions([]Option{
+		WithFIPSEndpoint(true), WithEndpoint("kafka.my-proxy.internal"),
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka.my-proxy.internal", endpoint)
+}
+
+func TestGenerateAuthTokenWithFIPSEndpoint(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithFIPSEndpoint(true))
+	assert.NoError(t, err)
+
+	params, err := ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka-fips.us-west-2.amazonaws.com", params.Host)
+	assert.Equal(t, TestRegion, params.Region)
+}
+
+func TestRoleSTSClientWithFIPSEndpoint(t *testing.T) {
+	client := sts.NewFromConfig(aws.Config{Region: "us-east-1"},
+		roleSTSOptionFns(resolveOptions([]Option{WithFIPSEndpoint(true)}))...)
+	assert.Equal(t, aws.FIPSEndpointStateEnabled, client.Options().EndpointOptions.UseFIPSEndpoint)
+
+	endpoint, err := sts.NewDefaultEndpointResolverV2().ResolveEndpoint(Ctx, sts.EndpointParameters{
+		Region:            aws.String(client.Options().Region),
+		UseFIPS:           aws.Bool(true),
+		UseGlobalEndpoint: aws.Bool(false),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sts-fips.us-east-1.amazonaws.com", endpoint.URI.Host)
+}
+
+func TestRoleSTSClientPrefersSTSEndpointOverFIPS(t *testing.T) {
+	client := sts.NewFromConfig(aws.Config{Region: "us-east-1"}, roleSTSOptionFns(resolveOptions([]Option{
+		WithFIPSEndpoint(true), WithSTSRegion("us-east-1", "https://sts.internal"),
+	}))...)
+	assert.Equal(t, aws.FIPSEndpointStateUnset, client.Options().EndpointOptions.UseFIPSEndpoint)
+	assert.Equal(t, "https://sts.internal", aws.ToString(client.Options().BaseEndpoint))
+}
+
+func TestRoleSTSClientWithoutFIPSEndpoint(t *testing.T) {
+	t.Setenv(FIPSEndpointEnvVar, "")
+
+	client := sts.NewFromConfig(aws.Config{Region: "us-east-1"}, roleSTSOptionFns(resolveOptions(nil))...)
+	assert.Equal(t, aws.FIPSEndpointStateUnset, client.Options().EndpointOptions.UseFIPSEndpoint)
+}
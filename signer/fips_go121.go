@@ -0,0 +1,8 @@
+//go:build !go1.24
+
+package signer
+
+// The Go Cryptographic Module is only available from Go 1.24.
+func goFIPS140Enabled() bool {
+	return false
+}
@@ -0,0 +1,10 @@
+//go:build go1.24
+
+package signer
+
+import "crypto/fips140"
+
+// Reports whether the Go Cryptographic Module operates in FIPS 140-3 mode.
+func goFIPS140Enabled() bool {
+	return fips140.Enabled()
+}
@@ -0,0 +1,30 @@
+//go:build go1.24 && !boringcrypto
+
+package signer
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Set in the environment of the test process signing in the Go Cryptographic Module's FIPS-only mode.
+const fipsOnlyTestEnvVar = "MSK_SIGNER_FIPS_ONLY_TEST"
+
+func TestSigningInFIPSOnlyMode(t *testing.T) {
+	if os.Getenv(fipsOnlyTestEnvVar) == "" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestSigningInFIPSOnlyMode$", "-test.v")
+		cmd.Env = append(os.Environ(), fipsOnlyTestEnvVar+"=1", "GODEBUG=fips140=only")
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+		return
+	}
+
+	assert.Equal(t, FIPSModuleGo, ActiveFIPSModule())
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithFIPSRequired())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+}
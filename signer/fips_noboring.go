@@ -0,0 +1,8 @@
+//go:build !boringcrypto
+
+package signer
+
+// BoringCrypto is only available to Go+BoringCrypto builds.
+func boringCryptoEnabled() bool {
+	return false
+}
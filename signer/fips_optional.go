@@ -0,0 +1,6 @@
+//go:build !fips
+
+package signer
+
+// FIPS validated crypto is only required when the options ask for it.
+const fipsRequiredBuild = false
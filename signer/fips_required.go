@@ -0,0 +1,6 @@
+//go:build fips
+
+package signer
+
+// Building with the fips tag requires FIPS validated crypto for every auth token.
+const fipsRequiredBuild = true
@@ -0,0 +1,34 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFIPSEnabledMatchesActiveModule(t *testing.T) {
+	assert.Equal(t, ActiveFIPSModule() != FIPSModuleNone, FIPSEnabled())
+}
+
+func TestFIPSRequired(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithFIPSRequired())
+
+	if FIPSEnabled() {
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		return
+	}
+	assert.ErrorIs(t, err, ErrFIPSNotEnabled)
+	assert.Empty(t, token)
+}
+
+func TestFIPSNotRequiredByDefault(t *testing.T) {
+	if FIPSRequiredBuild() {
+		t.Skip("built with the fips tag")
+	}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider)
+
+	assert.NoError(t, err)
+}
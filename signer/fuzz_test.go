@@ -0,0 +1,135 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func FuzzEncodeAuthToken(f *testing.F) {
+	f.Add("https://kafka.us-west-2.amazonaws.com/?Action=kafka-cluster%3AConnect&X-Amz-Date=20240101T000000Z" +
+		"&X-Amz-Expires=900&X-Amz-Signature=abc")
+	f.Add("https://kafka.us-west-2.amazonaws.com/?X-Amz-Date=20240101T000000Z&X-Amz-Expires=-1")
+	f.Add("https://kafka.us-west-2.amazonaws.com/?X-Amz-Date=99999999T999999Z&X-Amz-Expires=9223372036854775807")
+	f.Add("%zz://\x00")
+	f.Add("")
+
+	mskProfile, err := resolveCompatibilityProfile(Options{})
+	assert.NoError(f, err)
+
+	f.Fuzz(func(t *testing.T, signedURL string) {
+		value, expirationTimeMs, err := encodeAuthToken(signedURL, mskProfile, Options{TokenEncoding: TokenEncodingRawURL})
+		if err != nil {
+			assert.Empty(t, value)
+			return
+		}
+
+		params := decodeTokenParams(t, value)
+		assert.NotEmpty(t, params.Get(UserAgentKey))
+		expiresIn, _ := strconv.ParseInt(params.Get(ExpiresQueryKey), 10, 64)
+		date, _ := time.Parse("20060102T150405Z", params.Get("X-Amz-Date"))
+		assert.Equal(t, ComputeExpiry(date, time.Duration(expiresIn)*time.Second, 0).UnixMilli(), expirationTimeMs)
+	})
+}
+
+func FuzzTokenUnmarshalJSON(f *testing.F) {
+	f.Add(`{"value":"abc","expiresAt":"2024-01-01T00:15:00Z","region":"us-west-2"}`)
+	f.Add(`{"value":"abc","expiresAt":"not a time"}`)
+	f.Add(`{"expiresAt":null}`)
+	f.Add(`[]`)
+
+	f.Fuzz(func(t *testing.T, data string) {
+		var token Token
+		if err := json.Unmarshal([]byte(data), &token); err != nil {
+			return
+		}
+
+		encoded, err := json.Marshal(token)
+		assert.NoError(t, err)
+
+		var decoded Token
+		assert.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, token.Value, decoded.Value)
+		assert.Equal(t, token.Expiry(0).Unix(), decoded.Expiry(0).Unix())
+	})
+}
+
+func FuzzSignerConfigUnmarshalJSON(f *testing.F) {
+	f.Add(`{"region":"us-west-2","expiry":"10m","maxExpiry":900}`)
+	f.Add(`{"region":"us-west-2","expiry":-9223372036854775808}`)
+	f.Add(`{"expiry":"1e400s"}`)
+
+	f.Fuzz(func(t *testing.T, data string) {
+		var cfg SignerConfig
+		if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+			return
+		}
+		_ = cfg.Validate()
+
+		encoded, err := json.Marshal(cfg)
+		assert.NoError(t, err)
+
+		var decoded SignerConfig
+		assert.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, cfg, decoded)
+	})
+}
+
+func FuzzParseBootstrapBrokers(f *testing.F) {
+	f.Add(testProvisionedBrokers)
+	f.Add(testServerlessBroker)
+	f.Add("[::1]:9098,kafka.amazonaws.com")
+	f.Add(",,,")
+
+	f.Fuzz(func(t *testing.T, bootstrapBrokers string) {
+		endpoints, err := ParseBootstrapBrokers(bootstrapBrokers)
+		if err != nil {
+			return
+		}
+
+		assert.NotEmpty(t, endpoints)
+		for _, endpoint := range endpoints {
+			assert.NotEmpty(t, endpoint.Region)
+			assert.Equal(t, endpoints[0].Region, endpoint.Region)
+		}
+	})
+}
+
+func FuzzGenerateDecodeRoundTrip(f *testing.F) {
+	regions := []string{"us-east-1", "eu-west-1", "ap-southeast-2", "cn-north-1"}
+	f.Add(uint8(0), uint32(12345678), "orders")
+	f.Add(uint8(1), uint32(0), "value 42 &=?/\u3000")
+	f.Add(uint8(2), uint32(99999999), "")
+	f.Add(uint8(3), uint32(1), "\xff\x00%zz+")
+
+	f.Fuzz(func(t *testing.T, regionIndex uint8, keyNumber uint32, param string) {
+		region := regions[int(regionIndex)%len(regions)]
+		keyID := fmt.Sprintf("TESTPROPERTY%08d", keyNumber%1e8)
+		credentialsProvider := MockCredentialsProvider{credentials: aws.Credentials{
+			AccessKeyID:     keyID,
+			SecretAccessKey: "TEST-PROPERTY-SECRET-KEY",
+		}}
+		start := time.Now().Truncate(time.Second)
+
+		token, expirationTimeMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, region, credentialsProvider,
+			WithSignedQueryParameter("property", param))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		params := decodeTokenParams(t, token)
+		assert.Equal(t, ActionName, params.Get(ActionType))
+		assert.Equal(t, param, params.Get("property"))
+		assert.Contains(t, params.Get("X-Amz-Credential"), keyID+"/")
+		assert.Contains(t, params.Get("X-Amz-Credential"), "/"+region+"/"+SigningName+"/")
+		assert.NotEmpty(t, params.Get("X-Amz-Signature"))
+		assert.WithinRange(t, time.UnixMilli(expirationTimeMs),
+			ComputeExpiry(start, DefaultExpirySeconds*time.Second, 0),
+			ComputeExpiry(time.Now(), DefaultExpirySeconds*time.Second, 0))
+	})
+}
@@ -0,0 +1,69 @@
+package signer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GenerationStep records the outcome of a token generation step.
+type GenerationStep struct {
+	// Name of the step, matching the span names SpanLoadCredentials and SpanSignToken.
+	Name string
+
+	// Detail describes what the step produced, e.g. where the credentials came from and until when they are valid.
+	Detail string
+
+	// Duration is how long the step took.
+	Duration time.Duration
+
+	// Err is the error the step failed with, nil when it succeeded.
+	Err error
+}
+
+// TokenGenerationError is returned when generating an auth token fails. It records the steps that ran, in order, so
+// error reports tell which credentials were in use without having to enable debug logging after the fact. Use
+// errors.As to retrieve it.
+type TokenGenerationError struct {
+	// Region the token was generated for.
+	Region string
+
+	// Steps that ran, the last one being the failed step.
+	Steps []GenerationStep
+}
+
+// Error returns the error of the failed step followed by the context of the steps that succeeded.
+func (e *TokenGenerationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Unwrap().Error())
+	sb.WriteString(" [region ")
+	sb.WriteString(e.Region)
+	for _, step := range e.Steps {
+		if step.Err == nil {
+			fmt.Fprintf(&sb, "; %s: %s", step.Name, step.Detail)
+		}
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// Unwrap returns the error of the failed step.
+func (e *TokenGenerationError) Unwrap() error {
+	return e.Steps[len(e.Steps)-1].Err
+}
+
+// Builds the error for a token generation that failed in its last step.
+func newTokenGenerationError(region string, steps ...GenerationStep) error {
+	return &TokenGenerationError{Region: region, Steps: steps}
+}
+
+// Describes the credentials loaded by the credential step.
+func describeCredentials(source string, canExpire bool, expires time.Time) string {
+	if source == "" {
+		source = "an unnamed provider"
+	}
+	if !canExpire {
+		return fmt.Sprintf("credentials from %s, not expiring", source)
+	}
+	return fmt.Sprintf("credentials from %s valid until %s", source, expires.UTC().Format(time.RFC3339))
+}
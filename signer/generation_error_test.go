@@ -0,0 +1,53 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenGenerationErrorOnCredentialFailure(t *testing.T) {
+	provider := &sequenceCredentialsProvider{
+		credentials: []aws.Credentials{expiringCredentials("TEST-EXPIRING-KEY", 5*time.Second)},
+	}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, provider,
+		WithCredentialExpiryPolicy(CredentialExpiryFail, 0))
+
+	var generationErr *TokenGenerationError
+	assert.True(t, errors.As(err, &generationErr))
+	assert.ErrorIs(t, err, ErrCredentialsExpiringSoon)
+	assert.Equal(t, TestRegion, generationErr.Region)
+	assert.Len(t, generationErr.Steps, 1)
+	assert.Equal(t, SpanLoadCredentials, generationErr.Steps[0].Name)
+	assert.ErrorContains(t, err, "failed to load credentials")
+}
+
+func TestTokenGenerationErrorRecordsLoadedCredentials(t *testing.T) {
+	expires := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "TEST-ACCESS-KEY", Source: "TestProvider", CanExpire: true, Expires: expires},
+			nil
+	})
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, provider)
+
+	var generationErr *TokenGenerationError
+	assert.True(t, errors.As(err, &generationErr))
+	assert.Len(t, generationErr.Steps, 2)
+	assert.NoError(t, generationErr.Steps[0].Err)
+	assert.Equal(t, SpanSignToken, generationErr.Steps[1].Name)
+	assert.ErrorContains(t, err, "aws credentials cannot be empty")
+	assert.ErrorContains(t, err,
+		"[region us-west-2; LoadCredentials: credentials from TestProvider valid until 2099-01-01T00:00:00Z]")
+}
+
+func TestDescribeCredentials(t *testing.T) {
+	assert.Equal(t, "credentials from EnvConfigCredentials, not expiring",
+		describeCredentials("EnvConfigCredentials", false, time.Time{}))
+	assert.Equal(t, "credentials from an unnamed provider, not expiring", describeCredentials("", false, time.Time{}))
+}
@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	// GitHubActionsTokenURLEnvVar holds the url the job requests its OIDC token from.
+	GitHubActionsTokenURLEnvVar = "ACTIONS_ID_TOKEN_REQUEST_URL"
+
+	// GitHubActionsTokenEnvVar holds the bearer token authorizing the OIDC token request.
+	GitHubActionsTokenEnvVar = "ACTIONS_ID_TOKEN_REQUEST_TOKEN"
+
+	// GitHubActionsDefaultAudience is the audience of the requested OIDC token when none is given.
+	GitHubActionsDefaultAudience = "sts.amazonaws.com"
+
+	// Upper bound of the token response read into memory.
+	maxGitHubActionsTokenResponse = 1 << 20
+)
+
+// GenerateAuthTokenFromGitHubActions generates base64 encoded signed url as auth token by assuming the role with the
+// OIDC token of the running GitHub Actions job. The job needs the id-token: write permission and the role a trust
+// policy for the token.actions.githubusercontent.com identity provider.
+func GenerateAuthTokenFromGitHubActions(
+	ctx context.Context, region string, roleArn string, optFns ...Option,
+) (string, int64, error) {
+	return GenerateAuthTokenFromWebIdentity(ctx, region, roleArn, "", GitHubActionsTokenFetcher("", optFns...),
+		optFns...)
+}
+
+// GitHubActionsTokenFetcher returns a WebIdentityTokenFetcher requesting the OIDC token of the running GitHub Actions
+// job for the audience, or for GitHubActionsDefaultAudience when empty. The token is requested with the dialer and
+// host resolver of optFns, like the credentials of the signer.
+func GitHubActionsTokenFetcher(audience string, optFns ...Option) WebIdentityTokenFetcher {
+	if audience == "" {
+		audience = GitHubActionsDefaultAudience
+	}
+	client := newCredentialsHTTPClient(resolveOptions(optFns), 0)
+
+	return func(ctx context.Context) (string, error) {
+		requestURL := os.Getenv(GitHubActionsTokenURLEnvVar)
+		requestToken := os.Getenv(GitHubActionsTokenEnvVar)
+		if requestURL == "" || requestToken == "" {
+			return "", fmt.Errorf("%s and %s must be set, is the id-token: write permission granted to the job",
+				GitHubActionsTokenURLEnvVar, GitHubActionsTokenEnvVar)
+		}
+
+		return fetchGitHubActionsToken(ctx, client, requestURL, requestToken, audience)
+	}
+}
+
+// Requests the OIDC token from the GitHub Actions token endpoint.
+func fetchGitHubActionsToken(
+	ctx context.Context, client aws.HTTPClient, requestURL string, requestToken string, audience string,
+) (string, error) {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", GitHubActionsTokenURLEnvVar, err)
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request github actions oidc token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGitHubActionsTokenResponse))
+	if err != nil {
+		return "", fmt.Errorf("failed to read github actions oidc token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("github actions oidc token request failed with status %s", resp.Status)
+	}
+
+	var tokenResponse struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", fmt.Errorf("failed to decode github actions oidc token: %w", err)
+	}
+	if tokenResponse.Value == "" {
+		return "", errors.New("github actions oidc token response has no value")
+	}
+
+	return tokenResponse.Value, nil
+}
@@ -0,0 +1,76 @@
+package signer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Serves GitHub Actions OIDC tokens for the expected bearer token and points the job environment at the server.
+func withGitHubActionsTokenServer(t *testing.T, oidcToken string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer TEST-REQUEST-TOKEN" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "1", r.URL.Query().Get("api-version"))
+		fmt.Fprintf(w, `{"count":1,"value":%q}`, oidcToken+":"+r.URL.Query().Get("audience"))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv(GitHubActionsTokenURLEnvVar, server.URL+"/token?api-version=1")
+	t.Setenv(GitHubActionsTokenEnvVar, "TEST-REQUEST-TOKEN")
+}
+
+func TestGitHubActionsTokenFetcher(t *testing.T) {
+	withGitHubActionsTokenServer(t, "TEST-GITHUB-OIDC-TOKEN")
+
+	token, err := GitHubActionsTokenFetcher("")(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-GITHUB-OIDC-TOKEN:"+GitHubActionsDefaultAudience, token)
+
+	token, err = GitHubActionsTokenFetcher("kafka")(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-GITHUB-OIDC-TOKEN:kafka", token)
+}
+
+func TestGitHubActionsTokenFetcherWithDialer(t *testing.T) {
+	withGitHubActionsTokenServer(t, "TEST-GITHUB-OIDC-TOKEN")
+	dialer, dials := newCountingDialer()
+
+	_, err := GitHubActionsTokenFetcher("", WithDialer(dialer))(Ctx)
+
+	assert.NoError(t, err)
+	assert.Positive(t, dials.Load())
+}
+
+func TestGitHubActionsTokenFetcherUnauthorized(t *testing.T) {
+	withGitHubActionsTokenServer(t, "TEST-GITHUB-OIDC-TOKEN")
+	t.Setenv(GitHubActionsTokenEnvVar, "TEST-WRONG-TOKEN")
+
+	_, err := GitHubActionsTokenFetcher("")(Ctx)
+
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestGitHubActionsTokenFetcherOutsideActions(t *testing.T) {
+	t.Setenv(GitHubActionsTokenURLEnvVar, "")
+	t.Setenv(GitHubActionsTokenEnvVar, "")
+
+	_, err := GitHubActionsTokenFetcher("")(Ctx)
+
+	assert.ErrorContains(t, err, "id-token: write")
+}
+
+func TestGenerateAuthTokenFromGitHubActions(t *testing.T) {
+	withGitHubActionsTokenServer(t, "TEST-GITHUB-OIDC-TOKEN")
+	withSTSWebIdentityServer(t, "TEST-GITHUB-OIDC-TOKEN:"+GitHubActionsDefaultAudience)
+
+	token, _, err := GenerateAuthTokenFromGitHubActions(Ctx, TestRegion, "arn:aws:iam::123456789012:role/TestRole")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+}
@@ -0,0 +1,70 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// HostResolver returns the addresses to connect to for a host, in order of preference, e.g. to pin the sts endpoints
+// in split-horizon DNS environments. Returning no addresses and no error resolves the host with the dialer as usual.
+type HostResolver func(ctx context.Context, host string) ([]netip.Addr, error)
+
+// PinHosts returns a resolver connecting to the pinned addresses of the hosts, e.g.
+// "sts.us-east-1.amazonaws.com" pinned to the addresses of an sts interface VPC endpoint. Hosts are matched case
+// insensitively, other hosts are resolved as usual.
+func PinHosts(pins map[string][]netip.Addr) HostResolver {
+	normalized := make(map[string][]netip.Addr, len(pins))
+	for host, addrs := range pins {
+		normalized[strings.ToLower(host)] = addrs
+	}
+	return func(_ context.Context, host string) ([]netip.Addr, error) {
+		return normalized[strings.ToLower(host)], nil
+	}
+}
+
+// Returns whether the options customize the HTTP clients retrieving credentials, so they must replace the SDK ones.
+func customizesCredentialsHTTPClient(options Options) bool {
+	return options.Dialer != nil || options.HostResolver != nil
+}
+
+// Returns a dial function connecting to the addresses the resolver returns for the host, in order, with the dialer.
+// Dial errors name the host and the addresses tried, so misresolved endpoints are told apart from authorization
+// failures.
+func resolvingDialContext(
+	dialer *net.Dialer, resolve HostResolver,
+) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := netip.ParseAddr(host); err == nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		addrs, err := resolve(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s with the host resolver: %w", host, err)
+		}
+		if len(addrs) == 0 {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		var errs []error
+		for _, addr := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return nil, fmt.Errorf("failed to connect to %s at the resolved addresses %v: %w", host, addrs, errors.Join(errs...))
+	}
+}
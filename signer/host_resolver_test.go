@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Points the sts clients at the web identity server through a host name that only resolves when pinned.
+func withPinnedSTSWebIdentityServer(t *testing.T) {
+	withSTSWebIdentityServer(t, "TEST-OIDC-TOKEN")
+	t.Setenv("AWS_ENDPOINT_URL_STS",
+		strings.Replace(os.Getenv("AWS_ENDPOINT_URL_STS"), "127.0.0.1", "sts.pinned.invalid", 1))
+}
+
+func TestGenerateAuthTokenWithPinnedHosts(t *testing.T) {
+	withPinnedSTSWebIdentityServer(t)
+	resolver := PinHosts(map[string][]netip.Addr{
+		"STS.pinned.invalid": {netip.MustParseAddr("127.0.0.1")},
+	})
+
+	token, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, "arn:aws:iam::123456789012:role/TestRole", "",
+		fetchTestWebIdentityToken, WithHostResolver(resolver))
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(QueryKeyCredential.Get(decodeTokenParams(t, token)), "TEST-WEB-IDENTITY-ACCESS-KEY/"))
+}
+
+func TestGenerateAuthTokenWithHostResolverError(t *testing.T) {
+	withPinnedSTSWebIdentityServer(t)
+	resolver := func(ctx context.Context, host string) ([]netip.Addr, error) {
+		return nil, errors.New("split horizon lookup failed")
+	}
+
+	_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, "arn:aws:iam::123456789012:role/TestRole", "",
+		fetchTestWebIdentityToken, WithHostResolver(resolver), WithSTSRetryer(aws.NopRetryer{}))
+
+	assert.ErrorContains(t, err,
+		"failed to resolve sts.pinned.invalid with the host resolver: split horizon lookup failed")
+}
+
+func TestResolvingDialContextNamesTriedAddresses(t *testing.T) {
+	resolver := PinHosts(map[string][]netip.Addr{"sts.pinned.invalid": {netip.MustParseAddr("127.0.0.1")}})
+	dial := resolvingDialContext(&net.Dialer{}, resolver)
+
+	_, err := dial(Ctx, "tcp", "sts.pinned.invalid:1")
+
+	assert.ErrorContains(t, err, "failed to connect to sts.pinned.invalid at the resolved addresses [127.0.0.1]")
+}
+
+func TestResolvingDialContextFallsBackToDialer(t *testing.T) {
+	server, _ := newHeaderRecordingServer(t, "")
+	dial := resolvingDialContext(&net.Dialer{}, PinHosts(nil))
+
+	conn, err := dial(Ctx, "tcp", server.Listener.Addr().String())
+
+	assert.NoError(t, err)
+	_ = conn.Close()
+}
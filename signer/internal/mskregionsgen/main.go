@@ -0,0 +1,160 @@
+// Command mskregionsgen generates the table of the regions MSK is available in from the endpoints metadata of the AWS
+// SDK kafka client, so that new MSK regions flow in without editing the table by hand. It is run by go generate in
+// the signer package:
+//
+//	go generate ./signer
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Module of the kafka client, and the path of its endpoints metadata in it.
+const (
+	kafkaModule   = "github.com/aws/aws-sdk-go-v2/service/kafka"
+	endpointsPath = "internal/endpoints/endpoints.go"
+)
+
+func main() {
+	version := flag.String("version", "latest", "version of the kafka client module to read the endpoints of")
+	output := flag.String("output", "regions_gen.go", "file to write the table to")
+	flag.Parse()
+
+	path, source, err := kafkaEndpointsFile(*version)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := generate(data, source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Downloads the version of the kafka client module, returning the path of its endpoints metadata and its module path
+// with the resolved version for the generated header. The module is not required by go.mod, so the signer doesn't
+// depend on the kafka client.
+func kafkaEndpointsFile(version string) (string, string, error) {
+	out, err := exec.Command("go", "mod", "download", "-json", kafkaModule+"@"+version).Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download %s@%s: %w", kafkaModule, version, err)
+	}
+
+	var module struct {
+		Dir     string
+		Version string
+	}
+	if err := json.Unmarshal(out, &module); err != nil {
+		return "", "", fmt.Errorf("failed to parse go mod download output: %w", err)
+	}
+	return filepath.Join(module.Dir, filepath.FromSlash(endpointsPath)), kafkaModule + "@" + module.Version, nil
+}
+
+// Generates the gofmt'ed source of the region table from the endpoints metadata, a Go file declaring the partitions
+// of the kafka client in defaultPartitions.
+func generate(data []byte, source string) ([]byte, error) {
+	regions, err := parseRegions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by mskregionsgen from %s/%s; DO NOT EDIT.\n\n", source, endpointsPath)
+	b.WriteString("package signer\n\n")
+	b.WriteString("// Regions MSK is available in, from the endpoints of every partition of the AWS SDK kafka client. " +
+		"Regions\n// launched later are accepted through the extra regions of WithStrictRegionValidation until " +
+		"generated here.\n")
+	b.WriteString("var mskRegions = map[string]struct{}{\n")
+	for _, region := range regions {
+		fmt.Fprintf(&b, "\t%q: {},\n", region)
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// Returns the sorted regions with an endpoint in defaultPartitions, leaving out the FIPS and dual-stack variants of
+// the endpoints and the deprecated fips- pseudo regions.
+func parseRegions(data []byte) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), endpointsPath, data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse endpoints metadata: %w", err)
+	}
+
+	var partitions *ast.CompositeLit
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if ok && len(spec.Names) == 1 && spec.Names[0].Name == "defaultPartitions" && len(spec.Values) == 1 {
+			partitions, _ = spec.Values[0].(*ast.CompositeLit)
+		}
+		return partitions == nil
+	})
+	if partitions == nil {
+		return nil, fmt.Errorf("no defaultPartitions in endpoints metadata")
+	}
+
+	var regions []string
+	for _, partition := range partitions.Elts {
+		endpoints, ok := field(partition, "Endpoints").(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, elt := range endpoints.Elts {
+			endpoint, ok := elt.(*ast.KeyValueExpr)
+			if !ok || field(endpoint.Key, "Variant") != nil || field(endpoint.Value, "Deprecated") != nil {
+				continue
+			}
+			literal, ok := field(endpoint.Key, "Region").(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				continue
+			}
+			region, err := strconv.Unquote(literal.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid region %s in endpoints metadata: %w", literal.Value, err)
+			}
+			regions = append(regions, region)
+		}
+	}
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("no regions in endpoints metadata")
+	}
+
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// Returns the value of the named field of a composite literal, or nil when the expression is not a composite literal
+// or doesn't set the field.
+func field(expr ast.Expr, name string) ast.Expr {
+	literal, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	for _, elt := range literal.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok && key.Name == name {
+				return kv.Value
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	data := []byte(`package endpoints
+
+var defaultPartitions = endpoints.Partitions{
+	{
+		ID: "aws",
+		Endpoints: endpoints.Endpoints{
+			endpoints.EndpointKey{
+				Region: "us-west-2",
+			}: endpoints.Endpoint{},
+			endpoints.EndpointKey{
+				Region:  "us-west-2",
+				Variant: endpoints.FIPSVariant,
+			}: {
+				Hostname: "kafka-fips.us-west-2.amazonaws.com",
+			},
+			endpoints.EndpointKey{
+				Region: "fips-us-west-2",
+			}: endpoints.Endpoint{
+				Deprecated: aws.TrueTernary,
+			},
+			endpoints.EndpointKey{
+				Region: "af-south-1",
+			}: endpoints.Endpoint{},
+		},
+	},
+	{
+		ID: "aws-cn",
+		Endpoints: endpoints.Endpoints{
+			endpoints.EndpointKey{
+				Region: "cn-north-1",
+			}: endpoints.Endpoint{},
+		},
+	},
+}
+`)
+
+	code, err := generate(data, "test")
+
+	assert.NoError(t, err)
+	assert.Regexp(t, `"af-south-1":\s+\{\},\n\s+"cn-north-1":\s+\{\},\n\s+"us-west-2":\s+\{\},\n\}`, string(code))
+	assert.NotContains(t, string(code), "fips-us-west-2")
+}
+
+func TestGenerateWithoutPartitions(t *testing.T) {
+	_, err := generate([]byte("package endpoints\n"), "test")
+
+	assert.ErrorContains(t, err, "no defaultPartitions")
+}
+
+func TestGeneratedTableIsUpToDate(t *testing.T) {
+	checkedIn, err := os.ReadFile("../../regions_gen.go")
+	assert.NoError(t, err)
+	version := regexp.MustCompile(`service/kafka@(\S+?)/`).FindSubmatch(checkedIn)
+	if !assert.NotNil(t, version) {
+		return
+	}
+	path, source, err := kafkaEndpointsFile(string(version[1]))
+	if err != nil {
+		t.Skip(err)
+	}
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	code, err := generate(data, source)
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(checkedIn), string(code), "run go generate ./signer instead of editing regions_gen.go")
+}
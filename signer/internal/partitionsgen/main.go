@@ -0,0 +1,111 @@
+// Command partitionsgen generates the partition table of the signer from the partitions metadata of the AWS SDK, so
+// that the DNS suffixes of new partitions and regions flow in with SDK upgrades. It is run by go generate in the
+// signer package:
+//
+//	go generate ./signer
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Module holding the partitions metadata, and the path of the metadata in it.
+const (
+	sdkModule      = "github.com/aws/aws-sdk-go-v2"
+	partitionsPath = "internal/endpoints/awsrulesfn/partitions.json"
+)
+
+// Partitions metadata of the SDK, limited to the fields the table needs.
+type partitionsFile struct {
+	Partitions []struct {
+		ID      string `json:"id"`
+		Outputs struct {
+			DNSSuffix string `json:"dnsSuffix"`
+		} `json:"outputs"`
+		RegionRegex string                     `json:"regionRegex"`
+		Regions     map[string]json.RawMessage `json:"regions"`
+	} `json:"partitions"`
+}
+
+func main() {
+	input := flag.String("input", "", "partitions.json to read, defaults to the one of the SDK module required by go.mod")
+	output := flag.String("output", "partitions_gen.go", "file to write the table to")
+	flag.Parse()
+
+	path, source := *input, *input
+	if path == "" {
+		var err error
+		path, source, err = sdkPartitionsFile()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := generate(data, source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Locates the partitions metadata of the SDK module required by go.mod, returning its path and its module path with
+// the version for the generated header.
+func sdkPartitionsFile() (string, string, error) {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}} {{.Version}}", sdkModule).Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to locate %s: %w", sdkModule, err)
+	}
+
+	dir, version, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	return filepath.Join(dir, filepath.FromSlash(partitionsPath)), sdkModule + "@" + version, nil
+}
+
+// Generates the gofmt'ed source of the partition table from the partitions metadata.
+func generate(data []byte, source string) ([]byte, error) {
+	var file partitionsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse partitions metadata: %w", err)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by partitionsgen from %s/%s; DO NOT EDIT.\n\n", source, partitionsPath)
+	b.WriteString("package signer\n\n")
+	b.WriteString("// Partitions of the AWS SDK partitions metadata, in metadata order.\n")
+	b.WriteString("var partitions = []partition{\n")
+	for _, p := range file.Partitions {
+		fmt.Fprintf(&b, "\t{id: %q, dnsSuffix: %q, regionRegex: %q},\n", p.ID, p.Outputs.DNSSuffix, p.RegionRegex)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// Index in partitions of the partition of every region listed in the metadata.\n")
+	b.WriteString("var partitionRegions = map[string]int{\n")
+	for i, p := range file.Partitions {
+		regions := make([]string, 0, len(p.Regions))
+		for region := range p.Regions {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+		for _, region := range regions {
+			fmt.Fprintf(&b, "\t%q: %d,\n", region, i)
+		}
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	data := []byte(`{"partitions": [
+		{"id": "aws", "outputs": {"dnsSuffix": "amazonaws.com"}, "regionRegex": "^us\\-\\w+\\-\\d+$",
+			"regions": {"us-west-2": {}, "us-east-1": {}}},
+		{"id": "aws-cn", "outputs": {"dnsSuffix": "amazonaws.com.cn"}, "regionRegex": "^cn\\-\\w+\\-\\d+$",
+			"regions": {"cn-north-1": {}}}
+	]}`)
+
+	code, err := generate(data, "test")
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(code), `{id: "aws-cn", dnsSuffix: "amazonaws.com.cn", regionRegex: "^cn\\-\\w+\\-\\d+$"},`)
+	assert.Regexp(t, `"us-east-1":\s+0,\n\s+"us-west-2":\s+0,\n\s+"cn-north-1":\s+1,`, string(code))
+}
+
+func TestGeneratedTableIsUpToDate(t *testing.T) {
+	path, source, err := sdkPartitionsFile()
+	if err != nil {
+		t.Skip(err)
+	}
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	checkedIn, err := os.ReadFile("../../partitions_gen.go")
+	assert.NoError(t, err)
+
+	code, err := generate(data, source)
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(checkedIn), string(code), "run go generate ./signer after upgrading the SDK")
+}
@@ -0,0 +1,97 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	IRSARoleARNEnvVar         = "AWS_ROLE_ARN"                // IRSARoleARNEnvVar holds the role of the service account.
+	IRSATokenFileEnvVar       = "AWS_WEB_IDENTITY_TOKEN_FILE" // IRSATokenFileEnvVar holds the projected token path.
+	IRSARoleSessionNameEnvVar = "AWS_ROLE_SESSION_NAME"       // IRSARoleSessionNameEnvVar holds the session name.
+	IRSARegionEnvVar          = "AWS_REGION"                  // IRSARegionEnvVar holds the region of the pod.
+)
+
+const (
+	// DefaultIRSATokenOverlap is how long before rotation the IRSA preset generates the next token.
+	DefaultIRSATokenOverlap = time.Minute
+
+	// DefaultIRSAMinRefreshInterval is the minimum refresh interval of the IRSA preset.
+	DefaultIRSAMinRefreshInterval = 10 * time.Second
+)
+
+// ErrIRSANotConfigured is returned by NewIRSAProvider when the pod has no IAM role for service accounts injected.
+var ErrIRSANotConfigured = errors.New("iam roles for service accounts are not configured, " +
+	IRSARoleARNEnvVar + " and " + IRSATokenFileEnvVar + " must be set")
+
+// NewIRSAProvider returns a Provider configured with the recommended settings for EKS pods using IAM roles for service
+// accounts:
+//
+//   - the role is assumed with the projected service account token, read from AWS_WEB_IDENTITY_TOKEN_FILE on every
+//     assumption, using the regional sts endpoint of the signing region,
+//   - the assumed role credentials are cached until DefaultCredentialsExpiryWindow before they expire, with jitter so
+//     replicas do not refresh together, and refreshed once more when about to expire,
+//   - tokens are refreshed ahead of expiry, generating the next token DefaultIRSATokenOverlap before rotation, and are
+//     kept for at least DefaultIRSAMinRefreshInterval.
+//
+// The region defaults to AWS_REGION when empty. optFns are applied after the preset and can override it.
+func NewIRSAProvider(region string, optFns ...Option) (*Provider, error) {
+	roleARN, tokenFile := os.Getenv(IRSARoleARNEnvVar), os.Getenv(IRSATokenFileEnvVar)
+	if roleARN == "" || tokenFile == "" {
+		return nil, ErrIRSANotConfigured
+	}
+
+	if region == "" {
+		region = os.Getenv(IRSARegionEnvVar)
+	}
+	if region == "" {
+		return nil, fmt.Errorf("region cannot be empty, pass one or set %s", IRSARegionEnvVar)
+	}
+
+	sessionName := os.Getenv(IRSARoleSessionNameEnvVar)
+	if sessionName == "" {
+		sessionName = DefaultSessionName
+	}
+
+	optFns = append([]Option{
+		WithCredentialExpiryPolicy(CredentialExpiryRefresh, DefaultMinCredentialLifetime),
+		WithTokenOverlap(DefaultIRSATokenOverlap),
+		WithMinRefreshInterval(DefaultIRSAMinRefreshInterval),
+	}, optFns...)
+	options := resolveOptions(optFns)
+	if err := validateRoleAssumption(region, roleARN, options); err != nil {
+		return nil, err
+	}
+	if options.STSThrottleMonitor == nil {
+		options.STSThrottleMonitor = NewSTSThrottleMonitor()
+		optFns = append(optFns, WithSTSThrottleMonitor(options.STSThrottleMonitor))
+	}
+
+	webIdentity := stscreds.NewWebIdentityRoleProvider(irsaSTSClient(stsRegion(region, options), options), roleARN,
+		stscreds.IdentityTokenFile(tokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+			o.RoleSessionName = sessionName
+		})
+	return NewProvider(region, newJitteredCredentialsCache(webIdentity), optFns...), nil
+}
+
+// Returns the sts client configured in the options, or a client of the sts endpoint of the options or of the region.
+// Web identity role assumption is unsigned, so no credentials are loaded for it.
+func irsaSTSClient(region string, options Options) stscreds.AssumeRoleWithWebIdentityAPIClient {
+	if options.STSClient != nil {
+		return withSTSFaults(options.STSClient, options)
+	}
+
+	// Malformed client settings in the environment are ignored rather than failing the provider construction.
+	envConfig, err := config.NewEnvConfig()
+	if err != nil {
+		envConfig = config.EnvConfig{}
+	}
+	return withSTSFaults(sts.NewFromConfig(newEnvSDKConfig(region, envConfig, options), roleSTSOptionFns(options)...),
+		options)
+}
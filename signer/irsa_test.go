@@ -0,0 +1,76 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Sets the environment of a pod with an IAM role for service accounts injected.
+func setIRSAEnv(t *testing.T, roleARN string) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("projected-service-account-token"), 0o600))
+
+	t.Setenv(IRSARoleARNEnvVar, roleARN)
+	t.Setenv(IRSATokenFileEnvVar, tokenFile)
+	t.Setenv(IRSARoleSessionNameEnvVar, "kafka-pod")
+	t.Setenv(IRSARegionEnvVar, TestRegion)
+}
+
+func TestNewIRSAProvider(t *testing.T) {
+	roleARN := "arn:aws:iam::123456789012:role/kafka-irsa"
+	setIRSAEnv(t, roleARN)
+	client := &mockSTSClient{}
+
+	provider, err := NewIRSAProvider("", WithSTSClient(client))
+	assert.NoError(t, err)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, TestRegion, token.Region)
+	assert.Equal(t, "TEST-STS-CLIENT-ACCESS-KEY", token.KeyID)
+	assert.Equal(t, []string{roleARN}, client.roleARNs)
+
+	assert.Equal(t, DefaultIRSATokenOverlap, provider.options.TokenOverlap)
+	assert.Equal(t, DefaultIRSAMinRefreshInterval, provider.options.MinRefreshInterval)
+	assert.Equal(t, CredentialExpiryRefresh, provider.options.CredentialExpiryPolicy)
+	assert.NotNil(t, provider.options.STSThrottleMonitor)
+}
+
+func TestNewIRSAProviderOptionsOverridePreset(t *testing.T) {
+	setIRSAEnv(t, "arn:aws:iam::123456789012:role/kafka-irsa")
+
+	provider, err := NewIRSAProvider("eu-west-1", WithTokenOverlap(0), WithSTSClient(&mockSTSClient{}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", provider.region)
+	assert.Equal(t, time.Duration(0), provider.options.TokenOverlap)
+}
+
+func TestNewIRSAProviderWithoutIRSA(t *testing.T) {
+	t.Setenv(IRSARoleARNEnvVar, "")
+	t.Setenv(IRSATokenFileEnvVar, "")
+
+	_, err := NewIRSAProvider(TestRegion)
+	assert.ErrorIs(t, err, ErrIRSANotConfigured)
+
+	setIRSAEnv(t, "arn:aws:iam::123456789012:role/kafka-irsa")
+	t.Setenv(IRSARegionEnvVar, "")
+	_, err = NewIRSAProvider("")
+	assert.ErrorContains(t, err, "region cannot be empty")
+}
+
+func TestNewIRSAProviderHonorsSTSEndpointURL(t *testing.T) {
+	setIRSAEnv(t, "arn:aws:iam::123456789012:role/kafka-irsa")
+	withSTSWebIdentityServer(t, "projected-service-account-token")
+
+	provider, err := NewIRSAProvider("")
+	assert.NoError(t, err)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-WEB-IDENTITY-ACCESS-KEY", token.KeyID)
+}
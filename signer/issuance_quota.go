@@ -0,0 +1,50 @@
+package signer
+
+import (
+	"fmt"
+	"time"
+)
+
+// IssuanceQuotaError is returned by a Provider when generating a token would exceed its issuance quota, a safety brake
+// against reconnect loops exhausting sts quotas or flooding brokers with handshakes. Use errors.As to retrieve it.
+type IssuanceQuotaError struct {
+	// Max is the number of tokens the Provider may generate per window.
+	Max int
+
+	// Window is the sliding window the quota applies to.
+	Window time.Duration
+
+	// RetryAfter is how long until the quota allows the next token.
+	RetryAfter time.Duration
+}
+
+// Error returns the quota and when it allows the next token.
+func (e *IssuanceQuotaError) Error() string {
+	return fmt.Sprintf("token issuance quota of %d per %s exceeded, retry after %s", e.Max, e.Window, e.RetryAfter)
+}
+
+// Records a token generation attempt at now against the issuance quota, failing with an IssuanceQuotaError when the
+// quota is exhausted. Attempts are counted whether they succeed or not, as each one may call sts. The caller holds
+// p.mu.
+func (p *Provider) takeIssuanceQuotaLocked(now time.Time) error {
+	limit, window := p.options.IssuanceQuota, p.options.IssuanceQuotaWindow
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+
+	windowStart := now.Add(-window)
+	kept := p.issuedAt[:0]
+	for _, issuedAt := range p.issuedAt {
+		if issuedAt.After(windowStart) {
+			kept = append(kept, issuedAt)
+		}
+	}
+	p.issuedAt = kept
+
+	if len(p.issuedAt) >= limit {
+		return &IssuanceQuotaError{Max: limit, Window: window, RetryAfter: p.issuedAt[0].Sub(windowStart)}
+	}
+
+	p.issuedAt = append(p.issuedAt, now)
+	return nil
+}
@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderIssuanceQuota(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider(WithIssuanceQuota(2, time.Hour))
+
+	_, err := provider.ForceRefresh(Ctx)
+	assert.NoError(t, err)
+	_, err = provider.ForceRefresh(Ctx)
+	assert.NoError(t, err)
+	_, err = provider.ForceRefresh(Ctx)
+
+	var quotaErr *IssuanceQuotaError
+	assert.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, 2, quotaErr.Max)
+	assert.Equal(t, time.Hour, quotaErr.Window)
+	assert.Greater(t, quotaErr.RetryAfter, 59*time.Minute)
+	assert.Equal(t, 2, credentialsProvider.calls)
+}
+
+func TestProviderIssuanceQuotaSlides(t *testing.T) {
+	provider, _ := newCountingProvider(WithIssuanceQuota(1, time.Hour))
+	_, err := provider.ForceRefresh(Ctx)
+	assert.NoError(t, err)
+
+	provider.issuedAt[0] = provider.issuedAt[0].Add(-time.Hour)
+	_, err = provider.ForceRefresh(Ctx)
+
+	assert.NoError(t, err)
+}
+
+func TestProviderIssuanceQuotaServesCachedToken(t *testing.T) {
+	provider, _ := newCountingProvider(WithIssuanceQuota(1, time.Hour))
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	second, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Same(t, first, second)
+}
+
+func TestProviderIssuanceQuotaWithStaleFallback(t *testing.T) {
+	provider, _ := newCountingProvider(WithIssuanceQuota(1, time.Hour), WithRefreshStrategy(alwaysRefreshStrategy{}),
+		WithStaleTokenFallback(nil))
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	second, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+}
@@ -0,0 +1,62 @@
+package signer
+
+import "time"
+
+// Number of leading characters of the signature kept in issuance records, enough to correlate a record with a token
+// presented to a broker but not to reconstruct it.
+const issuanceRecordSignaturePrefixLen = 8
+
+// IssuanceRecord describes an issued auth token for compliance archiving. It holds no secrets: the signature is
+// truncated and the session token left out, so the token cannot be reconstructed from the record.
+type IssuanceRecord struct {
+	// Time is when the token was issued.
+	Time time.Time `json:"time"`
+
+	// Region is the region the token was signed for.
+	Region string `json:"region"`
+
+	// AccessKeyID is the access key id the token was signed with, identifying the principal. It is empty for tokens
+	// signed by a RemoteSigner.
+	AccessKeyID string `json:"accessKeyId,omitempty"`
+
+	// TemporaryCredentials reports whether the token was signed with temporary credentials carrying a session token.
+	TemporaryCredentials bool `json:"temporaryCredentials"`
+
+	// Source is the source of the credentials the token was signed with, as reported by the credentials provider.
+	Source string `json:"source,omitempty"`
+
+	// SigningTime is the signing time of the token.
+	SigningTime time.Time `json:"signingTime"`
+
+	// ExpiresAt is when the token expires.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// SignaturePrefix is the first characters of the signature of the token.
+	SignaturePrefix string `json:"signaturePrefix"`
+
+	// ClusterARN is the cluster ARN signed into the token, if any.
+	ClusterARN string `json:"clusterArn,omitempty"`
+}
+
+// Reports the issued token to the issuance recorder of the options, if any, describing it from its raw value.
+func recordIssuance(token *Token, raw string, options Options) {
+	if options.IssuanceRecorder == nil {
+		return
+	}
+
+	record := IssuanceRecord{
+		Time:        time.Now().UTC(),
+		Region:      token.Region,
+		AccessKeyID: token.KeyID,
+		Source:      token.Source,
+		ExpiresAt:   token.Expiry(0).UTC(),
+		ClusterARN:  options.ClusterARN,
+	}
+	if params, err := ParseToken(raw); err == nil {
+		record.AccessKeyID = params.AccessKeyID
+		record.TemporaryCredentials = params.SecurityToken != ""
+		record.SigningTime = params.SigningTime
+		record.SignaturePrefix = params.Signature[:min(len(params.Signature), issuanceRecordSignaturePrefixLen)]
+	}
+	options.IssuanceRecorder(record)
+}
@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssuanceRecorder(t *testing.T) {
+	var records []IssuanceRecord
+	credentialsProvider := MockCredentialsProvider{credentials: aws.Credentials{
+		AccessKeyID:     "ASIATESTRECORDKEY",
+		SecretAccessKey: "TEST-RECORD-SECRET-KEY",
+		SessionToken:    "TEST-RECORD-SESSION-TOKEN",
+		Source:          "TestProvider",
+	}}
+
+	token, expiryMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, credentialsProvider,
+		WithClusterARN("arn:aws:kafka:us-west-2:123456789012:cluster/orders/abc-123"),
+		WithIssuanceRecorder(func(record IssuanceRecord) {
+			records = append(records, record)
+		}))
+	assert.NoError(t, err)
+
+	assert.Len(t, records, 1)
+	record := records[0]
+	params := decodeTokenParams(t, token)
+	assert.Equal(t, TestRegion, record.Region)
+	assert.Equal(t, "ASIATESTRECORDKEY", record.AccessKeyID)
+	assert.True(t, record.TemporaryCredentials)
+	assert.Equal(t, "TestProvider", record.Source)
+	assert.Equal(t, expiryMs, record.ExpiresAt.UnixMilli())
+	assert.Equal(t, QueryKeyDate.Get(params), record.SigningTime.Format(SigningTimeFormat))
+	assert.Equal(t, "arn:aws:kafka:us-west-2:123456789012:cluster/orders/abc-123", record.ClusterARN)
+	assert.WithinDuration(t, time.Now(), record.Time, time.Minute)
+	assert.Len(t, record.SignaturePrefix, 8)
+	assert.Equal(t, QueryKeySignature.Get(params)[:8], record.SignaturePrefix)
+}
+
+func TestIssuanceRecorderWithTokenEnvelope(t *testing.T) {
+	var record IssuanceRecord
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithTokenEnvelope(prefixEnvelope{}), WithIssuanceRecorder(func(r IssuanceRecord) { record = r }))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-QUERY-ACCESS-KEY", record.AccessKeyID)
+	assert.Len(t, record.SignaturePrefix, 8)
+}
+
+func TestIssuanceRecorderSkipsRejectedTokens(t *testing.T) {
+	recorded := false
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithTokenValidators(AccessKeyIDPrefix("ASIA")),
+		WithIssuanceRecorder(func(IssuanceRecord) { recorded = true }))
+
+	assert.ErrorIs(t, err, ErrTokenPolicyViolation)
+	assert.False(t, recorded)
+}
@@ -0,0 +1,93 @@
+package signer
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	LogKeyEvent      = "event"       // LogKeyEvent is the log field holding the name of the token lifecycle event.
+	LogKeyRegion     = "region"      // LogKeyRegion is the log field holding the region the token was signed for.
+	LogKeyPrincipal  = "principal"   // LogKeyPrincipal is the log field holding the access key id used for signing.
+	LogKeyExpiry     = "expiry"      // LogKeyExpiry is the log field holding the expiration time of the token.
+	LogKeyDurationMs = "duration_ms" // LogKeyDurationMs is the log field holding how long the event took in millis.
+	LogKeyError      = "error"       // LogKeyError is the log field holding the error of a failed event.
+)
+
+const (
+	EventTokenGenerated        = "token_generated"         // EventTokenGenerated is logged when a token was minted.
+	EventTokenGenerationFailed = "token_generation_failed" // EventTokenGenerationFailed is logged when minting failed.
+	EventTokenWriteFailed      = "token_write_failed"      // EventTokenWriteFailed is logged when streaming failed.
+	EventStaleTokenServed      = "stale_token_served"      // EventStaleTokenServed is logged when falling back.
+	EventColdStartStoreFailed  = "cold_start_store_failed" // EventColdStartStoreFailed is logged on store failures.
+)
+
+// Logs a successfully generated token.
+func logTokenGenerated(
+	ctx context.Context, logger *slog.Logger, region string, principal string, expirationTimeMs int64, start time.Time,
+) {
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "generated msk auth token",
+		slog.String(LogKeyEvent, EventTokenGenerated),
+		slog.String(LogKeyRegion, region),
+		slog.String(LogKeyPrincipal, principal),
+		slog.Time(LogKeyExpiry, time.UnixMilli(expirationTimeMs).UTC()),
+		slog.Int64(LogKeyDurationMs, time.Since(start).Milliseconds()),
+	)
+}
+
+// Logs a failed token generation.
+func logTokenGenerationFailed(ctx context.Context, logger *slog.Logger, region string, err error, start time.Time) {
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelError, "failed to generate msk auth token",
+		slog.String(LogKeyEvent, EventTokenGenerationFailed),
+		slog.String(LogKeyRegion, region),
+		slog.Int64(LogKeyDurationMs, time.Since(start).Milliseconds()),
+		slog.String(LogKeyError, err.Error()),
+	)
+}
+
+// Logs a failure to write a new token to the token writer.
+func logTokenWriteFailed(ctx context.Context, logger *slog.Logger, err error) {
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelWarn, "failed to write msk auth token to stream",
+		slog.String(LogKeyEvent, EventTokenWriteFailed),
+		slog.String(LogKeyError, err.Error()),
+	)
+}
+
+// Logs that a stale token is returned because generating a new one failed.
+func logStaleTokenServed(ctx context.Context, logger *slog.Logger, token *Token, err error) {
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelWarn, "returning stale msk auth token after failing to generate a new one",
+		slog.String(LogKeyEvent, EventStaleTokenServed),
+		slog.String(LogKeyRegion, token.Region),
+		slog.Time(LogKeyExpiry, token.Expiry(0).UTC()),
+		slog.String(LogKeyError, err.Error()),
+	)
+}
+
+// Logs a failure to load or save the state of the cold start store.
+func logColdStartStoreFailed(ctx context.Context, logger *slog.Logger, operation string, err error) {
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(ctx, slog.LevelWarn, "failed to "+operation+" msk auth token cold start state",
+		slog.String(LogKeyEvent, EventColdStartStoreFailed),
+		slog.String(LogKeyError, err.Error()),
+	)
+}
@@ -0,0 +1,46 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLoggingOnTokenGenerated(t *testing.T) {
+	mockCreds := aws.Credentials{
+		AccessKeyID:     "TEST-LOG-ACCESS-KEY",
+		SecretAccessKey: "TEST-LOG-SECRET-KEY",
+	}
+	var buf bytes.Buffer
+
+	_, expiryMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion,
+		MockCredentialsProvider{credentials: mockCreds}, WithJSONLogging(&buf))
+	assert.NoError(t, err)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, EventTokenGenerated, entry[LogKeyEvent])
+	assert.Equal(t, TestRegion, entry[LogKeyRegion])
+	assert.Equal(t, mockCreds.AccessKeyID, entry[LogKeyPrincipal])
+	assert.Equal(t, time.UnixMilli(expiryMs).UTC().Format(time.RFC3339Nano), entry[LogKeyExpiry])
+	assert.Contains(t, entry, LogKeyDurationMs)
+}
+
+func TestJSONLoggingOnTokenGenerationFailed(t *testing.T) {
+	var buf bytes.Buffer
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, aws.AnonymousCredentials{},
+		WithJSONLogging(&buf))
+	assert.Error(t, err)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, EventTokenGenerationFailed, entry[LogKeyEvent])
+	assert.Equal(t, TestRegion, entry[LogKeyRegion])
+	assert.Equal(t, err.Error(), entry[LogKeyError])
+	assert.NotContains(t, entry, LogKeyPrincipal)
+}
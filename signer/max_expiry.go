@@ -0,0 +1,63 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxServiceExpiry is the longest token expiry accepted, the longest lifetime of a SigV4 presigned request.
+const MaxServiceExpiry = 7 * 24 * time.Hour
+
+// ErrInvalidExpiry is returned when the requested token expiry is shorter than a second or longer than
+// MaxServiceExpiry.
+var ErrInvalidExpiry = errors.New("invalid token expiry")
+
+// ErrExpiryExceedsMax is returned when the requested token expiry is longer than the configured maximum expiry and the
+// max expiry policy does not allow clamping it.
+var ErrExpiryExceedsMax = errors.New("token expiry exceeds the maximum expiry")
+
+// MaxExpiryPolicy decides what happens when the requested token expiry is longer than the maximum expiry.
+type MaxExpiryPolicy int
+
+const (
+	// MaxExpiryReject fails token generation with ErrExpiryExceedsMax. This is the default.
+	MaxExpiryReject MaxExpiryPolicy = iota
+
+	// MaxExpiryClamp signs the token with the maximum expiry instead of the requested one.
+	MaxExpiryClamp
+)
+
+// Resolves the expiry in seconds signed into the auth token, the requested expiry or DefaultExpirySeconds, applying
+// the maximum expiry guardrail.
+func tokenExpirySeconds(options Options) (int, error) {
+	expiry := DefaultExpirySeconds * time.Second
+	if options.Expiry != 0 {
+		if err := validateExpiry(options.Expiry); err != nil {
+			return 0, err
+		}
+		expiry = options.Expiry.Truncate(time.Second)
+	}
+	if options.MaxExpiry <= 0 || expiry <= options.MaxExpiry {
+		return int(expiry / time.Second), nil
+	}
+
+	if options.MaxExpiryPolicy != MaxExpiryClamp {
+		return 0, fmt.Errorf("%w: %s is longer than %s", ErrExpiryExceedsMax, expiry, options.MaxExpiry)
+	}
+	if options.MaxExpiry < time.Second {
+		return 0, fmt.Errorf("%w: %s is shorter than a second", ErrExpiryExceedsMax, options.MaxExpiry)
+	}
+	return int(options.MaxExpiry / time.Second), nil
+}
+
+// Validates that the requested expiry is at least a second and at most MaxServiceExpiry.
+func validateExpiry(expiry time.Duration) error {
+	if expiry < time.Second {
+		return fmt.Errorf("%w: %s is shorter than a second", ErrInvalidExpiry, expiry)
+	}
+	if expiry > MaxServiceExpiry {
+		return fmt.Errorf("%w: %s is longer than the service maximum of %s", ErrInvalidExpiry, expiry, MaxServiceExpiry)
+	}
+	return nil
+}
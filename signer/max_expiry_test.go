@@ -0,0 +1,70 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAuthTokenWithinMaxExpiry(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithMaxExpiry(time.Hour, MaxExpiryReject))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "900", decodeTokenParams(t, token).Get(ExpiresQueryKey))
+}
+
+func TestGenerateAuthTokenRejectsExpiryOverMax(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithMaxExpiry(5*time.Minute, MaxExpiryReject))
+
+	assert.ErrorIs(t, err, ErrExpiryExceedsMax)
+}
+
+func TestGenerateAuthTokenClampsExpiryToMax(t *testing.T) {
+	before := time.Now()
+	token, expiryMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithMaxExpiry(5*time.Minute+500*time.Millisecond, MaxExpiryClamp))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "300", decodeTokenParams(t, token).Get(ExpiresQueryKey))
+	assert.WithinDuration(t, before.Add(5*time.Minute), time.UnixMilli(expiryMs), 2*time.Second)
+}
+
+func TestGenerateAuthTokenClampRejectsSubSecondMax(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithMaxExpiry(time.Millisecond, MaxExpiryClamp))
+
+	assert.ErrorIs(t, err, ErrExpiryExceedsMax)
+}
+
+func TestGenerateAuthTokenWithExpiry(t *testing.T) {
+	before := time.Now()
+	token, expiryMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithExpiry(5*time.Minute+500*time.Millisecond))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "300", decodeTokenParams(t, token).Get(ExpiresQueryKey))
+	assert.WithinDuration(t, before.Add(5*time.Minute), time.UnixMilli(expiryMs), 2*time.Second)
+}
+
+func TestGenerateAuthTokenRejectsInvalidExpiry(t *testing.T) {
+	for _, expiry := range []time.Duration{-time.Minute, time.Millisecond, MaxServiceExpiry + time.Second} {
+		_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+			WithExpiry(expiry))
+
+		assert.ErrorIs(t, err, ErrInvalidExpiry, expiry.String())
+	}
+}
+
+func TestGenerateAuthTokenAppliesMaxExpiryToRequestedExpiry(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithExpiry(time.Hour), WithMaxExpiry(30*time.Minute, MaxExpiryReject))
+	assert.ErrorIs(t, err, ErrExpiryExceedsMax)
+
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithExpiry(time.Hour), WithMaxExpiry(30*time.Minute, MaxExpiryClamp))
+	assert.NoError(t, err)
+	assert.Equal(t, "1800", decodeTokenParams(t, token).Get(ExpiresQueryKey))
+}
@@ -3,20 +3,19 @@ package signer
 import (
 	"context"
 	"crypto/sha256"
-	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 
 	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
@@ -31,120 +30,325 @@ const (
 	ExpiresQueryKey      = "X-Amz-Expires"              // ExpiresQueryKey represents the key for the expiration time in the query parameters.
 	DefaultSessionName   = "MSKSASLDefaultSession"      // DefaultSessionName represents the default session name for assuming a role.
 	DefaultExpirySeconds = 900                          // DefaultExpirySeconds represents the default expiration time in seconds.
+	AppIDEnvVar          = "AWS_SDK_UA_APP_ID"          // AppIDEnvVar holds the app id appended to the user agent by default.
 )
 
 var (
-	endpointURLTemplate = "kafka.%s.amazonaws.com" // endpointURLTemplate represents the template for the Kafka endpoint URL
-	AwsDebugCreds       = false                    // AwsDebugCreds flag indicates whether credentials should be debugged
+	endpointURLTemplate = "kafka.%s.%s" // endpointURLTemplate represents the template for the Kafka endpoint URL
+	AwsDebugCreds       = false         // AwsDebugCreds flag indicates whether credentials should be debugged
 )
 
 // GenerateAuthToken generates base64 encoded signed url as auth token from default credentials.
 // Loads the IAM credentials from default credentials provider chain.
-func GenerateAuthToken(ctx context.Context, region string) (string, int64, error) {
-	credentials, err := loadDefaultCredentials(ctx, region)
-
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to load credentials: %w", err)
-	}
-
-	return constructAuthToken(ctx, region, credentials)
+func GenerateAuthToken(ctx context.Context, region string, optFns ...Option) (string, int64, error) {
+	options := resolveOptions(optFns)
+	return unpackToken(generateAuthToken(ctx, region, options, defaultCredentialsLoader(region, options)))
 }
 
 // GenerateAuthTokenFromProfile generates base64 encoded signed url as auth token by loading IAM credentials from an AWS named profile.
-func GenerateAuthTokenFromProfile(ctx context.Context, region string, awsProfile string) (string, int64, error) {
-	credentials, err := loadCredentialsFromProfile(ctx, region, awsProfile)
-
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to load credentials: %w", err)
+// Errors loading credentials from profiles backed by AWS IAM Identity Center (SSO) are returned as an SSOProfileError
+// telling how to refresh the SSO login.
+func GenerateAuthTokenFromProfile(
+	ctx context.Context, region string, awsProfile string, optFns ...Option,
+) (string, int64, error) {
+	options := resolveOptions(optFns)
+	loadCredentials := func(ctx context.Context, _ bool) (*aws.Credentials, error) {
+		return loadCredentialsFromProfile(ctx, region, awsProfile, options)
 	}
-
-	return constructAuthToken(ctx, region, credentials)
+	return unpackToken(generateAuthToken(ctx, region, options, loadCredentials))
 }
 
 // GenerateAuthTokenFromRole generates base64 encoded signed url as auth token by loading IAM credentials from an aws role Arn
 func GenerateAuthTokenFromRole(
-	ctx context.Context, region string, roleArn string, stsSessionName string,
+	ctx context.Context, region string, roleArn string, stsSessionName string, optFns ...Option,
 ) (string, int64, error) {
 	if stsSessionName == "" {
 		stsSessionName = DefaultSessionName
 	}
-	credentials, err := loadCredentialsFromRoleArn(ctx, region, roleArn, stsSessionName)
 
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to load credentials: %w", err)
+	options := resolveOptions(optFns)
+	loadCredentials := func(ctx context.Context, _ bool) (*aws.Credentials, error) {
+		return loadCredentialsFromRoleArn(ctx, region, roleArn, stsSessionName, options)
 	}
-
-	return constructAuthToken(ctx, region, credentials)
+	return unpackToken(generateAuthToken(ctx, region, options, loadCredentials))
 }
 
 // GenerateAuthTokenFromCredentialsProvider generates base64 encoded signed url as auth token by loading IAM credentials
 // from an aws credentials provider
 func GenerateAuthTokenFromCredentialsProvider(
-	ctx context.Context, region string, credentialsProvider aws.CredentialsProvider,
+	ctx context.Context, region string, credentialsProvider aws.CredentialsProvider, optFns ...Option,
 ) (string, int64, error) {
-	credentials, err := loadCredentialsFromCredentialsProvider(ctx, credentialsProvider)
+	options := resolveOptions(optFns)
+	return unpackToken(generateAuthToken(ctx, region, options, credentialsProviderLoader(credentialsProvider)))
+}
+
+// GenerateAuthTokenFromConfig generates base64 encoded signed url as auth token for the region of the aws config, signed
+// with the credentials of its credentials provider, so applications reuse the config they built at startup instead of
+// loading one for every token. An empty region is resolved as for GenerateAuthToken. The app id of the config, if any,
+// is appended to the user agent of the token unless WithUserAgentSuffix overrides it.
+func GenerateAuthTokenFromConfig(ctx context.Context, cfg aws.Config, optFns ...Option) (string, int64, error) {
+	if cfg.Credentials == nil {
+		return "", 0, fmt.Errorf("aws config credentials provider cannot be nil")
+	}
+	if cfg.AppID != "" {
+		optFns = append([]Option{WithUserAgentSuffix(appIDUserAgentSuffix(cfg.AppID))}, optFns...)
+	}
+	return GenerateAuthTokenFromCredentialsProvider(ctx, cfg.Region, cfg.Credentials, optFns...)
+}
+
+// GenerateAuthTokenWithOptions generates base64 encoded signed url as auth token configured by the options alone, so
+// new features are added as options rather than new functions. Tokens are signed for the region of WithRegion,
+// resolved as for GenerateAuthToken when empty, with IAM credentials loaded by assuming the role of WithRoleARN, from
+// the named profile of WithProfile, or from the default credentials provider chain.
+func GenerateAuthTokenWithOptions(ctx context.Context, optFns ...Option) (string, int64, error) {
+	options := resolveOptions(optFns)
+	if options.RoleARN != "" && options.Profile != "" {
+		return "", 0, fmt.Errorf("role arn and profile options cannot be used together")
+	}
+
+	region := options.Region
+	loadCredentials := defaultCredentialsLoader(region, options)
+	switch {
+	case options.RoleARN != "":
+		loadCredentials = roleCredentialsLoader(region, options)
+	case options.Profile != "":
+		loadCredentials = func(ctx context.Context, _ bool) (*aws.Credentials, error) {
+			return loadCredentialsFromProfile(ctx, region, options.Profile, options)
+		}
+	}
+	return unpackToken(generateAuthToken(ctx, region, options, loadCredentials))
+}
+
+// Loads the IAM credentials used to sign the auth token. When forceRefresh is set, cached credentials must not be
+// reused. The default chain, profile and role loaders resolve fresh credentials on every call and ignore it.
+type credentialsLoader func(ctx context.Context, forceRefresh bool) (*aws.Credentials, error)
+
+// Builds the loader assuming the role of the options with its session name, or DefaultSessionName when empty.
+func roleCredentialsLoader(region string, options Options) credentialsLoader {
+	sessionName := options.RoleSessionName
+	if sessionName == "" {
+		sessionName = DefaultSessionName
+	}
+	return func(ctx context.Context, _ bool) (*aws.Credentials, error) {
+		return loadCredentialsFromRoleArn(ctx, region, options.RoleARN, sessionName, options)
+	}
+}
+
+// Builds the loader retrieving credentials from the default credential chain.
+func defaultCredentialsLoader(region string, options Options) credentialsLoader {
+	return func(ctx context.Context, _ bool) (*aws.Credentials, error) {
+		return loadDefaultCredentials(ctx, region, options)
+	}
+}
+
+// Builds the loader retrieving credentials from the credentials provider, invalidating its cache on forced refresh.
+func credentialsProviderLoader(credentialsProvider aws.CredentialsProvider) credentialsLoader {
+	return func(ctx context.Context, forceRefresh bool) (*aws.Credentials, error) {
+		if forceRefresh {
+			invalidateCredentials(credentialsProvider)
+		}
+		return loadCredentialsFromCredentialsProvider(ctx, credentialsProvider)
+	}
+}
+
+// Generates the auth token from the credentials returned by the loader and reports the outcome to the configured logger,
+// or to the logger of the context when none is configured, and to metrics.
+func generateAuthToken(
+	ctx context.Context, region string, options Options, loadCredentials credentialsLoader,
+) (*Token, error) {
+	options = withContextLogger(ctx, options)
+	region, err := resolveRegion(ctx, region, options)
+	return reportTokenGeneration(ctx, region, options, func() (*Token, string, error) {
+		if err != nil {
+			return nil, "", err
+		}
+		return mintAuthToken(ctx, region, options, loadCredentials)
+	})
+}
+
+// Mints the auth token and reports the outcome to the configured logger and metrics.
+func reportTokenGeneration(
+	ctx context.Context, region string, options Options, mint func() (*Token, string, error),
+) (*Token, error) {
+	start := time.Now()
+
+	token, principal, err := mint()
+	if err == nil {
+		err = finishToken(ctx, token, options)
+	}
+	if err != nil {
+		logTokenGenerationFailed(ctx, options.Logger, region, err, start)
+		emitEMFMetrics(options, region, start, err)
+		return nil, err
+	}
+
+	logTokenGenerated(ctx, options.Logger, region, principal, token.ExpirationTimeMs, start)
+	warnNonDefaultTokenEncoding(ctx, options)
+	emitEMFMetrics(options, region, start, nil)
+	return token, nil
+}
+
+// Applies the policies of the options to the minted token in order: validates it, wraps it in the envelope and records
+// its issuance.
+func finishToken(ctx context.Context, token *Token, options Options) error {
+	raw := token.Value
+	if err := validateToken(token, options); err != nil {
+		return err
+	}
+	if err := wrapToken(ctx, token, options); err != nil {
+		return err
+	}
+	recordIssuance(token, raw, options)
+	return nil
+}
+
+// Loads the credentials and signs the auth token with them, returning the access key id used as principal.
+func mintAuthToken(
+	ctx context.Context, region string, options Options, loadCredentials credentialsLoader,
+) (*Token, string, error) {
+	if err := validateRegion(region, options); err != nil {
+		return nil, "", err
+	}
+	if err := enforceFIPS(options); err != nil {
+		return nil, "", err
+	}
+
+	fetchStart := time.Now()
+	var credentials *aws.Credentials
+	var err error
+	profileGenerationStep(ctx, SpanLoadCredentials, region, "", func(ctx context.Context) {
+		spanCtx, endSpan := startSpan(ctx, options.Tracer, SpanLoadCredentials)
+		err = injectFault(spanCtx, options, FaultStepLoadCredentials)
+		if err == nil {
+			credentials, err = loadCredentialsWithExpiryPolicy(spanCtx, options, loadCredentials)
+		}
+		if err == nil {
+			err = enforceSessionTokenPolicy(credentials, options)
+		}
+		endSpan(err)
+	})
+	credentialFetchDuration := time.Since(fetchStart)
+	if err != nil {
+		return nil, "", newTokenGenerationError(region, GenerationStep{
+			Name:     SpanLoadCredentials,
+			Duration: credentialFetchDuration,
+			Err:      fmt.Errorf("failed to load credentials: %w", annotateOptInRegionError(region, err)),
+		})
+	}
+	loadStep := GenerationStep{
+		Name:     SpanLoadCredentials,
+		Detail:   describeCredentials(credentials.Source, credentials.CanExpire, credentials.Expires),
+		Duration: credentialFetchDuration,
+	}
 
+	signStart := time.Now()
+	var value string
+	var expirationTimeMs int64
+	profileGenerationStep(ctx, SpanSignToken, region, credentials.Source, func(ctx context.Context) {
+		spanCtx, endSpan := startSpan(ctx, options.Tracer, SpanSignToken)
+		err = injectFault(spanCtx, options, FaultStepSignToken)
+		if err == nil {
+			value, expirationTimeMs, err = constructAuthToken(spanCtx, region, credentials, options)
+		}
+		endSpan(err)
+	})
+	signDuration := time.Since(signStart)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to load credentials: %w", err)
+		return nil, "", newTokenGenerationError(region, loadStep,
+			GenerationStep{Name: SpanSignToken, Duration: signDuration, Err: err})
 	}
 
-	return constructAuthToken(ctx, region, credentials)
+	token := &Token{
+		Value:                   value,
+		ExpirationTimeMs:        expirationTimeMs,
+		CredentialFetchDuration: credentialFetchDuration,
+		SignDuration:            signDuration,
+		Region:                  region,
+		KeyID:                   credentials.AccessKeyID,
+		Source:                  credentials.Source,
+	}
+	return token, credentials.AccessKeyID, nil
 }
 
-// Loads credentials from the default credential chain.
-func loadDefaultCredentials(ctx context.Context, region string) (*aws.Credentials, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+// Loads credentials from the default credential chain. Failures are returned as a CredentialChainError.
+func loadDefaultCredentials(ctx context.Context, region string, options Options) (*aws.Credentials, error) {
+	cfg, err := loadConfig(ctx, region, options)
 
 	if err != nil {
 		return nil, fmt.Errorf("unable to load SDK config: %w", err)
 	}
 
-	return loadCredentialsFromCredentialsProvider(ctx, cfg.Credentials)
+	credentials, err := loadCredentialsFromCredentialsProvider(ctx, cfg.Credentials)
+	if err != nil {
+		return nil, newCredentialChainError(ctx, options, err)
+	}
+	return credentials, nil
 }
 
 // Loads credentials from a named aws profile.
-func loadCredentialsFromProfile(ctx context.Context, region string, awsProfile string) (*aws.Credentials, error) {
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(region),
-		config.WithSharedConfigProfile(awsProfile),
-	)
+func loadCredentialsFromProfile(
+	ctx context.Context, region string, awsProfile string, options Options,
+) (*aws.Credentials, error) {
+	if options.DisableSharedConfig {
+		return nil, ErrSharedConfigDisabled
+	}
+
+	cfg, err := loadConfig(ctx, region, options, config.WithSharedConfigProfile(awsProfile))
 
 	if err != nil {
 		return nil, fmt.Errorf("unable to load SDK config: %w", err)
 	}
 
-	return loadCredentialsFromCredentialsProvider(ctx, cfg.Credentials)
+	creds, err := loadCredentialsFromCredentialsProvider(ctx, cfg.Credentials)
+	if err != nil {
+		return creds, annotateSSOProfileError(ctx, awsProfile, err)
+	}
+	return creds, nil
 }
 
 // Loads credentials from a named by assuming the passed role.
-// This implementation creates a new sts client for every call to get or refresh token. In order to avoid this, please
-// use your own credentials provider.
-// If you wish to use regional endpoint, please pass your own credentials provider.
+// This implementation creates a new sts client for every call to get or refresh token, unless one is passed with
+// WithSTSClient. In order to avoid this, please use your own credentials provider or sts client.
+// If you wish to use regional endpoint, please pass your own credentials provider or sts client.
 func loadCredentialsFromRoleArn(
-	ctx context.Context, region string, roleArn string, stsSessionName string,
+	ctx context.Context, region string, roleArn string, stsSessionName string, options Options,
 ) (*aws.Credentials, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if options.SourceIdentity != "" {
+		if err := validateSourceIdentity(options.SourceIdentity); err != nil {
+			return nil, err
+		}
+	}
 
+	stsClient, err := roleSTSClient(ctx, region, roleArn, options)
 	if err != nil {
-		return nil, fmt.Errorf("unable to load SDK config: %w", err)
+		return nil, err
 	}
 
-	stsClient := sts.NewFromConfig(cfg)
-
 	assumeRoleInput := &sts.AssumeRoleInput{
 		RoleArn:         aws.String(roleArn),
 		RoleSessionName: aws.String(stsSessionName),
 	}
+	if options.SourceIdentity != "" {
+		assumeRoleInput.SourceIdentity = aws.String(options.SourceIdentity)
+	}
 	assumeRoleOutput, err := stsClient.AssumeRole(ctx, assumeRoleInput)
 	if err != nil {
 		return nil, fmt.Errorf("unable to assume role, %s: %w", roleArn, err)
 	}
 
+	if assumeRoleOutput.Credentials == nil {
+		return nil, fmt.Errorf("unable to assume role, %s: no credentials returned", roleArn)
+	}
+
 	//Create new aws.Credentials instance using the credentials from AssumeRoleOutput.Credentials
 	creds := aws.Credentials{
-		AccessKeyID:     *assumeRoleOutput.Credentials.AccessKeyId,
-		SecretAccessKey: *assumeRoleOutput.Credentials.SecretAccessKey,
-		SessionToken:    *assumeRoleOutput.Credentials.SessionToken,
+		AccessKeyID:     aws.ToString(assumeRoleOutput.Credentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(assumeRoleOutput.Credentials.SecretAccessKey),
+		SessionToken:    aws.ToString(assumeRoleOutput.Credentials.SessionToken),
+	}
+	if assumeRoleOutput.Credentials.Expiration != nil {
+		creds.CanExpire = true
+		creds.Expires = *assumeRoleOutput.Credentials.Expiration
 	}
 
 	return &creds, nil
@@ -159,45 +363,91 @@ func loadCredentialsFromCredentialsProvider(
 }
 
 // Constructs Auth Token.
-func constructAuthToken(ctx context.Context, region string, credentials *aws.Credentials) (string, int64, error) {
-	endpointURL := fmt.Sprintf(endpointURLTemplate, region)
-
+func constructAuthToken(
+	ctx context.Context, region string, credentials *aws.Credentials, options Options,
+) (string, int64, error) {
 	if credentials == nil || credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
 		return "", 0, fmt.Errorf("aws credentials cannot be empty")
 	}
 
 	if AwsDebugCreds {
-		logCallerIdentity(ctx, region, *credentials)
+		logCallerIdentity(ctx, region, *credentials, options)
+	}
+
+	params, err := extraQueryParameters(region, options)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid query parameters: %w", err)
+	}
+
+	endpointURL, err := tokenEndpoint(region, options)
+	if err != nil {
+		return "", 0, err
+	}
+
+	expirySeconds, err := tokenExpirySeconds(options)
+	if err != nil {
+		return "", 0, err
 	}
 
-	req, err := buildRequest(DefaultExpirySeconds, endpointURL)
+	profile, err := resolveCompatibilityProfile(options)
+	if err != nil {
+		return "", 0, err
+	}
+
+	signingTime, err := resolveSigningTime(ctx, region, credentials, options)
+	if err != nil {
+		return "", 0, err
+	}
+
+	req, err := buildRequest(expirySeconds, endpointURL, params, profile)
 	if err != nil {
 		return "", 0, fmt.Errorf("failed to build request for signing: %w", err)
 	}
 
-	signedURL, err := signRequest(ctx, req, region, credentials)
+	signedURL, err := signRequest(ctx, req, region, credentials, signingTime, options)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to sign request with aws sig v4: %w", err)
+		return "", 0, fmt.Errorf("failed to sign request: %w", err)
 	}
+	logSignedURL(ctx, options, signedURL)
+
+	return encodeAuthToken(signedURL, profile, options)
+}
 
-	expirationTimeMs, err := getExpirationTimeMs(signedURL)
+// Encodes the presigned url into the auth token with the token encoding of the options, returning it with its
+// expiration time in millis shortened by the expiry margin of the options. The query of the url is encoded canonically,
+// so the same signed request always yields the same token bytes.
+func encodeAuthToken(signedURL string, profile CompatibilityProfile, options Options) (string, int64, error) {
+	expirationTimeMs, err := getExpirationTimeMs(signedURL, profile.ExpiresKey, options.ExpiryMargin)
 	if err != nil {
 		return "", 0, fmt.Errorf("failed to extract expiration from signed url: %w", err)
 	}
 
-	signedURLWithUserAgent, err := addUserAgent(signedURL)
+	if profile.OmitUserAgent {
+		canonicalURL, err := canonicalizeURL(signedURL)
+		if err != nil {
+			return "", 0, err
+		}
+		return base64Encode(canonicalURL, options.TokenEncoding), expirationTimeMs, nil
+	}
+
+	signedURLWithUserAgent, err := addUserAgent(signedURL, profile.UserAgentKey, userAgentSuffix(options))
 	if err != nil {
 		return "", 0, fmt.Errorf("failed to add user agent to the signed url: %w", err)
 	}
 
-	return base64Encode(signedURLWithUserAgent), expirationTimeMs, nil
+	return base64Encode(signedURLWithUserAgent, options.TokenEncoding), expirationTimeMs, nil
 }
 
 // Build https request with query parameters in order to sign.
-func buildRequest(expirySeconds int, endpointURL string) (*http.Request, error) {
+func buildRequest(
+	expirySeconds int, endpointURL string, extraParams url.Values, profile CompatibilityProfile,
+) (*http.Request, error) {
 	query := url.Values{
-		ActionType:      {ActionName},
-		ExpiresQueryKey: {strconv.FormatInt(int64(expirySeconds), 10)},
+		profile.ActionKey:  {profile.ActionName},
+		profile.ExpiresKey: {strconv.FormatInt(int64(expirySeconds), 10)},
+	}
+	for key, values := range extraParams {
+		query[key] = values
 	}
 
 	authURL := url.URL{
@@ -210,21 +460,22 @@ func buildRequest(expirySeconds int, endpointURL string) (*http.Request, error)
 	return http.NewRequest(http.MethodGet, authURL.String(), nil)
 }
 
-// Sign request with aws sig v4.
-func signRequest(ctx context.Context, req *http.Request, region string, credentials *aws.Credentials) (string, error) {
-	signer := v4.NewSigner()
-	signedURL, _, err := signer.PresignHTTP(ctx, *credentials, req,
-		calculateSHA256Hash(""),
-		SigningName,
-		region,
-		time.Now().UTC(),
-	)
-
-	return signedURL, err
+// Sign request at the signing time with the configured request signer, aws sig v4 by default.
+func signRequest(
+	ctx context.Context, req *http.Request, region string, credentials *aws.Credentials, signingTime time.Time,
+	options Options,
+) (string, error) {
+	var signer RequestSigner = SigV4Signer{}
+	if options.RequestSigner != nil {
+		signer = options.RequestSigner
+	}
+
+	return signer.PresignRequest(ctx, req, region, *credentials, signingTime)
 }
 
-// Parses the URL and gets the expiration time in millis associated with the signed url
-func getExpirationTimeMs(signedURL string) (int64, error) {
+// Parses the URL and gets the expiration time in millis associated with the signed url, reading the lifetime from the
+// expires query parameter and subtracting the margin.
+func getExpirationTimeMs(signedURL string, expiresKey string, margin time.Duration) (int64, error) {
 	parsedURL, err := url.Parse(signedURL)
 
 	if err != nil {
@@ -232,22 +483,19 @@ func getExpirationTimeMs(signedURL string) (int64, error) {
 	}
 
 	params := parsedURL.Query()
-	date, err := time.Parse("20060102T150405Z", params.Get("X-Amz-Date"))
+	date, err := time.Parse(SigningTimeFormat, QueryKeyDate.Get(params))
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse the 'X-Amz-Date' param from signed url: %w", err)
+		return 0, fmt.Errorf("failed to parse the '%s' param from signed url: %w", QueryKeyDate, err)
 	}
 
-	signingTimeMs := date.UnixNano() / int64(time.Millisecond)
-	expiryDurationSeconds, err := strconv.ParseInt(params.Get("X-Amz-Expires"), 10, 64)
+	expiryDurationSeconds, err := strconv.ParseInt(params.Get(expiresKey), 10, 64)
 
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse the 'X-Amz-Expires' param from signed url: %w", err)
+		return 0, fmt.Errorf("failed to parse the '%s' param from signed url: %w", expiresKey, err)
 	}
 
-	expiryDurationMs := expiryDurationSeconds * 1000
-	expiryMs := signingTimeMs + expiryDurationMs
-	return expiryMs, nil
+	return ComputeExpiry(date, time.Duration(expiryDurationSeconds)*time.Second, margin).UnixMilli(), nil
 }
 
 // Calculate sha256Hash and hex encode it.
@@ -256,14 +504,15 @@ func calculateSHA256Hash(input string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// Base64 encode with raw url encoding.
-func base64Encode(signedURL string) string {
+// Base64 encode with the encoding variant, raw url encoding by default.
+func base64Encode(signedURL string, encoding TokenEncoding) string {
 	signedURLBytes := []byte(signedURL)
-	return base64.RawURLEncoding.EncodeToString(signedURLBytes)
+	return encoding.base64().EncodeToString(signedURLBytes)
 }
 
-// Add user agent to the signed url
-func addUserAgent(signedURL string) (string, error) {
+// Add user agent to the signed url under the query parameter key, followed by the suffix when not empty, re-encoding
+// the query canonically
+func addUserAgent(signedURL string, key string, suffix string) (string, error) {
 	parsedSignedURL, err := url.Parse(signedURL)
 
 	if err != nil {
@@ -272,34 +521,65 @@ func addUserAgent(signedURL string) (string, error) {
 
 	query := parsedSignedURL.Query()
 	userAgent := strings.Join([]string{LibName, version, runtime.Version()}, "/")
-	query.Set(UserAgentKey, userAgent)
-	parsedSignedURL.RawQuery = query.Encode()
+	if suffix != "" {
+		if err := validateUserAgentSuffix(suffix); err != nil {
+			return "", err
+		}
+		userAgent += " " + suffix
+	}
+	query.Set(key, userAgent)
+	parsedSignedURL.RawQuery = canonicalQueryString(query)
 
 	return parsedSignedURL.String(), nil
 }
 
+// Returns the user agent suffix of the options, else the app id of the AWS_SDK_UA_APP_ID environment variable as the
+// SDK formats it in its user agent, app/<id>.
+func userAgentSuffix(options Options) string {
+	if options.UserAgentSuffix != "" {
+		return options.UserAgentSuffix
+	}
+	if appID := os.Getenv(AppIDEnvVar); appID != "" {
+		return appIDUserAgentSuffix(appID)
+	}
+	return ""
+}
+
+// Formats the app id as a user agent suffix, app/<id>.
+func appIDUserAgentSuffix(appID string) string {
+	return "app/" + appID
+}
+
+// Validates that the user agent suffix only holds printable ASCII characters.
+func validateUserAgentSuffix(suffix string) error {
+	for _, r := range suffix {
+		if r < ' ' || r > '~' {
+			return fmt.Errorf("user agent suffix %q must only hold printable ASCII characters", suffix)
+		}
+	}
+	return nil
+}
+
 // Log caller identity to debug which credentials are being picked up
-func logCallerIdentity(ctx context.Context, region string, awsCredentials aws.Credentials) {
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(region),
-		config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
-			Value: awsCredentials,
-		}),
-	)
+func logCallerIdentity(ctx context.Context, region string, awsCredentials aws.Credentials, options Options) {
+	cfg, err := loadConfig(ctx, region, options)
 	if err != nil {
 		log.Printf("failed to load AWS configuration: %v", err)
+		return
 	}
+	cfg.Credentials = credentials.StaticCredentialsProvider{Value: awsCredentials}
 
-	stsClient := sts.NewFromConfig(cfg)
+	stsClient := newSTSClient(cfg, options)
 
 	callerIdentity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 
 	if err != nil {
 		log.Printf("failed to get caller identity: %v", err)
+		return
 	}
 
 	log.Printf("Credentials Identity: {UserId: %s, Account: %s, Arn: %s}\n",
-		*callerIdentity.UserId,
-		*callerIdentity.Account,
-		*callerIdentity.Arn)
+		aws.ToString(callerIdentity.UserId),
+		aws.ToString(callerIdentity.Account),
+		aws.ToString(callerIdentity.Arn))
 }
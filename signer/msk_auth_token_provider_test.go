@@ -1,9 +1,11 @@
 package signer
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
+	"log"
 	"net/url"
 	"os"
 	"strconv"
@@ -40,7 +42,7 @@ func TestCalculatePayloadHashForSigning(t *testing.T) {
 
 func TestAddUserAgent(t *testing.T) {
 	signedURL := "https://kafka.us-west-2.amazonaws.com/?Action=kafka-cluster%3AConnect"
-	result, err := addUserAgent(signedURL)
+	result, err := addUserAgent(signedURL, UserAgentKey, "")
 
 	assert.NoError(t, err)
 	assert.True(t, strings.HasPrefix(result, fmt.Sprintf("%s&%s=%s", signedURL, UserAgentKey, LibName)))
@@ -48,7 +50,7 @@ func TestAddUserAgent(t *testing.T) {
 
 func TestAddUserAgentWithInvalidURL(t *testing.T) {
 	signedURL := ":invalidURL:"
-	result, err := addUserAgent(signedURL)
+	result, err := addUserAgent(signedURL, UserAgentKey, "")
 
 	assert.Error(t, err)
 	assert.Equal(t, "", result)
@@ -63,7 +65,7 @@ func TestLoadDefaultCredentials(t *testing.T) {
 	os.Setenv("AWS_ACCESS_KEY_ID", mockCreds.AccessKeyID)
 	os.Setenv("AWS_SECRET_ACCESS_KEY", mockCreds.SecretAccessKey)
 
-	creds, err := loadDefaultCredentials(Ctx, TestRegion)
+	creds, err := loadDefaultCredentials(Ctx, TestRegion, Options{})
 	assert.NoError(t, err)
 	assert.NotNil(t, creds)
 	assert.Equal(t, mockCreds.AccessKeyID, creds.AccessKeyID)
@@ -81,7 +83,7 @@ func TestConstructAuthToken(t *testing.T) {
 		SessionToken:    "MOCK-SESSION-TOKEN",
 	}
 
-	token, expiryMs, err := constructAuthToken(Ctx, TestRegion, &mockCreds)
+	token, expiryMs, err := constructAuthToken(Ctx, TestRegion, &mockCreds, Options{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, token)
@@ -241,3 +243,113 @@ func TestGenerateAuthTokenWithFailingCredentialsProvider(t *testing.T) {
 	assert.NotNil(t, token)
 	assert.Equal(t, int64(0), expiryMs)
 }
+
+func TestGenerateAuthTokenFromConfig(t *testing.T) {
+	cfg := aws.Config{Region: "eu-west-1", Credentials: testQueryCredentialsProvider}
+
+	token, expiryMs, err := GenerateAuthTokenFromConfig(Ctx, cfg)
+
+	assert.NoError(t, err)
+	assert.NotZero(t, expiryMs)
+	credential := QueryKeyCredential.Get(decodeTokenParams(t, token))
+	assert.True(t, strings.HasPrefix(credential, "TEST-QUERY-ACCESS-KEY/"))
+	assert.Contains(t, credential, "/eu-west-1/kafka-cluster/")
+}
+
+func TestGenerateAuthTokenFromConfigWithoutCredentials(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromConfig(Ctx, aws.Config{Region: TestRegion})
+
+	assert.ErrorContains(t, err, "credentials provider cannot be nil")
+}
+
+func TestGenerateAuthTokenWithOptions(t *testing.T) {
+	setEnvCredentials(t)
+
+	token, expiryMs, err := GenerateAuthTokenWithOptions(Ctx, WithRegion("eu-west-1"), WithExpiry(10*time.Minute),
+		WithUserAgentSuffix("orders-service/1.2"))
+
+	assert.NoError(t, err)
+	assert.NotZero(t, expiryMs)
+	params := decodeTokenParams(t, token)
+	assert.True(t, strings.HasPrefix(QueryKeyCredential.Get(params), "TEST-ENV-ACCESS-KEY/"))
+	assert.Contains(t, QueryKeyCredential.Get(params), "/eu-west-1/kafka-cluster/")
+	assert.Equal(t, "600", params.Get(ExpiresQueryKey))
+	assert.True(t, strings.HasSuffix(QueryKeyUserAgent.Get(params), " orders-service/1.2"))
+}
+
+func TestGenerateAuthTokenWithOptionsFromProfile(t *testing.T) {
+	setEnvCredentials(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	assert.NoError(t, os.WriteFile(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"), []byte(
+		"[orders]\naws_access_key_id = TEST-PROFILE-ACCESS-KEY\naws_secret_access_key = TEST-PROFILE-SECRET-KEY\n"),
+		0o600))
+
+	token, _, err := GenerateAuthTokenWithOptions(Ctx, WithRegion(TestRegion), WithProfile("orders"))
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(QueryKeyCredential.Get(decodeTokenParams(t, token)), "TEST-PROFILE-ACCESS-KEY/"))
+}
+
+func TestGenerateAuthTokenWithOptionsFromRole(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	roleARN := "arn:aws:iam::123456789012:role/kafka"
+	client := &mockSTSClient{}
+
+	token, _, err := GenerateAuthTokenWithOptions(Ctx, WithRegion(TestRegion), WithRoleARN(roleARN, ""),
+		WithSTSClient(client))
+
+	assert.NoError(t, err)
+	assert.Contains(t, QueryKeyCredential.Get(decodeTokenParams(t, token)), "TEST-STS-CLIENT-ACCESS-KEY")
+	assert.Equal(t, []string{roleARN}, client.roleARNs)
+}
+
+func TestGenerateAuthTokenWithOptionsRejectsProfileAndRole(t *testing.T) {
+	_, _, err := GenerateAuthTokenWithOptions(Ctx, WithRegion(TestRegion), WithProfile("orders"),
+		WithRoleARN("arn:aws:iam::123456789012:role/kafka", ""))
+
+	assert.ErrorContains(t, err, "cannot be used together")
+}
+
+func TestGenerateAuthTokenWithInvalidUserAgentSuffix(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithUserAgentSuffix("orders\nservice"))
+
+	assert.ErrorContains(t, err, "printable ASCII")
+}
+
+func TestGenerateAuthTokenWithAppIDFromEnv(t *testing.T) {
+	t.Setenv(AppIDEnvVar, "orders-service")
+
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(QueryKeyUserAgent.Get(decodeTokenParams(t, token)), " app/orders-service"))
+
+	token, _, err = GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithUserAgentSuffix("orders-service/1.2"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(QueryKeyUserAgent.Get(decodeTokenParams(t, token)), " orders-service/1.2"))
+}
+
+func TestGenerateAuthTokenFromConfigWithAppID(t *testing.T) {
+	t.Setenv(AppIDEnvVar, "")
+	cfg := aws.Config{Region: TestRegion, Credentials: testQueryCredentialsProvider, AppID: "payments"}
+
+	token, _, err := GenerateAuthTokenFromConfig(Ctx, cfg)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(QueryKeyUserAgent.Get(decodeTokenParams(t, token)), " app/payments"))
+}
+
+func TestLogCallerIdentityWithFailingSTS(t *testing.T) {
+	withSTSCallerIdentityServer(t, func(call int64) string { return "" })
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	assert.NotPanics(t, func() {
+		logCallerIdentity(Ctx, TestRegion, aws.Credentials{AccessKeyID: "TEST-ACCESS-KEY"}, Options{})
+	})
+	assert.Contains(t, logs.String(), "failed to get caller identity")
+	assert.NotContains(t, logs.String(), "Credentials Identity")
+}
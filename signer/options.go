@@ -0,0 +1,688 @@
+package signer
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Options holds the optional settings applied when generating an auth token.
+type Options struct {
+	// Logger receives token lifecycle events. No events are logged when nil.
+	Logger *slog.Logger
+
+	// Tracer starts spans around credential retrieval and signing. No spans are started when nil.
+	Tracer Tracer
+
+	// EMFNamespace is the CloudWatch namespace of the token generation metrics emitted in the embedded metric format.
+	// No metrics are emitted when empty.
+	EMFNamespace string
+
+	// EMFWriter receives the embedded metric format log events. WithEMFMetrics sets it to stdout, which is collected
+	// by the Lambda and ECS log drivers.
+	EMFWriter io.Writer
+
+	// CredentialExpiryPolicy decides what happens when the resolved credentials expire within MinCredentialLifetime.
+	CredentialExpiryPolicy CredentialExpiryPolicy
+
+	// MinCredentialLifetime is the remaining credential lifetime below which CredentialExpiryPolicy applies.
+	// DefaultMinCredentialLifetime is used when zero.
+	MinCredentialLifetime time.Duration
+
+	// STSRetryer is the retryer of the sts clients created by the signer. The SDK standard retryer is used when nil.
+	STSRetryer aws.Retryer
+
+	// DisableSharedConfig resolves the SDK config from environment variables only, without reading the shared config
+	// and credentials files or honoring AWS_PROFILE.
+	DisableSharedConfig bool
+
+	// DefaultsMode selects the SDK defaults, such as retry and timeout settings, used while retrieving credentials.
+	// The SDK default applies when empty.
+	DefaultsMode aws.DefaultsMode
+
+	// ClusterARN scopes the auth token to an MSK cluster by signing it into the url. This is experimental, brokers
+	// that do not evaluate the parameter ignore it.
+	ClusterARN string
+
+	// SignedQueryParameters are extra query parameters signed into the auth token.
+	SignedQueryParameters url.Values
+
+	// TokenTTLObserver is called by a Provider with the remaining lifetime of every token it returns.
+	TokenTTLObserver func(ttl time.Duration)
+
+	// RefreshStrategy decides when a Provider replaces its cached token. Tokens are replaced after
+	// DefaultRefreshFraction of their lifetime when nil.
+	RefreshStrategy RefreshStrategy
+
+	// TokenWriter receives every new token generated by a Provider as a JSON line in the format of
+	// Token.MarshalJSON, e.g. for a non-Go process reading from a pipe. Refreshes signing with the same credentials
+	// within the same minute as the last written token are not written again.
+	TokenWriter io.Writer
+
+	// StrictRegionValidation rejects regions MSK is not available in with ErrUnknownRegion before loading
+	// credentials, catching typos at token generation rather than as broker rejections.
+	StrictRegionValidation bool
+
+	// ExtraRegions are accepted in strict region validation mode in addition to the known MSK regions, e.g. for
+	// regions launched after this release.
+	ExtraRegions []string
+
+	// RequestSigner presigns the auth token request. SigV4Signer is used when nil.
+	RequestSigner RequestSigner
+
+	// STSThrottleMonitor tracks the throttling of the sts calls made by the SDK config the signer loads, such as role
+	// assumption. NewProvider creates one when nil.
+	STSThrottleMonitor *STSThrottleMonitor
+
+	// MaxExpiry is the longest token lifetime allowed, e.g. by organization policy. Tokens are not capped when zero.
+	MaxExpiry time.Duration
+
+	// MaxExpiryPolicy decides what happens when the requested token expiry is longer than MaxExpiry.
+	MaxExpiryPolicy MaxExpiryPolicy
+
+	// MinRefreshInterval is the shortest time a Provider keeps a token before replacing it, whatever the refresh
+	// strategy decides. Tokens are replaced as the strategy decides when zero.
+	MinRefreshInterval time.Duration
+
+	// TokenOverlap is how long before the cached token is replaced a Provider generates the next one, keeping both
+	// until the switch. No next token is generated ahead of time when zero.
+	TokenOverlap time.Duration
+
+	// StaleTokenFallback makes a Provider return its last token, even past expiry, when generating a new one fails.
+	StaleTokenFallback bool
+
+	// OnStaleToken is called with the stale token and the generation error whenever a Provider falls back to it.
+	OnStaleToken func(token *Token, err error)
+
+	// STSClient assumes the roles of role based token generation. A client is created from the SDK config loaded for
+	// the region on every generation when nil.
+	STSClient STSAPIClient
+
+	// TokenEncoding is the base64 variant of the auth token. MSK brokers only accept TokenEncodingRawURL, the
+	// default.
+	TokenEncoding TokenEncoding
+
+	// Compatibility names the query parameters managed by the signer. The zero value is the MSK profile.
+	Compatibility CompatibilityProfile
+
+	// LogSignedURLs logs the presigned url of every token at debug level, scrubbed by URLScrubber.
+	LogSignedURLs bool
+
+	// URLScrubber masks the secrets of the logged presigned urls. DefaultScrubber is used when nil.
+	URLScrubber Scrubber
+
+	// IssuanceQuota is the number of tokens a Provider may generate per IssuanceQuotaWindow. Token generation is not
+	// capped when zero.
+	IssuanceQuota int
+
+	// IssuanceQuotaWindow is the sliding window IssuanceQuota applies to.
+	IssuanceQuotaWindow time.Duration
+
+	// RegionFallback decides how the region is resolved when token generation is called with an empty one.
+	RegionFallback RegionFallback
+
+	// ExpiryMargin is subtracted from the expiration time reported with the auth token, so clients reauthenticate before
+	// the broker can see the token as expired. The signed lifetime is unchanged.
+	ExpiryMargin time.Duration
+
+	// Dialer dials the connections of the SDK HTTP clients retrieving credentials, from sts, the instance metadata
+	// service or the container credentials endpoint. The SDK default dialer is used when nil.
+	Dialer *net.Dialer
+
+	// SessionTokenPolicy decides whether long-term access keys, without a session token, may sign auth tokens.
+	SessionTokenPolicy SessionTokenPolicy
+
+	// RequireFIPS fails token generation with ErrFIPSNotEnabled unless FIPS validated crypto is in use. Builds with the
+	// fips tag always require it.
+	RequireFIPS bool
+
+	// TokenValidators inspect every generated auth token before it is returned, failing token generation with
+	// ErrTokenPolicyViolation when one rejects it.
+	TokenValidators []TokenValidator
+
+	// ClockSkewPolicy decides what happens when the system clock is off the trusted time by more than MaxClockSkew.
+	ClockSkewPolicy ClockSkewPolicy
+
+	// MaxClockSkew is the tolerated difference between the system clock and the trusted time. DefaultMaxClockSkew is
+	// used when zero.
+	MaxClockSkew time.Duration
+
+	// TimeSource supplies the trusted time the system clock is checked against. The Date header of the sts endpoint
+	// roles are assumed with is used when nil, its offset to the system clock being reused for a while.
+	TimeSource TimeSource
+
+	// HostResolver returns the addresses the connections retrieving credentials are made to, in place of DNS. Hosts
+	// are resolved by the dialer when nil.
+	HostResolver HostResolver
+
+	// TokenEnvelope wraps every generated auth token. Tokens are returned raw when nil.
+	TokenEnvelope TokenEnvelope
+
+	// IssuanceRecorder receives a redacted record of every issued auth token. No records are made when nil.
+	IssuanceRecorder func(record IssuanceRecord)
+
+	// STSRegion is the region of the sts endpoint roles are assumed with, defaulting to the signing region.
+	STSRegion string
+
+	// STSEndpoint is the URL of the sts endpoint roles are assumed with, overriding the endpoint of STSRegion.
+	STSEndpoint string
+
+	// ColdStartStore persists the token and credentials of a Provider across cold starts. Nothing is persisted when
+	// nil.
+	ColdStartStore ColdStartStore
+
+	// ColdStartDeadline bounds the generation of the first token of a Provider with a cold start store, defaulting to
+	// DefaultColdStartDeadline when zero.
+	ColdStartDeadline time.Duration
+
+	// Expiry is the requested lifetime of auth tokens, defaulting to DefaultExpirySeconds when zero.
+	Expiry time.Duration
+
+	// Region is the region GenerateAuthTokenWithOptions signs tokens for.
+	Region string
+
+	// Profile is the named profile GenerateAuthTokenWithOptions loads credentials from.
+	Profile string
+
+	// RoleARN is the role GenerateAuthTokenWithOptions and NewProvider assume to sign tokens.
+	RoleARN string
+
+	// RoleSessionName is the session name of the role assumed by GenerateAuthTokenWithOptions and NewProvider,
+	// DefaultSessionName when empty.
+	RoleSessionName string
+
+	// UserAgentSuffix is appended to the user agent of auth tokens, e.g. to identify the application.
+	UserAgentSuffix string
+
+	// APIOptions are added to the middleware stacks of the AWS clients created by the signer.
+	APIOptions []func(*middleware.Stack) error
+
+	// Clock returns the time tokens are signed at. The wall clock is used when nil.
+	Clock Clock
+
+	// Endpoint is the host auth tokens are signed for, instead of the kafka endpoint of the region.
+	Endpoint string
+
+	// SourceIdentity is set on the sessions of the roles assumed by the signer.
+	SourceIdentity string
+
+	// FIPSEndpoint decides whether tokens are signed for the FIPS kafka endpoint and roles are assumed with the FIPS
+	// sts endpoint. The AWS_USE_FIPS_ENDPOINT environment variable decides when unset.
+	FIPSEndpoint aws.FIPSEndpointState
+
+	// FaultInjector is called before the token generation steps to inject failures and delays in chaos tests.
+	FaultInjector FaultInjector
+}
+
+// Option configures the Options used when generating an auth token.
+type Option func(*Options)
+
+// WithLogger sets the logger that receives token lifecycle events.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		o.Logger = logger
+	}
+}
+
+// WithJSONLogging writes token lifecycle events to w as JSON lines using the stable field names defined by the
+// LogKey constants, so they can be ingested by log pipelines without further parsing.
+func WithJSONLogging(w io.Writer) Option {
+	return WithLogger(slog.New(slog.NewJSONHandler(w, nil)))
+}
+
+// WithTracer sets the tracer that starts spans around credential retrieval and signing.
+func WithTracer(tracer Tracer) Option {
+	return func(o *Options) {
+		o.Tracer = tracer
+	}
+}
+
+// WithEMFMetrics writes token generation metrics to stdout in the CloudWatch embedded metric format under the given
+// namespace, one log event per generation.
+func WithEMFMetrics(namespace string) Option {
+	return func(o *Options) {
+		o.EMFNamespace = namespace
+		o.EMFWriter = os.Stdout
+	}
+}
+
+// WithCredentialExpiryPolicy sets the policy applied when the resolved credentials expire within minLifetime, so that
+// tokens are not minted from sessions that end mid-handshake. DefaultMinCredentialLifetime is used when minLifetime is
+// zero.
+func WithCredentialExpiryPolicy(policy CredentialExpiryPolicy, minLifetime time.Duration) Option {
+	return func(o *Options) {
+		o.CredentialExpiryPolicy = policy
+		o.MinCredentialLifetime = minLifetime
+	}
+}
+
+// WithSTSRetryer sets the retryer used by the sts clients the signer creates to assume roles and look up the caller
+// identity, e.g. an adaptive retryer for accounts with tight sts quotas. The same retryer is shared by every call so
+// that its retry state persists between token generations.
+func WithSTSRetryer(retryer aws.Retryer) Option {
+	return func(o *Options) {
+		o.STSRetryer = retryer
+	}
+}
+
+// WithoutSharedConfig resolves the SDK config from environment variables only, skipping the ~/.aws config and
+// credentials files. This shortens startup in containers and prevents profiles from being picked up unexpectedly in
+// CI. Credentials are resolved from the access key environment variables, a web identity token file, the container
+// credentials endpoint or the EC2 instance metadata service, in that order.
+func WithoutSharedConfig() Option {
+	return func(o *Options) {
+		o.DisableSharedConfig = true
+	}
+}
+
+// WithDefaultsMode sets the SDK defaults mode (standard, in-region, cross-region, mobile or auto) of the config used to
+// retrieve credentials, so that retries and timeouts match the runtime environment.
+func WithDefaultsMode(mode aws.DefaultsMode) Option {
+	return func(o *Options) {
+		o.DefaultsMode = mode
+	}
+}
+
+// WithClusterARN scopes the auth token to the MSK cluster by signing its ARN into the url under ClusterARNQueryKey, so
+// security teams can experiment with least-privilege tokens. The ARN must identify a cluster in the signing region, and
+// cannot be combined with a ClusterARNQueryKey parameter set with WithSignedQueryParameter.
+func WithClusterARN(clusterARN string) Option {
+	return func(o *Options) {
+		o.ClusterARN = clusterARN
+	}
+}
+
+// WithSignedQueryParameter adds a query parameter signed into the auth token. Parameters managed by the signer, such as
+// Action and the X-Amz-* parameters, cannot be set.
+func WithSignedQueryParameter(key string, value string) Option {
+	return func(o *Options) {
+		if o.SignedQueryParameters == nil {
+			o.SignedQueryParameters = url.Values{}
+		}
+		o.SignedQueryParameters.Add(key, value)
+	}
+}
+
+// WithTokenTTLObserver sets a callback that a Provider calls with the remaining lifetime of every token it returns, so
+// dashboards can plot how close a fleet runs to token expiry as an early warning of refresh problems.
+func WithTokenTTLObserver(observer func(ttl time.Duration)) Option {
+	return func(o *Options) {
+		o.TokenTTLObserver = observer
+	}
+}
+
+// WithRefreshStrategy sets the strategy deciding when a Provider replaces its cached token.
+func WithRefreshStrategy(strategy RefreshStrategy) Option {
+	return func(o *Options) {
+		o.RefreshStrategy = strategy
+	}
+}
+
+// WithTokenWriter streams every new token generated by a Provider to w as a JSON line, skipping refreshes that
+// produced the same token as the last one written.
+func WithTokenWriter(w io.Writer) Option {
+	return func(o *Options) {
+		o.TokenWriter = w
+	}
+}
+
+// WithStrictRegionValidation rejects regions MSK is not available in, accepting the extra regions in addition to the
+// known ones.
+func WithStrictRegionValidation(extraRegions ...string) Option {
+	return func(o *Options) {
+		o.StrictRegionValidation = true
+		o.ExtraRegions = append(o.ExtraRegions, extraRegions...)
+	}
+}
+
+// WithRequestSigner sets the signer presigning the auth token request in place of SigV4Signer.
+func WithRequestSigner(signer RequestSigner) Option {
+	return func(o *Options) {
+		o.RequestSigner = signer
+	}
+}
+
+// WithSTSThrottleMonitor reports the throttling of the sts calls made while retrieving credentials to the monitor.
+func WithSTSThrottleMonitor(monitor *STSThrottleMonitor) Option {
+	return func(o *Options) {
+		o.STSThrottleMonitor = monitor
+	}
+}
+
+// WithMaxExpiry caps the lifetime of the auth token at maxExpiry, rejecting longer requested expiries with
+// ErrExpiryExceedsMax or clamping them to maxExpiry depending on the policy.
+func WithMaxExpiry(maxExpiry time.Duration, policy MaxExpiryPolicy) Option {
+	return func(o *Options) {
+		o.MaxExpiry = maxExpiry
+		o.MaxExpiryPolicy = policy
+	}
+}
+
+// WithMinRefreshInterval keeps the tokens of a Provider for at least the interval, guarding against refresh loops
+// caused by aggressive refresh strategies. Tokens that expire before the interval has elapsed fail with a
+// RefreshLoopError instead of being cached.
+func WithMinRefreshInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.MinRefreshInterval = interval
+	}
+}
+
+// WithTokenOverlap makes a Provider generate the next token the overlap window before the cached one is replaced and
+// return both from Provider.Tokens, so connection code can pick the newer token while handshakes already in flight
+// keep the older one.
+func WithTokenOverlap(window time.Duration) Option {
+	return func(o *Options) {
+		o.TokenOverlap = window
+	}
+}
+
+// WithStaleTokenFallback makes a Provider return its last token, even past expiry, when generating a new one fails,
+// calling onStale with the token and the error, which may be nil. This suits batch jobs where a failed handshake is
+// retried anyway and is preferable to blocking on a transient credentials outage. An error is still returned when no
+// token was ever generated.
+func WithStaleTokenFallback(onStale func(token *Token, err error)) Option {
+	return func(o *Options) {
+		o.StaleTokenFallback = true
+		o.OnStaleToken = onStale
+	}
+}
+
+// WithSTSClient sets the sts client assuming the roles of role based token generation, e.g. a client reused between
+// generations, using a regional endpoint or a test double. The SDK config is not loaded to assume roles when set, and
+// the STS settings of the other options do not apply to the client.
+func WithSTSClient(client STSAPIClient) Option {
+	return func(o *Options) {
+		o.STSClient = client
+	}
+}
+
+// WithTokenEncoding encodes the auth token with the base64 variant, e.g. TokenEncodingStd for downstream systems that
+// require padded standard base64. MSK brokers only accept the default TokenEncodingRawURL, so tokens encoded otherwise
+// must be re-encoded before being presented to a broker; a warning is logged once per process when a logger is set.
+func WithTokenEncoding(encoding TokenEncoding) Option {
+	return func(o *Options) {
+		o.TokenEncoding = encoding
+	}
+}
+
+// WithCompatibilityProfile names the query parameters managed by the signer after the profile, for MSK-compatible
+// brokers that expect different parameter names or casing. MSK brokers only accept the default profile.
+func WithCompatibilityProfile(profile CompatibilityProfile) Option {
+	return func(o *Options) {
+		o.Compatibility = profile
+	}
+}
+
+// WithSignedURLLogging logs the presigned url of every token to the logger at debug level, passed through the
+// scrubber first so signatures, session tokens and key ids are not leaked. DefaultScrubber is used when scrubber is
+// nil.
+func WithSignedURLLogging(scrubber Scrubber) Option {
+	return func(o *Options) {
+		o.LogSignedURLs = true
+		o.URLScrubber = scrubber
+	}
+}
+
+// WithIssuanceQuota caps the tokens a Provider generates at limit per sliding window, failing further generations with
+// an IssuanceQuotaError until the window allows more. Failed generations count towards the quota. This is a safety
+// brake against runaway reconnect loops exhausting sts quotas or flooding brokers.
+func WithIssuanceQuota(limit int, window time.Duration) Option {
+	return func(o *Options) {
+		o.IssuanceQuota = limit
+		o.IssuanceQuotaWindow = window
+	}
+}
+
+// WithRegionFallback sets how the region is resolved when token generation is called with an empty one: failing with
+// ErrRegionRequired, the default, or reading it from the environment or the instance metadata service.
+func WithRegionFallback(fallback RegionFallback) Option {
+	return func(o *Options) {
+		o.RegionFallback = fallback
+	}
+}
+
+// WithExpiryMargin reports the expiration time of auth tokens margin earlier than their signed expiry, e.g. 840s for a
+// 900s token with a one minute margin. Kafka clients schedule reauthentication from the reported expiration time, so the
+// margin keeps them from presenting a token the broker validates just after it expired. The margin is capped at the
+// token lifetime, and Provider refreshes are scheduled from the reported expiration time as well.
+func WithExpiryMargin(margin time.Duration) Option {
+	return func(o *Options) {
+		o.ExpiryMargin = margin
+	}
+}
+
+// WithDialer dials the connections made to retrieve credentials with dialer, e.g. with LocalAddr set to bind them to
+// the address of a specific network interface, or with a Control function selecting a VRF, on multi-homed hosts. The
+// dialer is copied, changes made to it afterwards are not picked up.
+func WithDialer(dialer *net.Dialer) Option {
+	return func(o *Options) {
+		o.Dialer = dialer
+	}
+}
+
+// WithSessionTokenPolicy sets whether long-term access keys may sign auth tokens. SessionTokenRequired fails token
+// generation with ErrSessionTokenRequired unless the credentials carry a session token, so long-lived keys never mint
+// MSK tokens. Tokens signed by a RemoteSigner are not checked, the signing credentials are not known to the signer.
+func WithSessionTokenPolicy(policy SessionTokenPolicy) Option {
+	return func(o *Options) {
+		o.SessionTokenPolicy = policy
+	}
+}
+
+// WithFIPSRequired fails token generation with ErrFIPSNotEnabled unless auth tokens are signed with FIPS validated
+// crypto, see FIPSEnabled, for deployments that must attest the FIPS compliance of token signing. Building with the fips
+// tag has the same effect for every Provider and call.
+func WithFIPSRequired() Option {
+	return func(o *Options) {
+		o.RequireFIPS = true
+	}
+}
+
+// WithTokenValidators adds validators inspecting every generated auth token before it is returned, so internal
+// policies, such as MaxTokenLifetime or AccessKeyIDPrefix, are enforced without wrapping every call site. Rejected
+// tokens fail token generation with ErrTokenPolicyViolation and are not cached by a Provider.
+func WithTokenValidators(validators ...TokenValidator) Option {
+	return func(o *Options) {
+		o.TokenValidators = append(o.TokenValidators, validators...)
+	}
+}
+
+// WithClockSkewPolicy checks the system clock against the trusted time before signing, failing with ErrClockSkew or
+// signing with the trusted time, depending on the policy, when they differ by more than maxSkew. DefaultMaxClockSkew
+// is used when maxSkew is zero. The trusted time is requested for every token; without it, only gross errors such as
+// a clock years in the past or credentials issued in its future are detected. Provider refreshes are still scheduled
+// from the system clock.
+func WithClockSkewPolicy(policy ClockSkewPolicy, maxSkew time.Duration) Option {
+	return func(o *Options) {
+		o.ClockSkewPolicy = policy
+		o.MaxClockSkew = maxSkew
+	}
+}
+
+// WithTimeSource sets the trusted time source the clock skew policy checks the system clock against, e.g. an
+// HTTPDateTimeSource of an internal endpoint in networks without access to sts.
+func WithTimeSource(source TimeSource) Option {
+	return func(o *Options) {
+		o.TimeSource = source
+	}
+}
+
+// WithHostResolver connects to the addresses the resolver returns, e.g. PinHosts, when retrieving credentials from
+// sts or the container credentials endpoint, for split-horizon DNS environments where the endpoints misresolve. The
+// addresses are tried in order with the dialer, and connection errors name the host and the addresses tried rather
+// than surfacing as role assumption failures. TLS still verifies the certificate of the host name.
+func WithHostResolver(resolver HostResolver) Option {
+	return func(o *Options) {
+		o.HostResolver = resolver
+	}
+}
+
+// WithTokenEnvelope returns every generated auth token wrapped in the envelope, e.g. a signed JWT carrying the token
+// for an internal gateway, in place of the raw token. The expiration time is that of the raw token, token validators
+// inspect the raw token and a Provider caches the wrapped one. Kafka clients connecting to MSK directly need the raw
+// token, unwrapped with TokenEnvelope.Unwrap.
+func WithTokenEnvelope(envelope TokenEnvelope) Option {
+	return func(o *Options) {
+		o.TokenEnvelope = envelope
+	}
+}
+
+// WithIssuanceRecorder calls recorder with a redacted record of every issued auth token, e.g. to archive who was
+// issued which token and until when for compliance. Records carry the principal, times and a signature prefix, never the
+// full signature or session token. The recorder is called synchronously after a token passed the token validators.
+func WithIssuanceRecorder(recorder func(record IssuanceRecord)) Option {
+	return func(o *Options) {
+		o.IssuanceRecorder = recorder
+	}
+}
+
+// WithSTSRegion assumes roles with the sts endpoint of the region, or with endpoint when not empty, instead of the
+// regional sts endpoint of the signing region. It decouples role assumption from signing across partitions, e.g.
+// assuming a role of the aws-us-gov partition with the sts endpoint of us-gov-west-1 while signing tokens for a
+// cluster of the aws partition. The role must be in the partition of the region, which is validated before it is
+// assumed.
+func WithSTSRegion(region string, endpoint string) Option {
+	return func(o *Options) {
+		o.STSRegion = region
+		o.STSEndpoint = endpoint
+	}
+}
+
+// WithColdStart optimizes a Provider for scale-to-zero consumers, e.g. Knative services, persisting its token and
+// temporary credentials to store after every generation and restoring them on the first token request:
+//
+//   - a restored token that is still fresh is served without generating one,
+//   - otherwise a token is generated, from the restored credentials while they are valid, within deadline, or
+//     DefaultColdStartDeadline when zero,
+//   - when that fails or the deadline elapses, the restored token is served while it has not expired, else the error
+//     is returned, wrapping ErrColdStartDeadline when the deadline elapsed.
+//
+// Later generations are not bounded by the deadline.
+func WithColdStart(store ColdStartStore, deadline time.Duration) Option {
+	return func(o *Options) {
+		o.ColdStartStore = store
+		o.ColdStartDeadline = deadline
+	}
+}
+
+// WithExpiry requests auth tokens valid for expiry, truncated to the second, instead of DefaultExpirySeconds, e.g.
+// shorter-lived tokens for stricter security postures. Token generation fails with ErrInvalidExpiry when expiry is
+// shorter than a second or longer than MaxServiceExpiry, and the max expiry of WithMaxExpiry still applies.
+func WithExpiry(expiry time.Duration) Option {
+	return func(o *Options) {
+		o.Expiry = expiry
+	}
+}
+
+// WithRegion sets the region GenerateAuthTokenWithOptions signs tokens for. The other functions take the region as an
+// argument and ignore it.
+func WithRegion(region string) Option {
+	return func(o *Options) {
+		o.Region = region
+	}
+}
+
+// WithProfile makes GenerateAuthTokenWithOptions load credentials from the named profile. It cannot be combined with
+// WithRoleARN.
+func WithProfile(profile string) Option {
+	return func(o *Options) {
+		o.Profile = profile
+	}
+}
+
+// WithRoleARN makes GenerateAuthTokenWithOptions, and NewProvider when passed no credentials provider, sign tokens by
+// assuming the role with the session name, or DefaultSessionName when empty. It cannot be combined with WithProfile.
+func WithRoleARN(roleARN string, sessionName string) Option {
+	return func(o *Options) {
+		o.RoleARN = roleARN
+		o.RoleSessionName = sessionName
+	}
+}
+
+// WithUserAgentSuffix appends the suffix to the user agent of auth tokens, separated by a space, e.g. my-app/1.2 to
+// identify the application in broker logs and support cases. The suffix must only hold printable ASCII characters.
+// Without it, the app id of the AWS_SDK_UA_APP_ID environment variable is appended as app/<id>, as the SDK does.
+func WithUserAgentSuffix(suffix string) Option {
+	return func(o *Options) {
+		o.UserAgentSuffix = suffix
+	}
+}
+
+// WithAPIOptions adds the middleware stack options to every AWS client the signer creates, the sts clients assuming
+// roles and looking up caller identities, the clients of the default credential chain and the instance metadata
+// clients, e.g. to attach mandatory headers or request audits the way other SDK clients are configured through
+// aws.Config.APIOptions. Clients passed with WithSTSClient or built into a credentials provider are not changed.
+func WithAPIOptions(apiOptions ...func(*middleware.Stack) error) Option {
+	return func(o *Options) {
+		o.APIOptions = append(o.APIOptions, apiOptions...)
+	}
+}
+
+// WithClock signs tokens at the time returned by clock instead of the wall clock, e.g. a fixed time for deterministic
+// tests. The clock skew policy of WithClockSkewPolicy checks and corrects the time of the clock like the system clock.
+func WithClock(clock Clock) Option {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+// WithEndpoint signs auth tokens for the host, optionally followed by a port, instead of the kafka endpoint of the
+// region, kafka.<region>.<dns suffix of its partition>, e.g. for tests, private DNS setups or forward proxies. The
+// region still scopes the signature.
+func WithEndpoint(host string) Option {
+	return func(o *Options) {
+		o.Endpoint = host
+	}
+}
+
+// WithSourceIdentity sets the source identity on the sessions of the roles the signer assumes with sts AssumeRole, e.g.
+// the user name of the human or the name of the workload. AWS records it in the CloudTrail events of the session,
+// including the kafka-cluster:Connect events of the auth tokens signed with its credentials, and carries it over to
+// roles assumed from the session, so security teams can trace which identity caused an MSK connection. The role trust
+// policy must allow sts:SetSourceIdentity, and the source identity cannot be changed once set on a session. It must
+// hold 2 to 64 characters out of letters, digits and _+=,.@-, or role assumption fails with ErrInvalidSourceIdentity
+// before contacting sts. Roles assumed with a web identity take the source identity from the token instead.
+func WithSourceIdentity(sourceIdentity string) Option {
+	return func(o *Options) {
+		o.SourceIdentity = sourceIdentity
+	}
+}
+
+// WithFIPSEndpoint signs auth tokens for the FIPS kafka endpoint of the region, kafka-fips.<region>.<dns suffix>, and
+// assumes roles with the FIPS sts endpoint of the sts region when enabled, e.g. for FedRAMP workloads, overriding the
+// AWS_USE_FIPS_ENDPOINT environment variable either way. The hosts of WithEndpoint and WithSTSRegion are used as is.
+// FIPS validated crypto is required separately with WithFIPSRequired.
+func WithFIPSEndpoint(enabled bool) Option {
+	return func(o *Options) {
+		o.FIPSEndpoint = aws.FIPSEndpointStateDisabled
+		if enabled {
+			o.FIPSEndpoint = aws.FIPSEndpointStateEnabled
+		}
+	}
+}
+
+// WithFaultInjector calls the fault injector before loading credentials, before every role assumption call to sts and
+// before signing, failing the step with the error it returns, e.g. FailStep(FaultStepSTS) to simulate an sts outage or
+// DelayStep(FaultStepLoadCredentials, 5*time.Second) to simulate a slow credential source. Injected failures are
+// handled like real ones, so Provider retries, stale token fallback and failover of a CompositeProvider can be
+// exercised. It is meant for chaos tests only and should never be set in production.
+func WithFaultInjector(injector FaultInjector) Option {
+	return func(o *Options) {
+		o.FaultInjector = injector
+	}
+}
+
+// Applies the option functions on top of the default options.
+func resolveOptions(optFns []Option) Options {
+	var options Options
+	for _, fn := range optFns {
+		fn(&options)
+	}
+	return options
+}
@@ -0,0 +1,55 @@
+package signer
+
+import (
+	"regexp"
+	"sync"
+)
+
+//go:generate go run ./internal/partitionsgen -output partitions_gen.go
+
+// An AWS partition, generated into partitions_gen.go from the partitions metadata of the AWS SDK.
+type partition struct {
+	id          string
+	dnsSuffix   string
+	regionRegex string
+}
+
+// Default partition of regions no partition claims, as the SDK resolves them.
+const defaultPartitionID = "aws"
+
+var (
+	compileRegionRegexesOnce sync.Once
+	regionRegexes            []*regexp.Regexp
+)
+
+// Returns the partition of the region: the one listing it, else the first one whose region pattern matches it, else
+// the aws partition.
+func partitionOf(region string) partition {
+	if i, ok := partitionRegions[region]; ok {
+		return partitions[i]
+	}
+
+	compileRegionRegexesOnce.Do(func() {
+		regionRegexes = make([]*regexp.Regexp, len(partitions))
+		for i, p := range partitions {
+			regionRegexes[i] = regexp.MustCompile(p.regionRegex)
+		}
+	})
+	for i, re := range regionRegexes {
+		if re.MatchString(region) {
+			return partitions[i]
+		}
+	}
+
+	for _, p := range partitions {
+		if p.id == defaultPartitionID {
+			return p
+		}
+	}
+	return partition{id: defaultPartitionID, dnsSuffix: "amazonaws.com"}
+}
+
+// Returns the DNS suffix of the partition of the region, e.g. amazonaws.com.cn for cn-north-1.
+func dnsSuffix(region string) string {
+	return partitionOf(region).dnsSuffix
+}
@@ -0,0 +1,61 @@
+// Code generated by partitionsgen from github.com/aws/aws-sdk-go-v2@v1.32.4/internal/endpoints/awsrulesfn/partitions.json; DO NOT EDIT.
+
+package signer
+
+// Partitions of the AWS SDK partitions metadata, in metadata order.
+var partitions = []partition{
+	{id: "aws", dnsSuffix: "amazonaws.com", regionRegex: "^(us|eu|ap|sa|ca|me|af|il|mx)\\-\\w+\\-\\d+$"},
+	{id: "aws-cn", dnsSuffix: "amazonaws.com.cn", regionRegex: "^cn\\-\\w+\\-\\d+$"},
+	{id: "aws-us-gov", dnsSuffix: "amazonaws.com", regionRegex: "^us\\-gov\\-\\w+\\-\\d+$"},
+	{id: "aws-iso", dnsSuffix: "c2s.ic.gov", regionRegex: "^us\\-iso\\-\\w+\\-\\d+$"},
+	{id: "aws-iso-b", dnsSuffix: "sc2s.sgov.gov", regionRegex: "^us\\-isob\\-\\w+\\-\\d+$"},
+	{id: "aws-iso-e", dnsSuffix: "cloud.adc-e.uk", regionRegex: "^eu\\-isoe\\-\\w+\\-\\d+$"},
+	{id: "aws-iso-f", dnsSuffix: "csp.hci.ic.gov", regionRegex: "^us\\-isof\\-\\w+\\-\\d+$"},
+}
+
+// Index in partitions of the partition of every region listed in the metadata.
+var partitionRegions = map[string]int{
+	"af-south-1":        0,
+	"ap-east-1":         0,
+	"ap-northeast-1":    0,
+	"ap-northeast-2":    0,
+	"ap-northeast-3":    0,
+	"ap-south-1":        0,
+	"ap-south-2":        0,
+	"ap-southeast-1":    0,
+	"ap-southeast-2":    0,
+	"ap-southeast-3":    0,
+	"ap-southeast-4":    0,
+	"ap-southeast-5":    0,
+	"aws-global":        0,
+	"ca-central-1":      0,
+	"ca-west-1":         0,
+	"eu-central-1":      0,
+	"eu-central-2":      0,
+	"eu-north-1":        0,
+	"eu-south-1":        0,
+	"eu-south-2":        0,
+	"eu-west-1":         0,
+	"eu-west-2":         0,
+	"eu-west-3":         0,
+	"il-central-1":      0,
+	"me-central-1":      0,
+	"me-south-1":        0,
+	"sa-east-1":         0,
+	"us-east-1":         0,
+	"us-east-2":         0,
+	"us-west-1":         0,
+	"us-west-2":         0,
+	"aws-cn-global":     1,
+	"cn-north-1":        1,
+	"cn-northwest-1":    1,
+	"aws-us-gov-global": 2,
+	"us-gov-east-1":     2,
+	"us-gov-west-1":     2,
+	"aws-iso-global":    3,
+	"us-iso-east-1":     3,
+	"us-iso-west-1":     3,
+	"aws-iso-b-global":  4,
+	"us-isob-east-1":    4,
+	"eu-isoe-west-1":    5,
+}
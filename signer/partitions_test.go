@@ -0,0 +1,40 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionOf(t *testing.T) {
+	for region, expected := range map[string]string{
+		"us-west-2":      "aws",
+		"cn-north-1":     "aws-cn",
+		"us-gov-west-1":  "aws-us-gov",
+		"us-iso-east-1":  "aws-iso",
+		"eu-west-9":      "aws",
+		"cn-southwest-9": "aws-cn",
+		"unknown":        "aws",
+	} {
+		assert.Equal(t, expected, partitionOf(region).id, region)
+	}
+}
+
+func TestDNSSuffix(t *testing.T) {
+	assert.Equal(t, "amazonaws.com", dnsSuffix("us-east-1"))
+	assert.Equal(t, "amazonaws.com.cn", dnsSuffix("cn-northwest-1"))
+	assert.Equal(t, "c2s.ic.gov", dnsSuffix("us-iso-west-1"))
+}
+
+func TestParseBrokerEndpointInOtherPartitions(t *testing.T) {
+	endpoint, err := ParseBrokerEndpoint("b-1.orders.abc123.c2.kafka.cn-north-1.amazonaws.com.cn:9098")
+	assert.NoError(t, err)
+	assert.Equal(t, "cn-north-1", endpoint.Region)
+
+	endpoint, err = ParseBrokerEndpoint("b-1.orders.abc123.c2.kafka.us-iso-east-1.c2s.ic.gov:9098")
+	assert.NoError(t, err)
+	assert.Equal(t, "us-iso-east-1", endpoint.Region)
+
+	_, err = ParseBrokerEndpoint("b-1.orders.abc123.c2.kafka.us-east-1.amazonaws.com.cn:9098")
+	assert.Error(t, err)
+}
@@ -0,0 +1,372 @@
+package signer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	saslHandshakeAPIKey    = 17            // Kafka api key of SaslHandshake requests.
+	saslAuthenticateAPIKey = 36            // Kafka api key of SaslAuthenticate requests.
+	probeClientID          = LibName       // Client id sent to the broker.
+	oauthBearerMechanism   = "OAUTHBEARER" // SASL mechanism of MSK IAM auth tokens.
+	maxProbeResponseSize   = 1 << 20       // Upper bound of the broker responses read into memory.
+)
+
+// ErrProbeAuthenticationFailed is returned by Probe when the broker rejected the SASL handshake or the auth token.
+var ErrProbeAuthenticationFailed = errors.New("broker rejected sasl authentication")
+
+// ProbeOptions configures Probe.
+type ProbeOptions struct {
+	// TLSConfig is the TLS configuration of the broker connection. The server name is set from the broker address
+	// when empty.
+	TLSConfig *tls.Config
+
+	// ProxyURL is the http or https proxy the broker connection is tunneled through with CONNECT. The broker is
+	// dialed directly when nil.
+	ProxyURL *url.URL
+
+	// Timeout bounds the whole probe when ctx has no deadline. DefaultProbeTimeout is used when zero.
+	Timeout time.Duration
+}
+
+// DefaultProbeTimeout bounds a probe when neither ctx nor the options set a deadline.
+const DefaultProbeTimeout = 30 * time.Second
+
+// ProbeResult reports how far a probe got.
+type ProbeResult struct {
+	// Broker is the probed broker address.
+	Broker string
+
+	// TLSVersion is the negotiated TLS version, zero when the TLS handshake did not complete.
+	TLSVersion uint16
+
+	// Mechanisms are the SASL mechanisms enabled on the broker, as reported by the SASL handshake.
+	Mechanisms []string
+
+	// Authenticated reports whether the broker accepted the auth token.
+	Authenticated bool
+
+	// ErrorCode is the Kafka error code of the rejected SASL handshake or authentication.
+	ErrorCode int16
+
+	// ErrorMessage is the error message of the broker for a rejected auth token.
+	ErrorMessage string
+
+	// SessionLifetime is the session lifetime granted by the broker to the authenticated connection.
+	SessionLifetime time.Duration
+
+	// Duration is how long the probe took.
+	Duration time.Duration
+}
+
+// Probe connects to the broker, performs the TLS handshake and authenticates with SASL/OAUTHBEARER using a token of
+// the provider, so connectivity and IAM setup can be verified without a Kafka client. The result reports how far the
+// probe got along with the broker error, and the error is nil only when the broker accepted the token.
+func Probe(
+	ctx context.Context, broker string, provider TokenProvider, optFns ...func(*ProbeOptions),
+) (*ProbeResult, error) {
+	var options ProbeOptions
+	for _, fn := range optFns {
+		fn(&options)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		timeout := options.Timeout
+		if timeout <= 0 {
+			timeout = DefaultProbeTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	result := &ProbeResult{Broker: broker}
+	err := probe(ctx, broker, provider, options, result)
+	result.Duration = time.Since(start)
+	return result, err
+}
+
+// Runs the probe, recording its progress in the result.
+func probe(
+	ctx context.Context, broker string, provider TokenProvider, options ProbeOptions, result *ProbeResult,
+) error {
+	token, err := provider.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	conn, err := dialBroker(ctx, broker, options)
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker %s: %w", broker, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	tlsConn := tls.Client(conn, brokerTLSConfig(broker, options.TLSConfig))
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return fmt.Errorf("tls handshake with broker %s failed: %w", broker, err)
+	}
+	result.TLSVersion = tlsConn.ConnectionState().Version
+
+	kafkaConn := &probeConn{rw: tlsConn}
+	if err := kafkaConn.saslHandshake(result); err != nil {
+		return err
+	}
+	return kafkaConn.saslAuthenticate(token.Value, result)
+}
+
+// Dials the broker, through the proxy when configured.
+func dialBroker(ctx context.Context, broker string, options ProbeOptions) (net.Conn, error) {
+	var dialer net.Dialer
+	if options.ProxyURL == nil {
+		return dialer.DialContext(ctx, "tcp", broker)
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddress(options.ProxyURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+	}
+	if options.ProxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: options.ProxyURL.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("tls handshake with proxy failed: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	if err := connectThroughProxy(ctx, conn, broker, options.ProxyURL); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Returns the host and port of the proxy, defaulting the port from the scheme.
+func proxyAddress(proxyURL *url.URL) string {
+	if proxyURL.Port() != "" {
+		return proxyURL.Host
+	}
+	if proxyURL.Scheme == "https" {
+		return net.JoinHostPort(proxyURL.Hostname(), "443")
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), "80")
+}
+
+// Opens a tunnel to the broker with an HTTP CONNECT request.
+func connectThroughProxy(ctx context.Context, conn net.Conn, broker string, proxyURL *url.URL) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodConnect, "", nil)
+	if err != nil {
+		return err
+	}
+	req.URL = &url.URL{Opaque: broker}
+	req.Host = broker
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("failed to send proxy connect request: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("failed to read proxy connect response: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy refused to connect to broker %s: %s", broker, resp.Status)
+	}
+	return nil
+}
+
+// Returns the TLS configuration of the broker connection, with the server name set from the broker address.
+func brokerTLSConfig(broker string, tlsConfig *tls.Config) *tls.Config {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(broker)
+		if err != nil {
+			host = broker
+		}
+		tlsConfig.ServerName = host
+	}
+	return tlsConfig
+}
+
+// Kafka connection exchanging the SASL requests of the probe.
+type probeConn struct {
+	rw            io.ReadWriter
+	correlationID int32
+}
+
+// Sends a SaslHandshake v1 request for OAUTHBEARER.
+func (c *probeConn) saslHandshake(result *ProbeResult) error {
+	var body bytes.Buffer
+	writeKafkaString(&body, oauthBearerMechanism)
+
+	resp, err := c.roundTrip(saslHandshakeAPIKey, 1, body.Bytes())
+	if err != nil {
+		return fmt.Errorf("sasl handshake failed: %w", err)
+	}
+
+	var errorCode int16
+	var count int32
+	if err := readKafkaValues(resp, &errorCode, &count); err != nil {
+		return fmt.Errorf("invalid sasl handshake response: %w", err)
+	}
+	for i := int32(0); i < count; i++ {
+		mechanism, err := readKafkaString(resp)
+		if err != nil {
+			return fmt.Errorf("invalid sasl handshake response: %w", err)
+		}
+		result.Mechanisms = append(result.Mechanisms, mechanism)
+	}
+
+	if errorCode != 0 {
+		result.ErrorCode = errorCode
+		return fmt.Errorf("%w: sasl handshake error code %d, broker enabled mechanisms %v",
+			ErrProbeAuthenticationFailed, errorCode, result.Mechanisms)
+	}
+	return nil
+}
+
+// Sends a SaslAuthenticate v1 request with the OAUTHBEARER client response carrying the token.
+func (c *probeConn) saslAuthenticate(token string, result *ProbeResult) error {
+	var body bytes.Buffer
+	authBytes := "n,,\x01auth=Bearer " + token + "\x01\x01"
+	_ = binary.Write(&body, binary.BigEndian, int32(len(authBytes)))
+	body.WriteString(authBytes)
+
+	resp, err := c.roundTrip(saslAuthenticateAPIKey, 1, body.Bytes())
+	if err != nil {
+		return fmt.Errorf("sasl authenticate failed: %w", err)
+	}
+
+	var errorCode int16
+	if err := readKafkaValues(resp, &errorCode); err != nil {
+		return fmt.Errorf("invalid sasl authenticate response: %w", err)
+	}
+	errorMessage, err := readKafkaString(resp)
+	if err != nil {
+		return fmt.Errorf("invalid sasl authenticate response: %w", err)
+	}
+	var authBytesLength int32
+	if err := readKafkaValues(resp, &authBytesLength); err != nil {
+		return fmt.Errorf("invalid sasl authenticate response: %w", err)
+	}
+	if authBytesLength > 0 {
+		if _, err := io.CopyN(io.Discard, resp, int64(authBytesLength)); err != nil {
+			return fmt.Errorf("invalid sasl authenticate response: %w", err)
+		}
+	}
+	var sessionLifetimeMs int64
+	if err := readKafkaValues(resp, &sessionLifetimeMs); err != nil {
+		return fmt.Errorf("invalid sasl authenticate response: %w", err)
+	}
+
+	if errorCode != 0 {
+		result.ErrorCode = errorCode
+		result.ErrorMessage = errorMessage
+		return fmt.Errorf("%w: error code %d: %s", ErrProbeAuthenticationFailed, errorCode, errorMessage)
+	}
+
+	result.Authenticated = true
+	result.SessionLifetime = time.Duration(sessionLifetimeMs) * time.Millisecond
+	return nil
+}
+
+// Sends a request with a v1 request header and returns the response body following the correlation id.
+func (c *probeConn) roundTrip(apiKey int16, apiVersion int16, body []byte) (*bytes.Reader, error) {
+	c.correlationID++
+
+	var req bytes.Buffer
+	_ = binary.Write(&req, binary.BigEndian, apiKey)
+	_ = binary.Write(&req, binary.BigEndian, apiVersion)
+	_ = binary.Write(&req, binary.BigEndian, c.correlationID)
+	writeKafkaString(&req, probeClientID)
+	req.Write(body)
+
+	frame := make([]byte, 4, 4+req.Len())
+	binary.BigEndian.PutUint32(frame, uint32(req.Len()))
+	if _, err := c.rw.Write(append(frame, req.Bytes()...)); err != nil {
+		return nil, err
+	}
+
+	var size int32
+	if err := binary.Read(c.rw, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size < 4 || size > maxProbeResponseSize {
+		return nil, fmt.Errorf("invalid response size %d", size)
+	}
+	resp := make([]byte, size)
+	if _, err := io.ReadFull(c.rw, resp); err != nil {
+		return nil, err
+	}
+
+	if correlationID := int32(binary.BigEndian.Uint32(resp)); correlationID != c.correlationID {
+		return nil, fmt.Errorf("unexpected correlation id %d, expected %d", correlationID, c.correlationID)
+	}
+	return bytes.NewReader(resp[4:]), nil
+}
+
+// Writes a Kafka string, prefixed with its int16 length.
+func writeKafkaString(w *bytes.Buffer, s string) {
+	_ = binary.Write(w, binary.BigEndian, int16(len(s)))
+	w.WriteString(s)
+}
+
+// Reads a Kafka nullable string, returning an empty string for null.
+func readKafkaString(r io.Reader) (string, error) {
+	var length int16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	if length < 0 {
+		return "", nil
+	}
+	s := make([]byte, length)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// Reads fixed size big endian values.
+func readKafkaValues(r io.Reader, values ...interface{}) error {
+	for _, value := range values {
+		if err := binary.Read(r, binary.BigEndian, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
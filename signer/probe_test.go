@@ -0,0 +1,206 @@
+package signer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Generates a self-signed certificate for localhost and the client TLS config trusting it.
+func newProbeTLSConfigs(t *testing.T) (*tls.Config, *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	server := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	return server, &tls.Config{RootCAs: roots, ServerName: "localhost"}
+}
+
+// Starts a fake broker answering the SASL requests of the probe, accepting only the expected token.
+func startFakeBroker(t *testing.T, serverTLS *tls.Config, expectedToken string) string {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverTLS)
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeBroker(conn, expectedToken)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveFakeBroker(conn net.Conn, expectedToken string) {
+	defer conn.Close()
+	for {
+		var size int32
+		if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+			return
+		}
+		req := make([]byte, size)
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		apiKey := int16(binary.BigEndian.Uint16(req))
+		correlationID := binary.BigEndian.Uint32(req[4:])
+		clientIDLength := int(binary.BigEndian.Uint16(req[8:]))
+		body := req[10+clientIDLength:]
+
+		var resp bytes.Buffer
+		_ = binary.Write(&resp, binary.BigEndian, correlationID)
+		switch apiKey {
+		case saslHandshakeAPIKey:
+			_ = binary.Write(&resp, binary.BigEndian, int16(0))
+			_ = binary.Write(&resp, binary.BigEndian, int32(1))
+			writeKafkaString(&resp, oauthBearerMechanism)
+		case saslAuthenticateAPIKey:
+			authBytes := string(body[4:])
+			if authBytes == "n,,\x01auth=Bearer "+expectedToken+"\x01\x01" {
+				_ = binary.Write(&resp, binary.BigEndian, int16(0))
+				_ = binary.Write(&resp, binary.BigEndian, int16(-1))
+				_ = binary.Write(&resp, binary.BigEndian, int32(0))
+				_ = binary.Write(&resp, binary.BigEndian, int64(900000))
+			} else {
+				_ = binary.Write(&resp, binary.BigEndian, int16(58))
+				writeKafkaString(&resp, "[58] SASL_AUTHENTICATION_FAILED: Access denied")
+				_ = binary.Write(&resp, binary.BigEndian, int32(0))
+				_ = binary.Write(&resp, binary.BigEndian, int64(0))
+			}
+		}
+
+		frame := make([]byte, 4)
+		binary.BigEndian.PutUint32(frame, uint32(resp.Len()))
+		if _, err := conn.Write(append(frame, resp.Bytes()...)); err != nil {
+			return
+		}
+	}
+}
+
+// Starts an HTTP CONNECT proxy tunneling to any address.
+func startConnectProxy(t *testing.T) *url.URL {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil || req.Method != http.MethodConnect {
+					return
+				}
+				target, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+					return
+				}
+				defer target.Close()
+				io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+	return &url.URL{Scheme: "http", Host: listener.Addr().String()}
+}
+
+func TestProbeAuthenticated(t *testing.T) {
+	serverTLS, clientTLS := newProbeTLSConfigs(t)
+	broker := startFakeBroker(t, serverTLS, "TEST-PROBE-TOKEN")
+
+	result, err := Probe(Ctx, broker, StaticTokenProvider("TEST-PROBE-TOKEN", time.Now().Add(time.Hour)),
+		func(o *ProbeOptions) { o.TLSConfig = clientTLS })
+
+	assert.NoError(t, err)
+	assert.True(t, result.Authenticated)
+	assert.Equal(t, []string{oauthBearerMechanism}, result.Mechanisms)
+	assert.Equal(t, 15*time.Minute, result.SessionLifetime)
+	assert.NotZero(t, result.TLSVersion)
+}
+
+func TestProbeRejected(t *testing.T) {
+	serverTLS, clientTLS := newProbeTLSConfigs(t)
+	broker := startFakeBroker(t, serverTLS, "TEST-PROBE-TOKEN")
+
+	result, err := Probe(Ctx, broker, StaticTokenProvider("TEST-WRONG-TOKEN", time.Now().Add(time.Hour)),
+		func(o *ProbeOptions) { o.TLSConfig = clientTLS })
+
+	assert.ErrorIs(t, err, ErrProbeAuthenticationFailed)
+	assert.False(t, result.Authenticated)
+	assert.Equal(t, int16(58), result.ErrorCode)
+	assert.Equal(t, "[58] SASL_AUTHENTICATION_FAILED: Access denied", result.ErrorMessage)
+}
+
+func TestProbeThroughProxy(t *testing.T) {
+	serverTLS, clientTLS := newProbeTLSConfigs(t)
+	broker := startFakeBroker(t, serverTLS, "TEST-PROBE-TOKEN")
+	proxyURL := startConnectProxy(t)
+
+	result, err := Probe(Ctx, broker, StaticTokenProvider("TEST-PROBE-TOKEN", time.Now().Add(time.Hour)),
+		func(o *ProbeOptions) {
+			o.TLSConfig = clientTLS
+			o.ProxyURL = proxyURL
+		})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Authenticated)
+}
+
+func TestProbeUntrustedCertificate(t *testing.T) {
+	serverTLS, _ := newProbeTLSConfigs(t)
+	broker := startFakeBroker(t, serverTLS, "TEST-PROBE-TOKEN")
+
+	result, err := Probe(Ctx, broker, NopProvider{})
+
+	assert.ErrorContains(t, err, "tls handshake")
+	assert.Zero(t, result.TLSVersion)
+}
+
+func TestProbeUnreachableBroker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	broker := listener.Addr().String()
+	listener.Close()
+
+	_, err = Probe(Ctx, broker, NopProvider{}, func(o *ProbeOptions) { o.Timeout = 5 * time.Second })
+
+	assert.True(t, strings.HasPrefix(err.Error(), "failed to connect to broker"))
+}
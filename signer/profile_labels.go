@@ -0,0 +1,25 @@
+package signer
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+const (
+	ProfileLabelStep   = "msk_iam_step"   // ProfileLabelStep is the pprof label naming the token generation step.
+	ProfileLabelRegion = "msk_iam_region" // ProfileLabelRegion is the pprof label holding the signing region.
+	ProfileLabelSource = "msk_iam_source" // ProfileLabelSource is the pprof label holding the credentials source.
+)
+
+// Runs f with pprof labels naming the token generation step, the region and the credentials source when known, added
+// to the labels of ctx, so CPU and heap profiles attribute the cost of generating tokens, e.g. during reconnect
+// storms, to the step, region and source.
+func profileGenerationStep(
+	ctx context.Context, step string, region string, source string, f func(ctx context.Context),
+) {
+	labels := []string{ProfileLabelStep, step, ProfileLabelRegion, region}
+	if source != "" {
+		labels = append(labels, ProfileLabelSource, source)
+	}
+	pprof.Do(ctx, pprof.Labels(labels...), f)
+}
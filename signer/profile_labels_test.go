@@ -0,0 +1,56 @@
+package signer
+
+import (
+	"context"
+	"net/http"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Records the pprof labels of the contexts it is called with.
+type labelRecorder struct {
+	labels map[string]map[string]string
+}
+
+func (r *labelRecorder) record(ctx context.Context, step string) {
+	labels := map[string]string{}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		labels[key] = value
+		return true
+	})
+	r.labels[step] = labels
+}
+
+func (r *labelRecorder) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	r.record(ctx, SpanLoadCredentials)
+	return aws.Credentials{
+		AccessKeyID: "TEST-LABELS-ACCESS-KEY", SecretAccessKey: "TEST-LABELS-SECRET-KEY", Source: "TestLabels",
+	}, nil
+}
+
+func (r *labelRecorder) PresignRequest(
+	ctx context.Context, req *http.Request, region string, credentials aws.Credentials, signingTime time.Time,
+) (string, error) {
+	r.record(ctx, SpanSignToken)
+	return SigV4Signer{}.PresignRequest(ctx, req, region, credentials, signingTime)
+}
+
+func TestGenerateAuthTokenProfileLabels(t *testing.T) {
+	recorder := &labelRecorder{labels: map[string]map[string]string{}}
+	ctx := pprof.WithLabels(Ctx, pprof.Labels("consumer", "orders"))
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(ctx, TestRegion, recorder, WithRequestSigner(recorder))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"consumer": "orders", ProfileLabelStep: SpanLoadCredentials, ProfileLabelRegion: TestRegion,
+	}, recorder.labels[SpanLoadCredentials])
+	assert.Equal(t, map[string]string{
+		"consumer": "orders", ProfileLabelStep: SpanSignToken, ProfileLabelRegion: TestRegion,
+		ProfileLabelSource: "TestLabels",
+	}, recorder.labels[SpanSignToken])
+}
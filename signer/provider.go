@@ -0,0 +1,302 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Provider generates auth tokens and caches them until they are close to expiry, so Kafka clients can ask for a token
+// on every connection without signing a new one each time. It is safe for concurrent use.
+type Provider struct {
+	region              string
+	loadCredentials     credentialsLoader
+	credentialsProvider string
+	options             Options
+
+	// generating is held while a token is generated, so callers asking for a token meanwhile wait for it instead of
+	// generating their own. It also guards streamed and coldStartRestored. Unlike mu, which guards the token state, it
+	// is held during network calls and waited for with the caller's context.
+	generating chan struct{}
+
+	mu            sync.Mutex
+	token         *Token
+	refreshAt     time.Time
+	next          *Token
+	nextRefreshAt time.Time
+	lastErr       error
+	lastErrTime   time.Time
+	issuedAt      []time.Time
+	streamed      *Token
+	stats         ProviderStats
+
+	credentials       *expiringCredentialsCache
+	coldStartRestored bool
+	restored          *Token
+}
+
+// NewProvider returns a Provider generating auth tokens for the region from the credentials of credentialsProvider.
+// When credentialsProvider is nil, credentials are those of the role of WithRoleARN, if any, or of the default
+// credentials provider chain.
+func NewProvider(region string, credentialsProvider aws.CredentialsProvider, optFns ...Option) *Provider {
+	options := resolveOptions(optFns)
+	if options.RefreshStrategy == nil {
+		options.RefreshStrategy = FractionRefreshStrategy{Fraction: DefaultRefreshFraction}
+	}
+	if options.STSThrottleMonitor == nil {
+		options.STSThrottleMonitor = NewSTSThrottleMonitor()
+	}
+
+	provider := &Provider{region: region, options: options, generating: make(chan struct{}, 1)}
+	switch {
+	case credentialsProvider != nil:
+		provider.setCredentialsLoader(credentialsProviderLoader(credentialsProvider),
+			fmt.Sprintf("%T", credentialsProvider))
+	case options.RoleARN != "":
+		provider.setCredentialsLoader(roleCredentialsLoader(region, options), "assumed role "+options.RoleARN)
+	default:
+		provider.setCredentialsLoader(defaultCredentialsLoader(region, options), defaultCredentialChain)
+	}
+	return provider
+}
+
+// Sets the loader the provider retrieves credentials with, caching the credentials until they are about to expire.
+// name describes the credentials in debug snapshots.
+func (p *Provider) setCredentialsLoader(loadCredentials credentialsLoader, name string) {
+	window := max(DefaultCredentialsExpiryWindow, p.options.MinCredentialLifetime)
+	p.credentials = newExpiringCredentialsCache(loadCredentials, window)
+	p.loadCredentials = p.credentials.load
+	p.credentialsProvider = name
+}
+
+// Token returns the cached auth token, generating a new one when none is cached or the refresh strategy decides the
+// cached one must be replaced.
+func (p *Provider) Token(ctx context.Context) (*Token, error) {
+	token, err := p.cachedOrNewToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.observeTokenTTL(token)
+	return token, nil
+}
+
+// Warm starts generating the first auth token in the background, resolving credentials and signing the token while the
+// service carries on starting up, so the first Kafka connection doesn't pay the cold path latency. Token calls made
+// before warm-up completes wait for its token instead of generating another one. The returned channel receives the
+// warm-up error, or nil, once done.
+func (p *Provider) Warm(ctx context.Context) <-chan error {
+	done := make(chan error, 1)
+
+	if err := p.acquireGeneration(ctx); err != nil {
+		done <- err
+		return done
+	}
+	go func() {
+		token, err := p.cachedOrNewTokenGenerating(ctx)
+		p.releaseGeneration()
+
+		if err == nil {
+			p.observeTokenTTL(token)
+		}
+		done <- err
+	}()
+
+	return done
+}
+
+// CurrentTokenTTL returns the remaining lifetime of the cached auth token, or zero when no unexpired token is cached.
+func (p *Provider) CurrentTokenTTL() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token == nil {
+		return 0
+	}
+	return tokenTTL(p.token)
+}
+
+// STSThrottleStats returns the throttling of the sts calls made while retrieving credentials. Calls made by a
+// credentials provider passed to NewProvider are tracked when its client has the STSThrottleMonitor middleware added.
+func (p *Provider) STSThrottleStats() STSThrottleStats {
+	return p.options.STSThrottleMonitor.Stats()
+}
+
+// Reports whether the provider holds a token it would return without generating a new one.
+func (p *Provider) hasFreshToken(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.token != nil && now.Before(p.refreshAt) || p.next != nil && now.Before(p.nextRefreshAt)
+}
+
+// Returns the cached token while it is fresh, otherwise generates and caches a new one. Callers arriving while a token
+// is generated wait for it, or for ctx to be done.
+func (p *Provider) cachedOrNewToken(ctx context.Context) (*Token, error) {
+	if token, ok := p.cachedToken(time.Now()); ok {
+		return token, nil
+	}
+
+	if err := p.acquireGeneration(ctx); err != nil {
+		return nil, err
+	}
+	defer p.releaseGeneration()
+
+	return p.cachedOrNewTokenGenerating(ctx)
+}
+
+// Waits for the token generation in progress, if any, to finish and claims the next one, failing when ctx is done
+// first.
+func (p *Provider) acquireGeneration(ctx context.Context) error {
+	select {
+	case p.generating <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Releases the token generation claimed with acquireGeneration.
+func (p *Provider) releaseGeneration() {
+	<-p.generating
+}
+
+// Returns the cached token while it is fresh, otherwise promotes the next token generated during the overlap window,
+// counting a cache hit. It reports false when a new token must be generated.
+func (p *Provider) cachedToken(now time.Time) (*Token, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != nil && now.Before(p.refreshAt) {
+		p.stats.TokensServed++
+		p.stats.CacheHits++
+		return p.token, true
+	}
+
+	if p.next != nil && now.Before(p.nextRefreshAt) {
+		p.token, p.refreshAt = p.next, p.nextRefreshAt
+		p.next = nil
+		p.stats.TokensServed++
+		p.stats.CacheHits++
+		return p.token, true
+	}
+	return nil, false
+}
+
+// Returns the cached token while it is fresh, otherwise promotes the next token generated during the overlap window
+// or generates and caches a new one. The token persisted in the cold start store, if any, is restored first. The caller
+// holds the token generation.
+func (p *Provider) cachedOrNewTokenGenerating(ctx context.Context) (*Token, error) {
+	p.restoreColdStart(ctx)
+	if token, ok := p.cachedToken(time.Now()); ok {
+		return token, nil
+	}
+
+	token, err := p.newColdStartToken(ctx)
+
+	p.mu.Lock()
+	stale := err != nil && (p.options.StaleTokenFallback && p.token != nil || p.servesRestoredTokenLocked())
+	if stale || err == nil {
+		p.stats.TokensServed++
+	}
+	if stale {
+		p.stats.StaleTokensServed++
+		token = p.token
+	}
+	p.mu.Unlock()
+
+	if stale {
+		p.reportStaleToken(ctx, token, err)
+		return token, nil
+	}
+	return token, err
+}
+
+// ForceRefresh invalidates cached credentials and replaces the cached token with one signed with freshly retrieved
+// credentials, so new connections pick up rotated keys right away. The cached token is kept when generation fails.
+func (p *Provider) ForceRefresh(ctx context.Context) (*Token, error) {
+	if err := p.acquireGeneration(ctx); err != nil {
+		return nil, err
+	}
+	token, err := p.newToken(ctx, func(ctx context.Context, _ bool) (*aws.Credentials, error) {
+		return p.loadCredentials(ctx, true)
+	})
+	p.releaseGeneration()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.stats.TokensServed++
+	p.mu.Unlock()
+
+	p.observeTokenTTL(token)
+	return token, nil
+}
+
+// Generates a new token from the credentials returned by the loader and caches it, discarding any next token. The
+// caller holds the token generation.
+func (p *Provider) newToken(ctx context.Context, loadCredentials credentialsLoader) (*Token, error) {
+	token, refreshAt, err := p.generateToken(ctx, loadCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.token, p.refreshAt = token, refreshAt
+	p.next = nil
+	p.mu.Unlock()
+	return token, nil
+}
+
+// Generates a new token from the credentials returned by the loader, returning it with the time it must be replaced
+// at. p.mu is only held to update the issuance quota and the stats, not while credentials are retrieved, the token
+// is signed or persisted. The caller holds the token generation.
+func (p *Provider) generateToken(ctx context.Context, loadCredentials credentialsLoader) (*Token, time.Time, error) {
+	issuedAt := time.Now()
+	p.mu.Lock()
+	err := p.takeIssuanceQuotaLocked(issuedAt)
+	if err != nil {
+		p.recordGenerationLocked(issuedAt, err)
+	}
+	p.mu.Unlock()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	token, err := generateAuthToken(ctx, p.region, p.options, loadCredentials)
+	var refreshAt time.Time
+	if err == nil {
+		refreshAt = p.options.RefreshStrategy.RefreshAt(issuedAt, token)
+		refreshAt, err = enforceMinRefreshInterval(issuedAt, refreshAt, token, p.options.MinRefreshInterval)
+	}
+
+	p.mu.Lock()
+	p.recordGenerationLocked(time.Now(), err)
+	p.mu.Unlock()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	p.saveColdStart(ctx, issuedAt, token)
+	if !isNoOpRefresh(p.streamed, token) {
+		writeTokenToStream(ctx, p.options, token)
+		p.streamed = token
+	}
+	return token, refreshAt, nil
+}
+
+// Reports the remaining lifetime of the token to the configured observer.
+func (p *Provider) observeTokenTTL(token *Token) {
+	if p.options.TokenTTLObserver != nil {
+		p.options.TokenTTLObserver(tokenTTL(token))
+	}
+}
+
+// Returns the remaining lifetime of the token, never negative.
+func tokenTTL(token *Token) time.Duration {
+	return max(time.Until(token.Expiry(0)), 0)
+}
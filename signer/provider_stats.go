@@ -0,0 +1,67 @@
+package signer
+
+import "time"
+
+// ProviderStats counts the tokens a Provider served and generated since it was created, e.g. for autoscalers and
+// canary analysis to consume auth health without a metrics backend.
+type ProviderStats struct {
+	// TokensServed is the number of tokens returned by Token, Tokens, Warm and ForceRefresh.
+	TokensServed int64 `json:"tokensServed"`
+
+	// CacheHits is the number of tokens served from the cache without generating a new one, including next tokens
+	// generated ahead of time during the overlap window.
+	CacheHits int64 `json:"cacheHits"`
+
+	// StaleTokensServed is the number of cached tokens served past their refresh time because generating a new one
+	// failed.
+	StaleTokensServed int64 `json:"staleTokensServed"`
+
+	// Refreshes is the number of tokens generated, including next tokens and forced refreshes.
+	Refreshes int64 `json:"refreshes"`
+
+	// Failures is the number of failed token generations.
+	Failures int64 `json:"failures"`
+
+	// LastRefreshTime is when the last token was generated, zero when none was.
+	LastRefreshTime time.Time `json:"lastRefreshTime"`
+
+	// LastFailureTime is when the last token generation failed, zero when none did.
+	LastFailureTime time.Time `json:"lastFailureTime"`
+}
+
+// CacheHitRatio returns the fraction of served tokens that came from the cache, zero when none was served.
+func (s ProviderStats) CacheHitRatio() float64 {
+	if s.TokensServed == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(s.TokensServed)
+}
+
+// FailureRatio returns the fraction of token generations that failed, zero when none was attempted.
+func (s ProviderStats) FailureRatio() float64 {
+	attempts := s.Refreshes + s.Failures
+	if attempts == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(attempts)
+}
+
+// Stats returns a snapshot of the counters of the provider.
+func (p *Provider) Stats() ProviderStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.stats
+}
+
+// Records the outcome of a token generation, keeping failures as the last error. The caller holds p.mu.
+func (p *Provider) recordGenerationLocked(at time.Time, err error) {
+	if err != nil {
+		p.lastErr, p.lastErrTime = err, at
+		p.stats.Failures++
+		p.stats.LastFailureTime = at
+		return
+	}
+	p.stats.Refreshes++
+	p.stats.LastRefreshTime = at
+}
@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderStatsCountsCacheHits(t *testing.T) {
+	provider, _ := newCountingProvider()
+
+	for i := 0; i < 4; i++ {
+		_, err := provider.Token(Ctx)
+		assert.NoError(t, err)
+	}
+
+	stats := provider.Stats()
+	assert.Equal(t, int64(4), stats.TokensServed)
+	assert.Equal(t, int64(3), stats.CacheHits)
+	assert.Equal(t, int64(1), stats.Refreshes)
+	assert.Zero(t, stats.Failures)
+	assert.WithinDuration(t, time.Now(), stats.LastRefreshTime, time.Minute)
+	assert.True(t, stats.LastFailureTime.IsZero())
+	assert.Equal(t, 0.75, stats.CacheHitRatio())
+}
+
+func TestProviderStatsCountsFailuresAndStaleTokens(t *testing.T) {
+	reject := false
+	provider, _ := newCountingProvider(WithRefreshStrategy(alwaysRefreshStrategy{}), WithStaleTokenFallback(nil),
+		WithTokenValidators(func(*Token, TokenParams) error {
+			if reject {
+				return errors.New("denied")
+			}
+			return nil
+		}))
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	reject = true
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+	_, err = provider.ForceRefresh(Ctx)
+	assert.Error(t, err)
+
+	stats := provider.Stats()
+	assert.Equal(t, int64(2), stats.TokensServed)
+	assert.Zero(t, stats.CacheHits)
+	assert.Equal(t, int64(1), stats.StaleTokensServed)
+	assert.Equal(t, int64(1), stats.Refreshes)
+	assert.Equal(t, int64(2), stats.Failures)
+	assert.InDelta(t, 2.0/3.0, stats.FailureRatio(), 1e-9)
+	assert.Equal(t, stats, provider.DebugSnapshot().Stats)
+}
+
+func TestProviderStatsRatiosWithoutTokens(t *testing.T) {
+	var stats ProviderStats
+
+	assert.Zero(t, stats.CacheHitRatio())
+	assert.Zero(t, stats.FailureRatio())
+}
@@ -0,0 +1,195 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Builds a provider signing with credentials that count how often they are retrieved.
+func newCountingProvider(optFns ...Option) (*Provider, *sequenceCredentialsProvider) {
+	credentialsProvider := &sequenceCredentialsProvider{credentials: []aws.Credentials{{
+		AccessKeyID:     "TEST-PROVIDER-ACCESS-KEY",
+		SecretAccessKey: "TEST-PROVIDER-SECRET-KEY",
+	}}}
+	return NewProvider(TestRegion, credentialsProvider, optFns...), credentialsProvider
+}
+
+func TestProviderCachesToken(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider()
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	second, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, credentialsProvider.calls)
+}
+
+func TestProviderRefreshesAgingToken(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider()
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.WithinDuration(t, time.Now().Add(12*time.Minute), provider.refreshAt, 5*time.Second)
+	provider.refreshAt = time.Now().Add(-time.Second)
+	second, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.NotSame(t, first, second)
+	assert.Equal(t, 2, credentialsProvider.calls)
+}
+
+func TestProviderCurrentTokenTTL(t *testing.T) {
+	provider, _ := newCountingProvider()
+	assert.Equal(t, time.Duration(0), provider.CurrentTokenTTL())
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	ttl := provider.CurrentTokenTTL()
+	assert.Greater(t, ttl, 14*time.Minute)
+	assert.LessOrEqual(t, ttl, 15*time.Minute)
+}
+
+func TestProviderTokenTTLObserver(t *testing.T) {
+	var observed []time.Duration
+	provider, _ := newCountingProvider(WithTokenTTLObserver(func(ttl time.Duration) {
+		observed = append(observed, ttl)
+	}))
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Len(t, observed, 2)
+	assert.Greater(t, observed[0], 14*time.Minute)
+	assert.LessOrEqual(t, observed[1], observed[0])
+}
+
+func TestProviderDoesNotCacheFailures(t *testing.T) {
+	provider := NewProvider(TestRegion, aws.AnonymousCredentials{})
+
+	token, err := provider.Token(Ctx)
+
+	assert.Error(t, err)
+	assert.Nil(t, token)
+	assert.Equal(t, time.Duration(0), provider.CurrentTokenTTL())
+}
+
+func TestProviderConcurrentTokens(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := provider.Token(Ctx)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, token.Value)
+			provider.CurrentTokenTTL()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, credentialsProvider.calls)
+}
+
+func TestProviderDoesNotBlockWhileGenerating(t *testing.T) {
+	provider := NewProvider(TestRegion, blockingCredentialsProvider{})
+	generateCtx, cancel := context.WithCancel(Ctx)
+	generated := make(chan error, 1)
+	go func() {
+		_, err := provider.Token(generateCtx)
+		generated <- err
+	}()
+	assert.Eventually(t, func() bool { return len(provider.generating) == 1 }, time.Second, time.Millisecond)
+
+	assert.Equal(t, time.Duration(0), provider.CurrentTokenTTL())
+	assert.Equal(t, ProviderStats{}, provider.Stats())
+	assert.Nil(t, provider.DebugSnapshot().CachedToken)
+
+	waitCtx, waitCancel := context.WithTimeout(Ctx, 10*time.Millisecond)
+	defer waitCancel()
+	_, err := provider.Token(waitCtx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	cancel()
+	assert.Error(t, <-generated)
+}
+
+func TestProviderWarm(t *testing.T) {
+	var mu sync.Mutex
+	var observed []time.Duration
+	provider, credentialsProvider := newCountingProvider(WithTokenTTLObserver(func(ttl time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		observed = append(observed, ttl)
+	}))
+
+	warm := provider.Warm(Ctx)
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, <-warm)
+
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, 1, credentialsProvider.calls)
+	assert.Greater(t, provider.CurrentTokenTTL(), 14*time.Minute)
+	assert.Len(t, observed, 2)
+}
+
+func TestProviderWarmFailure(t *testing.T) {
+	provider := NewProvider(TestRegion, aws.AnonymousCredentials{})
+
+	assert.Error(t, <-provider.Warm(Ctx))
+	assert.Equal(t, time.Duration(0), provider.CurrentTokenTTL())
+}
+
+func TestProviderForceRefresh(t *testing.T) {
+	credentialsProvider := &sequenceCredentialsProvider{credentials: []aws.Credentials{
+		expiringCredentials("TEST-OLD-ACCESS-KEY", time.Hour),
+		expiringCredentials("TEST-ROTATED-ACCESS-KEY", time.Hour),
+	}}
+	provider := NewProvider(TestRegion, aws.NewCredentialsCache(credentialsProvider))
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	refreshed, err := provider.ForceRefresh(Ctx)
+	assert.NoError(t, err)
+	cached, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first.Value, refreshed.Value)
+	assert.Same(t, refreshed, cached)
+	assert.Equal(t, 2, credentialsProvider.calls)
+	assert.Contains(t, decodeTokenParams(t, refreshed.Value).Get("X-Amz-Credential"), "TEST-ROTATED-ACCESS-KEY")
+}
+
+func TestProviderForceRefreshFailureKeepsCachedToken(t *testing.T) {
+	calls := 0
+	provider := NewProvider(TestRegion, aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		calls++
+		if calls > 1 {
+			return aws.Credentials{}, errors.New("rotation in progress")
+		}
+		return aws.Credentials{AccessKeyID: "TEST-ACCESS-KEY", SecretAccessKey: "TEST-SECRET-KEY"}, nil
+	}))
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	_, err = provider.ForceRefresh(Ctx)
+	assert.ErrorContains(t, err, "rotation in progress")
+	cached, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Same(t, first, cached)
+}
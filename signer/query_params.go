@@ -0,0 +1,71 @@
+package signer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ClusterARNQueryKey is the signed query parameter holding the cluster ARN a token is scoped to.
+const ClusterARNQueryKey = "ResourceArn"
+
+// Collects the extra query parameters to sign into the auth token, rejecting the ones the signer manages itself.
+func extraQueryParameters(region string, options Options) (url.Values, error) {
+	params := url.Values{}
+
+	profile, err := resolveCompatibilityProfile(options)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range options.SignedQueryParameters {
+		if isReservedQueryKey(key, profile) {
+			return nil, fmt.Errorf("query parameter %s is managed by the signer and cannot be overridden", key)
+		}
+		params[key] = append([]string(nil), values...)
+	}
+
+	if options.ClusterARN != "" {
+		if err := validateClusterARN(options.ClusterARN, region); err != nil {
+			return nil, err
+		}
+		for key := range params {
+			if strings.EqualFold(key, ClusterARNQueryKey) {
+				return nil, fmt.Errorf("query parameter %s cannot be set together with the cluster ARN", key)
+			}
+		}
+		params.Set(ClusterARNQueryKey, options.ClusterARN)
+	}
+
+	return params, nil
+}
+
+// Reports whether the query parameter is set by the signer, as named by the compatibility profile, or by aws sig v4.
+func isReservedQueryKey(key string, profile CompatibilityProfile) bool {
+	switch {
+	case strings.EqualFold(key, ActionType), strings.EqualFold(key, UserAgentKey):
+		return true
+	case strings.EqualFold(key, profile.ActionKey), strings.EqualFold(key, profile.ExpiresKey):
+		return true
+	case strings.EqualFold(key, profile.UserAgentKey):
+		return true
+	case isSigV4QueryKey(key):
+		return true
+	default:
+		return false
+	}
+}
+
+// Validates that the ARN identifies an MSK cluster in the signing region.
+func validateClusterARN(clusterARN string, region string) error {
+	parsed, err := parseClusterARN(clusterARN)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Region != region {
+		return fmt.Errorf("cluster arn %s is not in the signing region %s", clusterARN, region)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package signer
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+const TestClusterARN = "arn:aws:kafka:us-west-2:123456789012:cluster/demo/7e4f5a8b-1234-5678-9abc-def012345678-1"
+
+// Decodes the auth token and returns the query parameters of the signed url.
+func decodeTokenParams(t *testing.T, token string) url.Values {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	assert.NoError(t, err)
+
+	parsedURL, err := url.Parse(string(decoded))
+	assert.NoError(t, err)
+	return parsedURL.Query()
+}
+
+var testQueryCredentialsProvider = MockCredentialsProvider{credentials: aws.Credentials{
+	AccessKeyID:     "TEST-QUERY-ACCESS-KEY",
+	SecretAccessKey: "TEST-QUERY-SECRET-KEY",
+}}
+
+func TestGenerateAuthTokenWithClusterARN(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithClusterARN(TestClusterARN))
+
+	assert.NoError(t, err)
+	params := decodeTokenParams(t, token)
+	assert.Equal(t, TestClusterARN, params.Get(ClusterARNQueryKey))
+	assert.Equal(t, "kafka-cluster:Connect", params.Get("Action"))
+	assert.NotEmpty(t, params.Get("X-Amz-Signature"))
+}
+
+func TestGenerateAuthTokenWithInvalidClusterARN(t *testing.T) {
+	for name, clusterARN := range map[string]string{
+		"not an arn":     "demo-cluster",
+		"not a cluster":  "arn:aws:kafka:us-west-2:123456789012:topic/demo/abc/orders",
+		"not kafka":      "arn:aws:s3:us-west-2:123456789012:cluster/demo/abc",
+		"another region": "arn:aws:kafka:us-east-1:123456789012:cluster/demo/abc",
+	} {
+		t.Run(name, func(t *testing.T) {
+			token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+				WithClusterARN(clusterARN))
+
+			assert.Error(t, err)
+			assert.Empty(t, token)
+		})
+	}
+}
+
+func TestGenerateAuthTokenWithClusterARNAndResourceArnQueryParameter(t *testing.T) {
+	for _, key := range []string{ClusterARNQueryKey, "resourcearn"} {
+		t.Run(key, func(t *testing.T) {
+			token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+				WithClusterARN(TestClusterARN), WithSignedQueryParameter(key, TestClusterARN))
+
+			assert.Error(t, err)
+			assert.Empty(t, token)
+		})
+	}
+}
+
+func TestGenerateAuthTokenWithSignedQueryParameters(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithSignedQueryParameter("Audience", "orders"), WithSignedQueryParameter("Audience", "payments"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders", "payments"}, decodeTokenParams(t, token)["Audience"])
+}
+
+func TestGenerateAuthTokenWithReservedQueryParameters(t *testing.T) {
+	for _, key := range []string{"Action", "action", "X-Amz-Expires", "x-amz-signature", "User-Agent"} {
+		t.Run(key, func(t *testing.T) {
+			_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+				WithSignedQueryParameter(key, "override"))
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGenerateAuthTokenFromConfigSourceWithClusterARN(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-CONFIG-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-CONFIG-SECRET-KEY")
+
+	token, _, err := GenerateAuthTokenFromConfigSource(Ctx,
+		staticConfigSource{config: SignerConfig{Region: TestRegion, ClusterARN: TestClusterARN}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, TestClusterARN, decodeTokenParams(t, token).Get(ClusterARNQueryKey))
+}
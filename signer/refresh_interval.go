@@ -0,0 +1,42 @@
+package signer
+
+import (
+	"fmt"
+	"time"
+)
+
+// RefreshLoopError is returned by a Provider when a new token expires before the minimum refresh interval has elapsed,
+// so that honoring the interval would hand out expired tokens and ignoring it would refresh in a tight loop. It points
+// at a pathological configuration such as a tiny max expiry.
+type RefreshLoopError struct {
+	// Lifetime is the lifetime of the new token.
+	Lifetime time.Duration
+
+	// MinRefreshInterval is the configured minimum interval between refreshes.
+	MinRefreshInterval time.Duration
+}
+
+// Error returns the token lifetime and the minimum refresh interval it falls short of.
+func (e *RefreshLoopError) Error() string {
+	return fmt.Sprintf("token lifetime %s is shorter than the minimum refresh interval %s, refreshing would loop",
+		e.Lifetime, e.MinRefreshInterval)
+}
+
+// Delays the refresh time of the token issued at issuedAt until the minimum refresh interval has elapsed, failing
+// with a RefreshLoopError when the token expires before then.
+func enforceMinRefreshInterval(issuedAt time.Time, refreshAt time.Time, token *Token, minInterval time.Duration) (
+	time.Time, error,
+) {
+	if minInterval <= 0 {
+		return refreshAt, nil
+	}
+
+	if lifetime := token.Expiry(0).Sub(issuedAt); lifetime < minInterval {
+		return time.Time{}, &RefreshLoopError{Lifetime: lifetime, MinRefreshInterval: minInterval}
+	}
+
+	if earliest := issuedAt.Add(minInterval); refreshAt.Before(earliest) {
+		return earliest, nil
+	}
+	return refreshAt, nil
+}
@@ -0,0 +1,53 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnforceMinRefreshInterval(t *testing.T) {
+	issuedAt := time.Now()
+	token := &Token{ExpirationTimeMs: issuedAt.Add(15 * time.Minute).UnixMilli()}
+
+	refreshAt, err := enforceMinRefreshInterval(issuedAt, issuedAt, token, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, issuedAt.Add(time.Minute), refreshAt)
+
+	refreshAt, err = enforceMinRefreshInterval(issuedAt, issuedAt.Add(12*time.Minute), token, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, issuedAt.Add(12*time.Minute), refreshAt)
+
+	refreshAt, err = enforceMinRefreshInterval(issuedAt, issuedAt, token, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, issuedAt, refreshAt)
+}
+
+func TestProviderWithMinRefreshInterval(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider(WithRefreshStrategy(alwaysRefreshStrategy{}),
+		WithMinRefreshInterval(time.Minute))
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	second, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, credentialsProvider.calls)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), provider.refreshAt, 5*time.Second)
+}
+
+func TestProviderRefreshLoopError(t *testing.T) {
+	provider, _ := newCountingProvider(WithMaxExpiry(30*time.Second, MaxExpiryClamp),
+		WithMinRefreshInterval(time.Minute))
+
+	_, err := provider.Token(Ctx)
+
+	var loopErr *RefreshLoopError
+	assert.True(t, errors.As(err, &loopErr))
+	assert.Equal(t, time.Minute, loopErr.MinRefreshInterval)
+	assert.LessOrEqual(t, loopErr.Lifetime, 30*time.Second)
+	assert.Equal(t, time.Duration(0), provider.CurrentTokenTTL())
+}
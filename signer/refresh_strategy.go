@@ -0,0 +1,42 @@
+package signer
+
+import "time"
+
+// DefaultRefreshFraction is the fraction of a token's lifetime after which a Provider replaces it by default.
+const DefaultRefreshFraction = 0.8
+
+// RefreshStrategy decides when a Provider replaces its cached token. Strategies can adapt to the generation latency
+// reported on the token.
+type RefreshStrategy interface {
+	// RefreshAt returns the time after which the token, generated starting at issuedAt, is replaced.
+	RefreshAt(issuedAt time.Time, token *Token) time.Time
+}
+
+// FractionRefreshStrategy replaces tokens once a fraction of their lifetime has elapsed.
+type FractionRefreshStrategy struct {
+	// Fraction of the token lifetime after which it is replaced, between 0 and 1. DefaultRefreshFraction is used
+	// when out of range.
+	Fraction float64
+}
+
+// RefreshAt returns the time at which the fraction of the token lifetime has elapsed.
+func (s FractionRefreshStrategy) RefreshAt(issuedAt time.Time, token *Token) time.Time {
+	fraction := s.Fraction
+	if fraction <= 0 || fraction > 1 {
+		fraction = DefaultRefreshFraction
+	}
+
+	lifetime := token.Expiry(0).Sub(issuedAt)
+	return issuedAt.Add(time.Duration(float64(lifetime) * fraction))
+}
+
+// LeadTimeRefreshStrategy replaces tokens a fixed lead time before they expire.
+type LeadTimeRefreshStrategy struct {
+	// LeadTime before expiry at which the token is replaced.
+	LeadTime time.Duration
+}
+
+// RefreshAt returns the time the lead time before the token expires.
+func (s LeadTimeRefreshStrategy) RefreshAt(issuedAt time.Time, token *Token) time.Time {
+	return token.Expiry(s.LeadTime)
+}
@@ -0,0 +1,58 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFractionRefreshStrategy(t *testing.T) {
+	issuedAt := time.Now()
+	token := &Token{ExpirationTimeMs: issuedAt.Add(10 * time.Minute).UnixMilli()}
+
+	refreshAt := FractionRefreshStrategy{Fraction: 0.5}.RefreshAt(issuedAt, token)
+	assert.WithinDuration(t, issuedAt.Add(5*time.Minute), refreshAt, time.Millisecond)
+
+	for _, fraction := range []float64{0, -1, 1.5} {
+		refreshAt = FractionRefreshStrategy{Fraction: fraction}.RefreshAt(issuedAt, token)
+		assert.WithinDuration(t, issuedAt.Add(8*time.Minute), refreshAt, time.Millisecond)
+	}
+}
+
+func TestLeadTimeRefreshStrategy(t *testing.T) {
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(15 * time.Minute)
+	token := &Token{ExpirationTimeMs: expiresAt.UnixMilli()}
+
+	refreshAt := LeadTimeRefreshStrategy{LeadTime: 2 * time.Minute}.RefreshAt(issuedAt, token)
+
+	assert.WithinDuration(t, expiresAt.Add(-2*time.Minute), refreshAt, time.Millisecond)
+}
+
+// Refreshes tokens as soon as they are issued.
+type alwaysRefreshStrategy struct{}
+
+func (alwaysRefreshStrategy) RefreshAt(issuedAt time.Time, token *Token) time.Time {
+	return issuedAt
+}
+
+func TestProviderWithRefreshStrategy(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider(WithRefreshStrategy(alwaysRefreshStrategy{}))
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, credentialsProvider.calls)
+}
+
+func TestProviderWithLeadTimeRefreshStrategy(t *testing.T) {
+	provider, _ := newCountingProvider(WithRefreshStrategy(LeadTimeRefreshStrategy{LeadTime: time.Minute}))
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	assert.WithinDuration(t, time.Now().Add(14*time.Minute), provider.refreshAt, 5*time.Second)
+}
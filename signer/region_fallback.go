@@ -0,0 +1,92 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+)
+
+// ErrRegionRequired is returned when token generation is called with an empty region and the region fallback policy
+// does not resolve one.
+var ErrRegionRequired = errors.New("region is required")
+
+// RegionFallback decides how the region is resolved when token generation is called with an empty one.
+type RegionFallback int
+
+const (
+	// RegionFallbackError fails with ErrRegionRequired. This is the default.
+	RegionFallbackError RegionFallback = iota
+
+	// RegionFallbackEnv uses the AWS_REGION environment variable, or AWS_DEFAULT_REGION when unset.
+	RegionFallbackEnv
+
+	// RegionFallbackIMDS uses the region of the EC2 instance, read from the instance metadata service.
+	RegionFallbackIMDS
+)
+
+// String returns the name of the policy.
+func (f RegionFallback) String() string {
+	switch f {
+	case RegionFallbackError:
+		return "error"
+	case RegionFallbackEnv:
+		return "env"
+	case RegionFallbackIMDS:
+		return "imds"
+	default:
+		return fmt.Sprintf("RegionFallback(%d)", int(f))
+	}
+}
+
+// Region read from the instance metadata service, cached once read successfully as it cannot change.
+var (
+	imdsRegionMu sync.Mutex
+	imdsRegion   string
+)
+
+// Returns the region, resolving it with the fallback policy of the options when empty.
+func resolveRegion(ctx context.Context, region string, options Options) (string, error) {
+	if region != "" {
+		return region, nil
+	}
+
+	switch options.RegionFallback {
+	case RegionFallbackEnv:
+		for _, envVar := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+			if region := os.Getenv(envVar); region != "" {
+				return region, nil
+			}
+		}
+		return "", fmt.Errorf("%w: AWS_REGION and AWS_DEFAULT_REGION are not set", ErrRegionRequired)
+	case RegionFallbackIMDS:
+		region, err := regionFromIMDS(ctx, options)
+		if err != nil {
+			return "", fmt.Errorf("%w: failed to read the region from the instance metadata service: %w",
+				ErrRegionRequired, err)
+		}
+		return region, nil
+	default:
+		return "", fmt.Errorf("%w: pass one or set a region fallback with WithRegionFallback", ErrRegionRequired)
+	}
+}
+
+// Returns the region of the EC2 instance from the instance metadata service.
+func regionFromIMDS(ctx context.Context, options Options) (string, error) {
+	imdsRegionMu.Lock()
+	defer imdsRegionMu.Unlock()
+
+	if imdsRegion != "" {
+		return imdsRegion, nil
+	}
+
+	output, err := newEC2IMDSClient(options).GetRegion(ctx, &imds.GetRegionInput{})
+	if err != nil {
+		return "", err
+	}
+	imdsRegion = output.Region
+	return imdsRegion, nil
+}
@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAuthTokenWithEmptyRegionFails(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, "", testQueryCredentialsProvider)
+
+	assert.True(t, errors.Is(err, ErrRegionRequired))
+}
+
+func TestGenerateAuthTokenWithEnvRegionFallback(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "eu-west-1")
+
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, "", testQueryCredentialsProvider,
+		WithRegionFallback(RegionFallbackEnv))
+
+	assert.NoError(t, err)
+	assert.Contains(t, decodeTokenParams(t, token).Get("X-Amz-Credential"), "/eu-west-1/")
+}
+
+func TestEnvRegionFallbackWithoutRegion(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	_, err := resolveRegion(Ctx, "", Options{RegionFallback: RegionFallbackEnv})
+
+	assert.True(t, errors.Is(err, ErrRegionRequired))
+}
+
+// Serves the region of the instance from a fake instance metadata service.
+func withIMDSRegion(t *testing.T, region string) *int {
+	var regionRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/api/token":
+			w.Header().Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "21600")
+			_, _ = w.Write([]byte("imds-session-token"))
+		case "/latest/dynamic/instance-identity/document":
+			regionRequests++
+			_, _ = w.Write([]byte(`{"region": "` + region + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL)
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+
+	imdsRegion = ""
+	t.Cleanup(func() { imdsRegion = "" })
+	return &regionRequests
+}
+
+func TestGenerateAuthTokenWithIMDSRegionFallback(t *testing.T) {
+	regionRequests := withIMDSRegion(t, "ap-southeast-2")
+
+	for i := 0; i < 2; i++ {
+		token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, "", testQueryCredentialsProvider,
+			WithRegionFallback(RegionFallbackIMDS))
+
+		assert.NoError(t, err)
+		assert.Contains(t, decodeTokenParams(t, token).Get("X-Amz-Credential"), "/ap-southeast-2/")
+	}
+	assert.Equal(t, 1, *regionRequests)
+}
+
+func TestRegionFallbackDoesNotApplyToExplicitRegion(t *testing.T) {
+	region, err := resolveRegion(Ctx, TestRegion, Options{RegionFallback: RegionFallbackIMDS})
+
+	assert.NoError(t, err)
+	assert.Equal(t, TestRegion, region)
+}
@@ -0,0 +1,118 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/smithy-go"
+)
+
+// ErrUnknownRegion is returned in strict region validation mode when the region is not one MSK is available in.
+var ErrUnknownRegion = errors.New("unknown msk region")
+
+//go:generate go run ./internal/mskregionsgen -output regions_gen.go
+
+// Regions that are disabled by default and must be enabled for the account. Session tokens issued by the global sts
+// endpoint are not valid in them unless the account opts into version 2 global endpoint tokens.
+var optInRegions = map[string]struct{}{
+	"af-south-1":     {},
+	"ap-east-1":      {},
+	"ap-south-2":     {},
+	"ap-southeast-3": {},
+	"ap-southeast-4": {},
+	"ap-southeast-5": {},
+	"ap-southeast-7": {},
+	"ca-west-1":      {},
+	"eu-central-2":   {},
+	"eu-south-1":     {},
+	"eu-south-2":     {},
+	"il-central-1":   {},
+	"me-central-1":   {},
+	"me-south-1":     {},
+	"mx-central-1":   {},
+}
+
+// Error codes returned by aws services for session tokens that are not valid in the region.
+var invalidTokenErrorCodes = map[string]struct{}{
+	"InvalidClientTokenId":        {},
+	"UnrecognizedClientException": {},
+	"AuthFailure":                 {},
+}
+
+// IsOptInRegion reports whether the region is disabled by default and must be enabled for the account. The sts
+// clients the signer creates always use the regional endpoint of the signing region, as opt-in regions require.
+func IsOptInRegion(region string) bool {
+	_, ok := optInRegions[region]
+	return ok
+}
+
+// Explains credential errors caused by session tokens that are not valid in an opt-in region, which otherwise surface
+// as a bare invalid token error.
+func annotateOptInRegionError(region string, err error) error {
+	var apiErr smithy.APIError
+	if !IsOptInRegion(region) || !errors.As(err, &apiErr) {
+		return err
+	}
+	if _, ok := invalidTokenErrorCodes[apiErr.ErrorCode()]; !ok {
+		return err
+	}
+
+	return fmt.Errorf("%w (%s is an opt-in region: enable it for the account and use session tokens from its "+
+		"regional sts endpoint, or version 2 tokens from the global endpoint)", err, region)
+}
+
+// Validates the region against the MSK regions and the extra regions when strict region validation is enabled,
+// suggesting the closest known region for typos.
+func validateRegion(region string, options Options) error {
+	if !options.StrictRegionValidation {
+		return nil
+	}
+
+	if _, ok := mskRegions[region]; ok {
+		return nil
+	}
+	for _, extra := range options.ExtraRegions {
+		if region == extra {
+			return nil
+		}
+	}
+
+	if suggestion := closestRegion(region); suggestion != "" {
+		return fmt.Errorf("%w %q, did you mean %q", ErrUnknownRegion, region, suggestion)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownRegion, region)
+}
+
+// Returns the MSK region within two edits of the region, or an empty string when there is none.
+func closestRegion(region string) string {
+	closest, closestDistance := "", 3
+	for known := range mskRegions {
+		if distance := editDistance(region, known); distance < closestDistance ||
+			(distance == closestDistance && known < closest) {
+			closest, closestDistance = known, distance
+		}
+	}
+	return closest
+}
+
+// Returns the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
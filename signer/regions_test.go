@@ -0,0 +1,106 @@
+package signer
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictRegionValidationRejectsTypo(t *testing.T) {
+	provider := &sequenceCredentialsProvider{credentials: []aws.Credentials{{
+		AccessKeyID:     "TEST-REGION-ACCESS-KEY",
+		SecretAccessKey: "TEST-REGION-SECRET-KEY",
+	}}}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, "us-este-1", provider, WithStrictRegionValidation())
+
+	assert.ErrorIs(t, err, ErrUnknownRegion)
+	assert.ErrorContains(t, err, `did you mean "us-east-1"`)
+	assert.Equal(t, 0, provider.calls)
+}
+
+func TestStrictRegionValidationAcceptsKnownAndExtraRegions(t *testing.T) {
+	mockCreds := aws.Credentials{AccessKeyID: "TEST-REGION-ACCESS-KEY", SecretAccessKey: "TEST-REGION-SECRET-KEY"}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion,
+		MockCredentialsProvider{credentials: mockCreds}, WithStrictRegionValidation())
+	assert.NoError(t, err)
+
+	_, _, err = GenerateAuthTokenFromCredentialsProvider(Ctx, "xx-test-1",
+		MockCredentialsProvider{credentials: mockCreds}, WithStrictRegionValidation("xx-test-1"))
+	assert.NoError(t, err)
+}
+
+func TestRegionValidationDisabledByDefault(t *testing.T) {
+	assert.NoError(t, validateRegion("us-este-1", resolveOptions(nil)))
+}
+
+func TestValidateRegionWithoutSuggestion(t *testing.T) {
+	err := validateRegion("mars-1", resolveOptions([]Option{WithStrictRegionValidation()}))
+
+	assert.ErrorIs(t, err, ErrUnknownRegion)
+	assert.NotContains(t, err.Error(), "did you mean")
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("us-east-1", "us-east-1"))
+	assert.Equal(t, 2, editDistance("us-este-1", "us-east-1"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
+
+func TestIsOptInRegion(t *testing.T) {
+	assert.True(t, IsOptInRegion("ap-southeast-5"))
+	assert.True(t, IsOptInRegion("me-south-1"))
+	assert.False(t, IsOptInRegion("us-east-1"))
+	assert.False(t, IsOptInRegion("cn-north-1"))
+
+	for region := range optInRegions {
+		assert.Contains(t, mskRegions, region)
+	}
+}
+
+func TestOptInRegionSTSEndpoint(t *testing.T) {
+	client := newSTSClient(aws.Config{Region: "ap-southeast-5"}, resolveOptions(nil))
+
+	endpoint, err := sts.NewDefaultEndpointResolverV2().ResolveEndpoint(Ctx, sts.EndpointParameters{
+		Region:            aws.String(client.Options().Region),
+		UseGlobalEndpoint: aws.Bool(false),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sts.ap-southeast-5.amazonaws.com", endpoint.URI.Host)
+}
+
+func TestOptInRegionTokenHost(t *testing.T) {
+	mockCreds := aws.Credentials{AccessKeyID: "TEST-REGION-ACCESS-KEY", SecretAccessKey: "TEST-REGION-SECRET-KEY"}
+
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, "ap-southeast-5",
+		MockCredentialsProvider{credentials: mockCreds}, WithStrictRegionValidation())
+
+	assert.NoError(t, err)
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(decoded), "https://kafka.ap-southeast-5.amazonaws.com/?"))
+	assert.Contains(t, decodeTokenParams(t, token).Get("X-Amz-Credential"), "/ap-southeast-5/kafka-cluster/")
+}
+
+func TestOptInRegionInvalidTokenError(t *testing.T) {
+	invalidToken := &smithy.GenericAPIError{Code: "InvalidClientTokenId", Message: "The security token is invalid"}
+	provider := aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{}, invalidToken
+	})
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, "ap-southeast-5", provider)
+	assert.ErrorIs(t, err, invalidToken)
+	assert.ErrorContains(t, err, "ap-southeast-5 is an opt-in region")
+
+	_, _, err = GenerateAuthTokenFromCredentialsProvider(Ctx, "us-east-1", provider)
+	assert.ErrorIs(t, err, invalidToken)
+	assert.NotContains(t, err.Error(), "opt-in region")
+}
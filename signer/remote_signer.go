@@ -0,0 +1,182 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	RemoteSignerSource      = "RemoteSigner" // RemoteSignerSource is the source of tokens signed by a RemoteSigner.
+	maxRemoteSignerResponse = 1 << 20        // Upper bound of the remote signer response read into memory.
+)
+
+// RemoteSigner delegates signing the auth token to a remote service holding the credentials, so the host running the
+// Kafka client never sees secret keys.
+//
+// The signer POSTs a JSON document with the region and the unsigned url of the kafka-cluster:Connect request to the
+// endpoint:
+//
+//	{"region": "us-west-2", "url": "https://kafka.us-west-2.amazonaws.com/?Action=kafka-cluster%3AConnect&..."}
+//
+// The service presigns the url with SigV4 for the kafka-cluster service and answers with status 200 and
+//
+//	{"signedUrl": "https://kafka.us-west-2.amazonaws.com/?Action=...&X-Amz-Signature=...", "principal": "..."}
+//
+// where the optional principal is logged in place of the access key id.
+type RemoteSigner struct {
+	// Endpoint is the url of the remote signing service.
+	Endpoint string
+
+	// HTTPClient sends the signing requests, e.g. with mutual TLS configured. http.DefaultClient is used when nil.
+	HTTPClient *http.Client
+}
+
+type remoteSignRequest struct {
+	Region string `json:"region"`
+	URL    string `json:"url"`
+}
+
+type remoteSignResponse struct {
+	SignedURL string `json:"signedUrl"`
+	Principal string `json:"principal,omitempty"`
+}
+
+// GenerateAuthTokenFromRemoteSigner generates base64 encoded signed url as auth token by having the remote signer sign
+// it. No credentials are loaded locally.
+func GenerateAuthTokenFromRemoteSigner(
+	ctx context.Context, region string, remote *RemoteSigner, optFns ...Option,
+) (string, int64, error) {
+	options := withContextLogger(ctx, resolveOptions(optFns))
+	region, err := resolveRegion(ctx, region, options)
+	return unpackToken(reportTokenGeneration(ctx, region, options, func() (*Token, string, error) {
+		if err != nil {
+			return nil, "", err
+		}
+		return mintRemoteAuthToken(ctx, region, options, remote)
+	}))
+}
+
+// Builds the auth token request and has the remote signer sign it, returning the principal reported by the signer.
+func mintRemoteAuthToken(
+	ctx context.Context, region string, options Options, remote *RemoteSigner,
+) (*Token, string, error) {
+	if err := validateRegion(region, options); err != nil {
+		return nil, "", err
+	}
+
+	signStart := time.Now()
+	spanCtx, endSpan := startSpan(ctx, options.Tracer, SpanSignToken)
+	var value, principal string
+	var expirationTimeMs int64
+	err := injectFault(spanCtx, options, FaultStepSignToken)
+	if err == nil {
+		value, expirationTimeMs, principal, err = constructRemoteAuthToken(spanCtx, region, options, remote)
+	}
+	endSpan(err)
+	signDuration := time.Since(signStart)
+	if err != nil {
+		return nil, "", newTokenGenerationError(region,
+			GenerationStep{Name: SpanSignToken, Duration: signDuration, Err: err})
+	}
+
+	token := &Token{
+		Value:            value,
+		ExpirationTimeMs: expirationTimeMs,
+		SignDuration:     signDuration,
+		Region:           region,
+		Source:           RemoteSignerSource,
+	}
+	return token, principal, nil
+}
+
+// Constructs the auth token from the url signed by the remote signer.
+func constructRemoteAuthToken(
+	ctx context.Context, region string, options Options, remote *RemoteSigner,
+) (string, int64, string, error) {
+	if remote == nil || remote.Endpoint == "" {
+		return "", 0, "", errors.New("remote signer endpoint cannot be empty")
+	}
+
+	params, err := extraQueryParameters(region, options)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("invalid query parameters: %w", err)
+	}
+
+	endpointURL, err := tokenEndpoint(region, options)
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	expirySeconds, err := tokenExpirySeconds(options)
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	profile, err := resolveCompatibilityProfile(options)
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	req, err := buildRequest(expirySeconds, endpointURL, params, profile)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("failed to build request for signing: %w", err)
+	}
+
+	signed, err := remote.sign(ctx, remoteSignRequest{Region: region, URL: req.URL.String()})
+	if err != nil {
+		return "", 0, "", fmt.Errorf("failed to sign request with remote signer: %w", err)
+	}
+	logSignedURL(ctx, options, signed.SignedURL)
+
+	value, expirationTimeMs, err := encodeAuthToken(signed.SignedURL, profile, options)
+	return value, expirationTimeMs, signed.Principal, err
+}
+
+// Sends the signing request to the remote signing service.
+func (r *RemoteSigner) sign(ctx context.Context, signRequest remoteSignRequest) (*remoteSignResponse, error) {
+	body, err := json.Marshal(signRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteSignerResponse))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer responded with status %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+
+	var signed remoteSignResponse
+	if err := json.Unmarshal(respBody, &signed); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if signed.SignedURL == "" {
+		return nil, errors.New("remote signer response has no signed url")
+	}
+
+	return &signed, nil
+}
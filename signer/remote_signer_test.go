@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Starts a remote signing service presigning urls with the test credentials.
+func newRemoteSignerServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var signRequest remoteSignRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&signRequest))
+
+		req, err := http.NewRequest(http.MethodGet, signRequest.URL, nil)
+		assert.NoError(t, err)
+		signedURL, err := SigV4Signer{}.PresignRequest(r.Context(), req, signRequest.Region, aws.Credentials{
+			AccessKeyID:     "TEST-REMOTE-ACCESS-KEY",
+			SecretAccessKey: "TEST-REMOTE-SECRET-KEY",
+		}, time.Now().UTC())
+		assert.NoError(t, err)
+
+		assert.NoError(t, json.NewEncoder(w).Encode(remoteSignResponse{SignedURL: signedURL, Principal: "TestRole"}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGenerateAuthTokenFromRemoteSigner(t *testing.T) {
+	server := newRemoteSignerServer(t)
+
+	token, expiryMs, err := GenerateAuthTokenFromRemoteSigner(Ctx, TestRegion, &RemoteSigner{Endpoint: server.URL},
+		WithClusterARN("arn:aws:kafka:us-west-2:123456789012:cluster/test/abc"))
+
+	assert.NoError(t, err)
+	assert.Greater(t, expiryMs, time.Now().UnixMilli())
+
+	params := decodeTokenParams(t, token)
+	assert.Equal(t, ActionName, params.Get(ActionType))
+	assert.Equal(t, "arn:aws:kafka:us-west-2:123456789012:cluster/test/abc", params.Get(ClusterARNQueryKey))
+	assert.Contains(t, params.Get("X-Amz-Credential"), "TEST-REMOTE-ACCESS-KEY")
+	assert.NotEmpty(t, params.Get(UserAgentKey))
+}
+
+func TestGenerateAuthTokenFromRemoteSignerErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "caller not allowed")
+	}))
+	t.Cleanup(server.Close)
+
+	_, _, err := GenerateAuthTokenFromRemoteSigner(Ctx, TestRegion, &RemoteSigner{Endpoint: server.URL})
+
+	assert.ErrorContains(t, err, "403 Forbidden: caller not allowed")
+}
+
+func TestGenerateAuthTokenFromRemoteSignerWithoutEndpoint(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromRemoteSigner(Ctx, TestRegion, &RemoteSigner{})
+
+	assert.ErrorContains(t, err, "remote signer endpoint cannot be empty")
+}
+
+func TestRemoteSignRequestURLIsUnsigned(t *testing.T) {
+	var received remoteSignRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	_, _, _ = GenerateAuthTokenFromRemoteSigner(Ctx, TestRegion, &RemoteSigner{Endpoint: server.URL})
+
+	u, err := url.Parse(received.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, TestRegion, received.Region)
+	assert.Equal(t, ActionName, u.Query().Get(ActionType))
+	assert.Empty(t, u.Query().Get("X-Amz-Signature"))
+}
@@ -0,0 +1,36 @@
+package signer
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// RequestSigner presigns the kafka-cluster:Connect request the auth token is made of. SigV4Signer is used by default,
+// other signature algorithms can be plugged in with WithRequestSigner. The presigned url must carry the X-Amz-Date
+// and X-Amz-Expires query parameters the token expiration is derived from.
+type RequestSigner interface {
+	PresignRequest(
+		ctx context.Context, req *http.Request, region string, credentials aws.Credentials, signingTime time.Time,
+	) (string, error)
+}
+
+// SigV4Signer presigns requests with AWS Signature Version 4 for the SigningName service.
+type SigV4Signer struct{}
+
+// PresignRequest presigns the request with SigV4, returning the presigned url.
+func (SigV4Signer) PresignRequest(
+	ctx context.Context, req *http.Request, region string, credentials aws.Credentials, signingTime time.Time,
+) (string, error) {
+	signedURL, _, err := v4.NewSigner().PresignHTTP(ctx, credentials, req,
+		calculateSHA256Hash(""),
+		SigningName,
+		region,
+		signingTime,
+	)
+
+	return signedURL, err
+}
@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Delegates to SigV4Signer, recording the region and signing time it was called with.
+type recordingRequestSigner struct {
+	region      string
+	signingTime time.Time
+}
+
+func (r *recordingRequestSigner) PresignRequest(
+	ctx context.Context, req *http.Request, region string, credentials aws.Credentials, signingTime time.Time,
+) (string, error) {
+	r.region = region
+	r.signingTime = signingTime
+	return SigV4Signer{}.PresignRequest(ctx, req, region, credentials, signingTime)
+}
+
+// Fails every presign.
+type failingRequestSigner struct{}
+
+func (failingRequestSigner) PresignRequest(
+	ctx context.Context, req *http.Request, region string, credentials aws.Credentials, signingTime time.Time,
+) (string, error) {
+	return "", errors.New("algorithm not supported")
+}
+
+var requestSignerTestCredentials = aws.Credentials{
+	AccessKeyID:     "TEST-SIGNER-ACCESS-KEY",
+	SecretAccessKey: "TEST-SIGNER-SECRET-KEY",
+}
+
+func TestWithRequestSigner(t *testing.T) {
+	signer := &recordingRequestSigner{}
+
+	token, expiryMs, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion,
+		MockCredentialsProvider{credentials: requestSignerTestCredentials}, WithRequestSigner(signer))
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, TestRegion, signer.region)
+	assert.WithinDuration(t, time.Now(), signer.signingTime, 5*time.Second)
+	assert.Equal(t, signer.signingTime.Add(DefaultExpirySeconds*time.Second).Unix(), expiryMs/1000)
+}
+
+func TestWithFailingRequestSigner(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion,
+		MockCredentialsProvider{credentials: requestSignerTestCredentials}, WithRequestSigner(failingRequestSigner{}))
+
+	assert.ErrorContains(t, err, "failed to sign request: algorithm not supported")
+}
@@ -0,0 +1,102 @@
+package signer
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// Scrubber masks the secrets of a presigned url before it is logged.
+type Scrubber interface {
+	// Scrub returns the url with its secrets masked.
+	Scrub(signedURL string) string
+}
+
+// ScrubberFunc adapts a function to the Scrubber interface.
+type ScrubberFunc func(signedURL string) string
+
+// Scrub calls f.
+func (f ScrubberFunc) Scrub(signedURL string) string {
+	return f(signedURL)
+}
+
+// DefaultScrubberMask replaces the masked query parameter values.
+const DefaultScrubberMask = "REDACTED"
+
+// DefaultScrubber masks the signature and the session token of a presigned url, and shortens the access key id in its
+// credential scope to its first and last four characters. Urls that cannot be parsed are masked entirely.
+type DefaultScrubber struct {
+	// Mask replaces the masked values. DefaultScrubberMask is used when empty.
+	Mask string
+
+	// ExtraKeys are additional query parameters whose values are masked, compared case-insensitively.
+	ExtraKeys []string
+}
+
+// Scrub returns the url with its secrets masked.
+func (s DefaultScrubber) Scrub(signedURL string) string {
+	mask := s.Mask
+	if mask == "" {
+		mask = DefaultScrubberMask
+	}
+
+	parsedURL, err := url.Parse(signedURL)
+	if err != nil {
+		return mask
+	}
+
+	query := parsedURL.Query()
+	for key := range query {
+		switch {
+		case QueryKeySignature.Matches(key), QueryKeySecurityToken.Matches(key):
+			query[key] = []string{mask}
+		case QueryKeyCredential.Matches(key):
+			for i, credential := range query[key] {
+				keyID, scope, _ := strings.Cut(credential, "/")
+				query[key][i] = shortenKeyID(keyID) + "/" + scope
+			}
+		case s.isExtraKey(key):
+			query[key] = []string{mask}
+		}
+	}
+
+	parsedURL.RawQuery = canonicalQueryString(query)
+	return parsedURL.String()
+}
+
+// Reports whether the query parameter is one of the extra keys to mask.
+func (s DefaultScrubber) isExtraKey(key string) bool {
+	for _, extra := range s.ExtraKeys {
+		if strings.EqualFold(key, extra) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogKeyURL is the log field holding the scrubbed presigned url of a token.
+const LogKeyURL = "url"
+
+// EventTokenSigned is logged at debug level with the scrubbed presigned url when signed url logging is enabled.
+const EventTokenSigned = "token_signed"
+
+// Logs the presigned url, scrubbed of its secrets, at debug level when signed url logging is enabled.
+func logSignedURL(ctx context.Context, options Options, signedURL string) {
+	if options.Logger == nil || !options.LogSignedURLs {
+		return
+	}
+	if !options.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	var scrubber Scrubber = DefaultScrubber{}
+	if options.URLScrubber != nil {
+		scrubber = options.URLScrubber
+	}
+
+	options.Logger.LogAttrs(ctx, slog.LevelDebug, "signed msk auth token url",
+		slog.String(LogKeyEvent, EventTokenSigned),
+		slog.String(LogKeyURL, scrubber.Scrub(signedURL)),
+	)
+}
@@ -0,0 +1,76 @@
+package signer
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultScrubber(t *testing.T) {
+	signedURL := "https://kafka.us-west-2.amazonaws.com/?Action=kafka-cluster%3AConnect" +
+		"&X-Amz-Credential=ASIAEXAMPLEKEY1234%2F20240101%2Fus-west-2%2Fkafka-cluster%2Faws4_request" +
+		"&X-Amz-Security-Token=session-secret&X-Amz-Signature=deadbeef&custom=secret-value"
+
+	scrubbed := DefaultScrubber{ExtraKeys: []string{"CUSTOM"}}.Scrub(signedURL)
+
+	assert.NotContains(t, scrubbed, "deadbeef")
+	assert.NotContains(t, scrubbed, "session-secret")
+	assert.NotContains(t, scrubbed, "secret-value")
+	assert.NotContains(t, scrubbed, "ASIAEXAMPLEKEY1234")
+	assert.Contains(t, scrubbed, "X-Amz-Credential=ASIA...1234%2F20240101%2Fus-west-2")
+	assert.Contains(t, scrubbed, "X-Amz-Signature=REDACTED")
+	assert.Contains(t, scrubbed, "Action=kafka-cluster%3AConnect")
+}
+
+func TestDefaultScrubberMasksUnparsableURLs(t *testing.T) {
+	assert.Equal(t, "***", DefaultScrubber{Mask: "***"}.Scrub("%zz://\x00"))
+}
+
+// Returns a debug level JSON logger writing to the buffer.
+func newDebugLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestGenerateAuthTokenLogsScrubbedURL(t *testing.T) {
+	var buf bytes.Buffer
+	credentialsProvider := MockCredentialsProvider{credentials: aws.Credentials{
+		AccessKeyID:     "ASIAEXAMPLEKEY1234",
+		SecretAccessKey: "TEST-SECRET-KEY",
+		SessionToken:    "TEST-SESSION-TOKEN",
+	}}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, credentialsProvider,
+		WithLogger(newDebugLogger(&buf)), WithSignedURLLogging(nil))
+
+	assert.NoError(t, err)
+	urlLine, _, _ := strings.Cut(buf.String(), "\n")
+	assert.Contains(t, urlLine, EventTokenSigned)
+	assert.NotContains(t, urlLine, "TEST-SESSION-TOKEN")
+	assert.NotContains(t, urlLine, "ASIAEXAMPLEKEY1234")
+}
+
+func TestGenerateAuthTokenLogsWithCustomScrubber(t *testing.T) {
+	var buf bytes.Buffer
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithLogger(newDebugLogger(&buf)), WithSignedURLLogging(ScrubberFunc(func(string) string {
+			return "custom-scrubbed"
+		})))
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `"url":"custom-scrubbed"`)
+}
+
+func TestGenerateAuthTokenDoesNotLogURLsByDefault(t *testing.T) {
+	var buf bytes.Buffer
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithLogger(newDebugLogger(&buf)))
+
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(buf.String(), EventTokenSigned))
+}
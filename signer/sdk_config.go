@@ -0,0 +1,88 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/endpointcreds"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ErrSharedConfigDisabled is returned when credentials are requested from a named profile while shared config
+// loading is disabled.
+var ErrSharedConfigDisabled = errors.New("shared config files are disabled, named profiles cannot be loaded")
+
+// Builds the SDK config of clients created without loading the shared config files, for the region or the region of
+// the environment when empty. The client settings the SDK config loader reads from the environment are applied as it
+// would: the app id, the account id endpoint mode, and the endpoint urls of AWS_ENDPOINT_URL and
+// AWS_ENDPOINT_URL_<SERVICE> unless AWS_IGNORE_CONFIGURED_ENDPOINT_URLS is set. The sts throttle monitor and the
+// dialer and API options of the options are applied as well.
+func newEnvSDKConfig(region string, envConfig config.EnvConfig, options Options) aws.Config {
+	if region == "" {
+		region = envConfig.Region
+	}
+
+	cfg := aws.Config{
+		Region:                region,
+		APIOptions:            sdkAPIOptions(options),
+		AppID:                 envConfig.AppID,
+		AccountIDEndpointMode: envConfig.AccountIDEndpointMode,
+		ConfigSources:         []interface{}{envConfig},
+	}
+	if cfg.AccountIDEndpointMode == "" {
+		cfg.AccountIDEndpointMode = aws.AccountIDEndpointModePreferred
+	}
+	if envConfig.BaseEndpoint != "" && (envConfig.IgnoreConfiguredEndpoints == nil || !*envConfig.IgnoreConfiguredEndpoints) {
+		cfg.BaseEndpoint = aws.String(envConfig.BaseEndpoint)
+	}
+	if customizesCredentialsHTTPClient(options) {
+		cfg.HTTPClient = newCredentialsHTTPClient(options, 0)
+	}
+	return cfg
+}
+
+// Loads the SDK config for the region, honoring the options that control how the config is resolved.
+func loadConfig(
+	ctx context.Context, region string, options Options, optFns ...func(*config.LoadOptions) error,
+) (aws.Config, error) {
+	var defaultsMode aws.DefaultsMode
+	if !defaultsMode.SetFromString(string(options.DefaultsMode)) {
+		return aws.Config{}, fmt.Errorf("unknown defaults mode: %s", options.DefaultsMode)
+	}
+
+	if options.DisableSharedConfig {
+		cfg, err := loadEnvOnlyConfig(region, options)
+		cfg.DefaultsMode = defaultsMode
+		return cfg, err
+	}
+
+	loadOptFns := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if defaultsMode != "" {
+		loadOptFns = append(loadOptFns, config.WithDefaultsMode(defaultsMode))
+	}
+	if apiOptions := sdkAPIOptions(options); apiOptions != nil {
+		loadOptFns = append(loadOptFns, config.WithAPIOptions(apiOptions))
+	}
+	if customizesCredentialsHTTPClient(options) {
+		// The container credentials provider does not inherit the HTTP client of the config.
+		client := newCredentialsHTTPClient(options, 0)
+		loadOptFns = append(loadOptFns, config.WithHTTPClient(client),
+			config.WithEndpointCredentialOptions(func(o *endpointcreds.Options) {
+				o.HTTPClient = client
+			}))
+	}
+	return config.LoadDefaultConfig(ctx, append(loadOptFns, optFns...)...)
+}
+
+// Returns the middleware stack options of the SDK clients created by the signer: the sts throttle monitor followed by
+// the API options of the options.
+func sdkAPIOptions(options Options) []func(*middleware.Stack) error {
+	apiOptions := stsThrottleAPIOptions(options)
+	if len(options.APIOptions) == 0 {
+		return apiOptions
+	}
+	return append(apiOptions, options.APIOptions...)
+}
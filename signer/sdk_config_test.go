@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigWithDefaultsMode(t *testing.T) {
+	cfg, err := loadConfig(Ctx, TestRegion, resolveOptions([]Option{WithDefaultsMode(aws.DefaultsModeInRegion)}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, TestRegion, cfg.Region)
+	assert.Equal(t, aws.DefaultsModeInRegion, cfg.DefaultsMode)
+}
+
+func TestLoadConfigWithoutSharedConfigAndDefaultsMode(t *testing.T) {
+	options := resolveOptions([]Option{WithoutSharedConfig(), WithDefaultsMode(aws.DefaultsModeStandard)})
+
+	cfg, err := loadConfig(Ctx, TestRegion, options)
+
+	assert.NoError(t, err)
+	assert.Equal(t, aws.DefaultsModeStandard, cfg.DefaultsMode)
+}
+
+func TestLoadConfigWithInvalidDefaultsMode(t *testing.T) {
+	_, err := loadConfig(Ctx, TestRegion, resolveOptions([]Option{WithDefaultsMode("warp-speed")}))
+
+	assert.Error(t, err)
+}
+
+func TestNewEnvSDKConfigAppliesClientSettings(t *testing.T) {
+	t.Setenv("AWS_ACCOUNT_ID_ENDPOINT_MODE", "required")
+	t.Setenv("AWS_ENDPOINT_URL", "https://endpoint.example.com")
+	t.Setenv("AWS_SDK_UA_APP_ID", "orders")
+	envConfig, err := config.NewEnvConfig()
+	assert.NoError(t, err)
+
+	cfg := newEnvSDKConfig("", envConfig, Options{})
+
+	assert.Equal(t, aws.AccountIDEndpointMode(aws.AccountIDEndpointModeRequired), cfg.AccountIDEndpointMode)
+	assert.Equal(t, "https://endpoint.example.com", aws.ToString(cfg.BaseEndpoint))
+	assert.Equal(t, "orders", cfg.AppID)
+}
+
+func TestNewEnvSDKConfigDefaults(t *testing.T) {
+	cfg := newEnvSDKConfig(TestRegion, config.EnvConfig{Region: "eu-west-1"}, Options{})
+
+	assert.Equal(t, TestRegion, cfg.Region)
+	assert.Equal(t, aws.AccountIDEndpointMode(aws.AccountIDEndpointModePreferred), cfg.AccountIDEndpointMode)
+	assert.Nil(t, cfg.BaseEndpoint)
+}
+
+func TestNewEnvSDKConfigIgnoresConfiguredEndpoints(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL", "https://endpoint.example.com")
+	t.Setenv("AWS_IGNORE_CONFIGURED_ENDPOINT_URLS", "true")
+	envConfig, err := config.NewEnvConfig()
+	assert.NoError(t, err)
+
+	cfg := newEnvSDKConfig(TestRegion, envConfig, Options{})
+
+	assert.Nil(t, cfg.BaseEndpoint)
+}
+
+// Adds the audit header to every request of the client.
+func addAuditHeader(stack *middleware.Stack) error {
+	return stack.Build.Add(middleware.BuildMiddlewareFunc("AuditHeader", func(
+		ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+	) (middleware.BuildOutput, middleware.Metadata, error) {
+		if req, ok := in.Request.(*smithyhttp.Request); ok {
+			req.Header.Set("X-Audit", "kafka-signer")
+		}
+		return next.HandleBuild(ctx, in)
+	}), middleware.After)
+}
+
+func TestGenerateAuthTokenWithAPIOptions(t *testing.T) {
+	for name, optFns := range map[string][]Option{
+		"shared config":   {WithAPIOptions(addAuditHeader)},
+		"env config only": {WithAPIOptions(addAuditHeader), WithoutSharedConfig()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var auditHeader string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auditHeader = r.Header.Get("X-Audit")
+				fmt.Fprint(w, assumeRoleWithWebIdentityResponse)
+			}))
+			t.Cleanup(server.Close)
+			t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+
+			_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, "arn:aws:iam::123456789012:role/TestRole", "",
+				fetchTestWebIdentityToken, optFns...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "kafka-signer", auditHeader)
+		})
+	}
+}
+
+func TestSDKAPIOptions(t *testing.T) {
+	assert.Empty(t, sdkAPIOptions(resolveOptions(nil)))
+
+	monitor := NewSTSThrottleMonitor()
+	options := resolveOptions([]Option{WithSTSThrottleMonitor(monitor), WithAPIOptions(addAuditHeader)})
+	assert.Len(t, sdkAPIOptions(options), 2)
+}
@@ -0,0 +1,33 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ErrSessionTokenRequired is returned when the session token policy requires temporary credentials and the resolved
+// credentials are long-term access keys without a session token.
+var ErrSessionTokenRequired = errors.New("aws credentials have no session token")
+
+// SessionTokenPolicy decides whether credentials without a session token may sign auth tokens.
+type SessionTokenPolicy int
+
+const (
+	// SessionTokenOptional signs with long-term access keys as well as temporary credentials. This is the default.
+	SessionTokenOptional SessionTokenPolicy = iota
+
+	// SessionTokenRequired only signs with temporary credentials, failing token generation with
+	// ErrSessionTokenRequired for long-term access keys.
+	SessionTokenRequired
+)
+
+// Applies the session token policy to the resolved credentials.
+func enforceSessionTokenPolicy(credentials *aws.Credentials, options Options) error {
+	if options.SessionTokenPolicy != SessionTokenRequired || credentials.SessionToken != "" {
+		return nil
+	}
+	return fmt.Errorf("%w: access key %s is a long-term key, temporary credentials are required",
+		ErrSessionTokenRequired, shortenKeyID(credentials.AccessKeyID))
+}
@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+// RefreshOnSignal force refreshes the token of the provider every time the process receives one of the signals, SIGHUP
+// when none are given, until ctx is done. Each refreshed token is written to tokenFile, unless empty, for processes
+// reading the token from disk. Failures are reported to the logger of the provider and the previous token stays in
+// use.
+func RefreshOnSignal(ctx context.Context, provider *Provider, tokenFile string, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	go func() {
+		defer signal.Stop(received)
+		refreshOnSignals(ctx, provider, tokenFile, received)
+	}()
+}
+
+// Force refreshes the token on every received signal until ctx is done. The token file is not rewritten when the
+// refresh produced the token already written to it.
+func refreshOnSignals(ctx context.Context, provider *Provider, tokenFile string, received <-chan os.Signal) {
+	var written *Token
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-received:
+			token, err := provider.ForceRefresh(ctx)
+			if err != nil || tokenFile == "" || isNoOpRefresh(written, token) {
+				continue
+			}
+			if err := writeTokenFile(tokenFile, token); err != nil {
+				logTokenWriteFailed(ctx, provider.options.Logger, err)
+				continue
+			}
+			written = token
+		}
+	}
+}
+
+// Replaces the token file with the token value, atomically so readers never see a partial token.
+func writeTokenFile(path string, token *Token) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(token.Value); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
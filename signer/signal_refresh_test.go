@@ -0,0 +1,63 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Delivers the signal to the signal refresh loop and waits for the loop to finish handling it.
+func refreshOnTestSignal(provider *Provider, tokenFile string) {
+	ctx, cancel := context.WithCancel(Ctx)
+	received := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		refreshOnSignals(ctx, provider, tokenFile, received)
+	}()
+
+	received <- syscall.SIGHUP
+	cancel()
+	<-done
+}
+
+func TestRefreshOnSignalWritesTokenFile(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	refreshOnTestSignal(provider, tokenFile)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	written, err := os.ReadFile(tokenFile)
+	assert.NoError(t, err)
+	assert.NotSame(t, first, token)
+	assert.Equal(t, 2, credentialsProvider.calls)
+	assert.Equal(t, token.Value, string(written))
+}
+
+func TestRefreshOnSignalLogsTokenFileFailure(t *testing.T) {
+	var logs bytes.Buffer
+	provider, _ := newCountingProvider(WithJSONLogging(&logs))
+
+	refreshOnTestSignal(provider, filepath.Join(t.TempDir(), "missing", "token"))
+
+	assert.Contains(t, logs.String(), EventTokenWriteFailed)
+}
+
+func TestRefreshOnSignalStopsWithContext(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider()
+	ctx, cancel := context.WithCancel(Ctx)
+
+	RefreshOnSignal(ctx, provider, "")
+	cancel()
+
+	assert.Equal(t, 0, credentialsProvider.calls)
+}
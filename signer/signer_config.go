@@ -0,0 +1,177 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SignerConfig is the signer configuration managed outside the application, e.g. in SSM Parameter Store, and loaded
+// through a ConfigSource.
+type SignerConfig struct {
+	// Region is the region of the MSK cluster. It is required.
+	Region string
+
+	// RoleARN is the role assumed to sign the auth token. Credentials are loaded from the default credential chain
+	// when empty.
+	RoleARN string
+
+	// STSSessionName is the session name of the assumed role. DefaultSessionName is used when empty.
+	STSSessionName string
+
+	// ClusterARN is the ARN of the MSK cluster the auth token is meant for.
+	ClusterARN string
+
+	// Expiry is the requested lifetime of the auth token.
+	Expiry time.Duration
+
+	// MaxExpiry is the longest token lifetime allowed by organization policy. Longer tokens are clamped to it.
+	MaxExpiry time.Duration
+}
+
+// Validate reports whether the signer configuration is complete and consistent.
+func (c SignerConfig) Validate() error {
+	if c.Region == "" {
+		return errors.New("signer config region cannot be empty")
+	}
+	if c.Expiry < 0 {
+		return fmt.Errorf("signer config expiry cannot be negative: %s", c.Expiry)
+	}
+	if c.Expiry > 0 {
+		if err := validateExpiry(c.Expiry); err != nil {
+			return fmt.Errorf("signer config expiry: %w", err)
+		}
+	}
+	if c.MaxExpiry < 0 {
+		return fmt.Errorf("signer config max expiry cannot be negative: %s", c.MaxExpiry)
+	}
+	if c.MaxExpiry > 0 && c.Expiry > c.MaxExpiry {
+		return fmt.Errorf("signer config expiry %s exceeds the max expiry %s: %w", c.Expiry, c.MaxExpiry,
+			ErrExpiryExceedsMax)
+	}
+	return nil
+}
+
+// JSON document of the signer configuration, with the expiries as a Go duration string or a number of seconds.
+type signerConfigJSON struct {
+	Region         string          `json:"region,omitempty"`
+	RoleARN        string          `json:"roleArn,omitempty"`
+	STSSessionName string          `json:"stsSessionName,omitempty"`
+	ClusterARN     string          `json:"clusterArn,omitempty"`
+	Expiry         json.RawMessage `json:"expiry,omitempty"`
+	MaxExpiry      json.RawMessage `json:"maxExpiry,omitempty"`
+}
+
+// MarshalJSON encodes the signer configuration with camel case keys and the expiries as Go duration strings.
+func (c SignerConfig) MarshalJSON() ([]byte, error) {
+	doc := signerConfigJSON{
+		Region:         c.Region,
+		RoleARN:        c.RoleARN,
+		STSSessionName: c.STSSessionName,
+		ClusterARN:     c.ClusterARN,
+	}
+	if c.Expiry != 0 {
+		doc.Expiry = json.RawMessage(`"` + c.Expiry.String() + `"`)
+	}
+	if c.MaxExpiry != 0 {
+		doc.MaxExpiry = json.RawMessage(`"` + c.MaxExpiry.String() + `"`)
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes the signer configuration, accepting the expiries as Go duration strings such as "10m" or as
+// numbers of seconds.
+func (c *SignerConfig) UnmarshalJSON(data []byte) error {
+	var doc signerConfigJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	expiry, err := parseExpiryJSON(doc.Expiry)
+	if err != nil {
+		return err
+	}
+
+	maxExpiry, err := parseExpiryJSON(doc.MaxExpiry)
+	if err != nil {
+		return err
+	}
+
+	*c = SignerConfig{
+		Region:         doc.Region,
+		RoleARN:        doc.RoleARN,
+		STSSessionName: doc.STSSessionName,
+		ClusterARN:     doc.ClusterARN,
+		Expiry:         expiry,
+		MaxExpiry:      maxExpiry,
+	}
+	return nil
+}
+
+// Parses an expiry given as a JSON duration string or number of seconds.
+func parseExpiryJSON(raw json.RawMessage) (time.Duration, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+
+	var seconds int64
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	var duration string
+	if err := json.Unmarshal(raw, &duration); err != nil {
+		return 0, fmt.Errorf("signer config expiry must be a duration string or a number of seconds: %s", raw)
+	}
+
+	expiry, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid signer config expiry: %w", err)
+	}
+	return expiry, nil
+}
+
+// ConfigSource supplies the signer configuration. Sources that reload their configuration return the latest one.
+type ConfigSource interface {
+	Config(ctx context.Context) (SignerConfig, error)
+}
+
+// GenerateAuthTokenFromConfigSource generates base64 encoded signed url as auth token using the current configuration
+// of the config source. The configured role is assumed when set, otherwise credentials are loaded from the default
+// credentials provider chain. The configured cluster ARN, expiry and max expiry are applied before optFns, which can
+// override them. The max expiry of the config source clamps the token lifetime.
+func GenerateAuthTokenFromConfigSource(
+	ctx context.Context, source ConfigSource, optFns ...Option,
+) (string, int64, error) {
+	cfg, err := source.Config(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to load signer config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return "", 0, fmt.Errorf("invalid signer config: %w", err)
+	}
+
+	optFns = append(configOptions(cfg), optFns...)
+	if cfg.RoleARN != "" {
+		return GenerateAuthTokenFromRole(ctx, cfg.Region, cfg.RoleARN, cfg.STSSessionName, optFns...)
+	}
+	return GenerateAuthToken(ctx, cfg.Region, optFns...)
+}
+
+// Returns the options applying the cluster ARN, expiry and max expiry of the signer configuration.
+func configOptions(cfg SignerConfig) []Option {
+	var optFns []Option
+	if cfg.Expiry > 0 {
+		optFns = append(optFns, WithExpiry(cfg.Expiry))
+	}
+	if cfg.MaxExpiry > 0 {
+		optFns = append(optFns, WithMaxExpiry(cfg.MaxExpiry, MaxExpiryClamp))
+	}
+	if cfg.ClusterARN != "" {
+		optFns = append(optFns, WithClusterARN(cfg.ClusterARN))
+	}
+	return optFns
+}
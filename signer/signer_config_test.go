@@ -0,0 +1,123 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a fixed signer config.
+type staticConfigSource struct {
+	config SignerConfig
+	err    error
+}
+
+func (s staticConfigSource) Config(ctx context.Context) (SignerConfig, error) {
+	return s.config, s.err
+}
+
+func TestSignerConfigValidate(t *testing.T) {
+	assert.NoError(t, SignerConfig{Region: TestRegion}.Validate())
+	assert.NoError(t, SignerConfig{Region: TestRegion, Expiry: 5 * time.Minute}.Validate())
+	assert.Error(t, SignerConfig{}.Validate())
+	assert.Error(t, SignerConfig{Region: TestRegion, Expiry: -time.Second}.Validate())
+	assert.ErrorIs(t, SignerConfig{Region: TestRegion, Expiry: MaxServiceExpiry + time.Hour}.Validate(), ErrInvalidExpiry)
+	assert.Error(t, SignerConfig{Region: TestRegion, MaxExpiry: -time.Second}.Validate())
+	assert.ErrorIs(t, SignerConfig{Region: TestRegion, Expiry: 10 * time.Minute, MaxExpiry: 5 * time.Minute}.Validate(),
+		ErrExpiryExceedsMax)
+}
+
+func TestSignerConfigJSON(t *testing.T) {
+	var cfg SignerConfig
+	err := json.Unmarshal([]byte(`{"region": "us-west-2", "roleArn": "arn:aws:iam::123456789012:role/kafka", `+
+		`"stsSessionName": "kafka", "clusterArn": "arn:aws:kafka:us-west-2:123456789012:cluster/demo/abc", `+
+		`"expiry": "10m", "maxExpiry": 720}`), &cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, SignerConfig{
+		Region:         "us-west-2",
+		RoleARN:        "arn:aws:iam::123456789012:role/kafka",
+		STSSessionName: "kafka",
+		ClusterARN:     "arn:aws:kafka:us-west-2:123456789012:cluster/demo/abc",
+		Expiry:         10 * time.Minute,
+		MaxExpiry:      12 * time.Minute,
+	}, cfg)
+
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+
+	var decoded SignerConfig
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, cfg, decoded)
+}
+
+func TestSignerConfigJSONExpiry(t *testing.T) {
+	var cfg SignerConfig
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"region": "us-west-2", "expiry": 300}`), &cfg))
+	assert.Equal(t, 5*time.Minute, cfg.Expiry)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"region": "us-west-2"}`), &cfg))
+	assert.Equal(t, time.Duration(0), cfg.Expiry)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"region": "us-west-2", "expiry": "soon"}`), &cfg))
+	assert.Error(t, json.Unmarshal([]byte(`{"region": "us-west-2", "expiry": true}`), &cfg))
+}
+
+func TestGenerateAuthTokenFromConfigSource(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-CONFIG-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-CONFIG-SECRET-KEY")
+
+	token, expiryMs, err := GenerateAuthTokenFromConfigSource(Ctx,
+		staticConfigSource{config: SignerConfig{Region: TestRegion}})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, int64(0), expiryMs)
+}
+
+func TestGenerateAuthTokenFromConfigSourceClampsToMaxExpiry(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-CONFIG-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-CONFIG-SECRET-KEY")
+
+	token, _, err := GenerateAuthTokenFromConfigSource(Ctx,
+		staticConfigSource{config: SignerConfig{Region: TestRegion, MaxExpiry: 5 * time.Minute}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "300", decodeTokenParams(t, token).Get(ExpiresQueryKey))
+}
+
+func TestGenerateAuthTokenFromConfigSourceWithExpiry(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-CONFIG-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-CONFIG-SECRET-KEY")
+
+	token, _, err := GenerateAuthTokenFromConfigSource(Ctx,
+		staticConfigSource{config: SignerConfig{Region: TestRegion, Expiry: 10 * time.Minute}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "600", decodeTokenParams(t, token).Get(ExpiresQueryKey))
+}
+
+func TestGenerateAuthTokenFromConfigSourceOptionsOverrideConfig(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-CONFIG-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-CONFIG-SECRET-KEY")
+
+	token, _, err := GenerateAuthTokenFromConfigSource(Ctx,
+		staticConfigSource{config: SignerConfig{Region: TestRegion, Expiry: 10 * time.Minute}},
+		WithExpiry(5*time.Minute))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "300", decodeTokenParams(t, token).Get(ExpiresQueryKey))
+}
+
+func TestGenerateAuthTokenFromFailingConfigSource(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromConfigSource(Ctx, staticConfigSource{err: errors.New("unreachable")})
+	assert.Error(t, err)
+
+	_, _, err = GenerateAuthTokenFromConfigSource(Ctx, staticConfigSource{config: SignerConfig{}})
+	assert.Error(t, err)
+}
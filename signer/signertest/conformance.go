@@ -0,0 +1,157 @@
+package signertest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+)
+
+const (
+	// Number of goroutines requesting tokens at the same time in the concurrency check.
+	concurrentCallers = 16
+
+	// Number of tokens every goroutine requests in the concurrency check.
+	callsPerCaller = 4
+
+	// Number of successive tokens requested in the refresh check.
+	successiveCalls = 3
+
+	// How long a provider may take to return once its context is canceled.
+	cancelTimeout = 10 * time.Second
+)
+
+// RunProviderTests runs the conformance suite against the provider as subtests of t. The provider must be able to
+// generate tokens when the suite runs. It checks that:
+//
+//   - tokens are returned with a value and an expiry in the future, no later than signer.MaxServiceExpiry, and no
+//     later than the expiry signed into tokens that parse as MSK IAM auth tokens,
+//   - successive tokens are unexpired and never expire before a token returned earlier,
+//   - concurrent calls all return unexpired tokens,
+//   - calls with a canceled context return promptly, with either a token or an error, never both nor neither.
+func RunProviderTests(t *testing.T, provider signer.TokenProvider) {
+	t.Helper()
+
+	t.Run("Token", func(t *testing.T) {
+		requireToken(t, provider, context.Background())
+	})
+
+	t.Run("SuccessiveTokens", func(t *testing.T) {
+		var previous *signer.Token
+		for i := 0; i < successiveCalls; i++ {
+			token := requireToken(t, provider, context.Background())
+			if previous != nil && token.ExpirationTimeMs < previous.ExpirationTimeMs {
+				t.Errorf("call %d returned a token expiring at %s, before the token of the previous call expiring at %s",
+					i+1, token.Expiry(0).UTC(), previous.Expiry(0).UTC())
+			}
+			previous = token
+		}
+	})
+
+	t.Run("ConcurrentTokens", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan string, concurrentCallers*callsPerCaller)
+		for i := 0; i < concurrentCallers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < callsPerCaller; j++ {
+					if _, problem := requestToken(provider, context.Background()); problem != "" {
+						errs <- problem
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for problem := range errs {
+			t.Errorf("concurrent call: %s", problem)
+		}
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		type result struct {
+			token *signer.Token
+			err   error
+		}
+		done := make(chan result, 1)
+		go func() {
+			token, err := provider.Token(ctx)
+			done <- result{token, err}
+		}()
+
+		select {
+		case r := <-done:
+			if r.err != nil && r.token != nil {
+				t.Errorf("returned both a token and the error %v", r.err)
+			}
+			if r.err == nil {
+				if r.token == nil {
+					t.Fatal("returned neither a token nor an error")
+				}
+				if problem := expiryProblem(r.token, time.Now()); problem != "" {
+					t.Error(problem)
+				}
+			}
+		case <-time.After(cancelTimeout):
+			t.Fatalf("did not return within %s of its context being canceled", cancelTimeout)
+		}
+	})
+}
+
+// Requests a token, failing the test unless an unexpired token is returned without error.
+func requireToken(t *testing.T, provider signer.TokenProvider, ctx context.Context) *signer.Token {
+	t.Helper()
+
+	token, problem := requestToken(provider, ctx)
+	if problem != "" {
+		t.Fatal(problem)
+	}
+	return token
+}
+
+// Requests a token, returning it with a description of what is wrong with the outcome, or an empty string when it
+// conforms. It does not fail the test so it can be called from other goroutines.
+func requestToken(provider signer.TokenProvider, ctx context.Context) (*signer.Token, string) {
+	token, err := provider.Token(ctx)
+	switch {
+	case err != nil && token != nil:
+		return nil, "returned both a token and the error " + err.Error()
+	case err != nil:
+		return nil, "failed to return a token: " + err.Error()
+	case token == nil:
+		return nil, "returned neither a token nor an error"
+	case token.Value == "":
+		return nil, "returned a token with an empty value"
+	}
+	return token, expiryProblem(token, time.Now())
+}
+
+// Describes what is wrong with the expiry of the token at now, or returns an empty string when it conforms.
+func expiryProblem(token *signer.Token, now time.Time) string {
+	expiry := token.Expiry(0)
+	if !expiry.After(now) {
+		return "returned a token that expired at " + expiry.UTC().Format(time.RFC3339Nano)
+	}
+	if latest := now.Add(signer.MaxServiceExpiry); expiry.After(latest) {
+		return "returned a token expiring at " + expiry.UTC().Format(time.RFC3339) + ", after the service maximum " +
+			latest.UTC().Format(time.RFC3339)
+	}
+
+	params, err := signer.ParseToken(token.Value)
+	if err != nil {
+		// Tokens that do not parse, e.g. wrapped in an envelope, only have their reported expiry checked.
+		return ""
+	}
+	if signed := params.ExpiresAt(); expiry.After(signed) {
+		return "returned a token expiring at " + expiry.UTC().Format(time.RFC3339Nano) +
+			", after the expiry signed into it " + signed.UTC().Format(time.RFC3339Nano)
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package signertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCredentialsProvider = credentials.NewStaticCredentialsProvider(
+	"TEST-CONFORMANCE-ACCESS-KEY", "TEST-CONFORMANCE-SECRET-KEY", "")
+
+func TestProviderConformance(t *testing.T) {
+	RunProviderTests(t, signer.NewProvider("us-west-2", testCredentialsProvider))
+}
+
+func TestProviderWithExpiryConformance(t *testing.T) {
+	RunProviderTests(t, signer.NewProvider("us-west-2", testCredentialsProvider,
+		signer.WithExpiry(5*time.Minute), signer.WithExpiryMargin(30*time.Second)))
+}
+
+func TestStaticTokenProviderConformance(t *testing.T) {
+	RunProviderTests(t, signer.StaticTokenProvider("static-token", time.Now().Add(time.Hour)))
+}
+
+func TestExpiryProblem(t *testing.T) {
+	now := time.Now()
+	token, err := signer.GenerateToken(context.Background(), "us-west-2", testCredentialsProvider)
+	assert.NoError(t, err)
+
+	assert.Empty(t, expiryProblem(token, now))
+	assert.Contains(t, expiryProblem(token, now.Add(time.Hour)), "returned a token that expired at")
+
+	extended := *token
+	extended.ExpirationTimeMs += time.Minute.Milliseconds()
+	assert.Contains(t, expiryProblem(&extended, now), "after the expiry signed into it")
+
+	farFuture := &signer.Token{Value: "opaque", ExpirationTimeMs: now.Add(30 * 24 * time.Hour).UnixMilli()}
+	assert.Contains(t, expiryProblem(farFuture, now), "after the service maximum")
+}
@@ -0,0 +1,9 @@
+// Package signertest provides a conformance test suite for implementations of signer.TokenProvider, so third-party
+// providers can verify they behave as the Kafka client adapters of the signer expect:
+//
+//	func TestMyProviderConformance(t *testing.T) {
+//		signertest.RunProviderTests(t, NewMyProvider(...))
+//	}
+//
+// Run the suite with the race detector enabled, go test -race, for its concurrency checks to be meaningful.
+package signertest
@@ -0,0 +1,40 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	minSourceIdentityLength = 2  // Shortest source identity accepted by sts.
+	maxSourceIdentityLength = 64 // Longest source identity accepted by sts.
+)
+
+// ErrInvalidSourceIdentity is returned when the source identity is not accepted by sts, because of its length or
+// characters.
+var ErrInvalidSourceIdentity = errors.New("invalid source identity")
+
+// Validates that the source identity holds 2 to 64 characters out of letters, digits and _+=,.@-, as sts requires.
+func validateSourceIdentity(sourceIdentity string) error {
+	if len(sourceIdentity) < minSourceIdentityLength || len(sourceIdentity) > maxSourceIdentityLength {
+		return fmt.Errorf("%w: %q must hold %d to %d characters", ErrInvalidSourceIdentity, sourceIdentity,
+			minSourceIdentityLength, maxSourceIdentityLength)
+	}
+	for _, r := range sourceIdentity {
+		if !isSourceIdentityChar(r) {
+			return fmt.Errorf("%w: %q holds %q, only letters, digits and _+=,.@- are allowed",
+				ErrInvalidSourceIdentity, sourceIdentity, r)
+		}
+	}
+	return nil
+}
+
+// Reports whether sts accepts the character in a source identity.
+func isSourceIdentityChar(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("_+=,.@-", r)
+}
@@ -0,0 +1,56 @@
+package signer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSourceIdentity(t *testing.T) {
+	for _, sourceIdentity := range []string{"jane", "svc_orders-1", "jane.doe@example.com", "a+b=c,d"} {
+		assert.NoError(t, validateSourceIdentity(sourceIdentity), sourceIdentity)
+	}
+	assert.NoError(t, validateSourceIdentity(strings.Repeat("a", maxSourceIdentityLength)))
+}
+
+func TestValidateSourceIdentityRejectsInvalid(t *testing.T) {
+	for _, sourceIdentity := range []string{
+		"a", strings.Repeat("a", maxSourceIdentityLength+1), "jane doe", "aws:jane", "jane/doe", "jané",
+	} {
+		assert.ErrorIs(t, validateSourceIdentity(sourceIdentity), ErrInvalidSourceIdentity, sourceIdentity)
+	}
+}
+
+func TestGenerateAuthTokenFromRoleWithSourceIdentity(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	client := &mockSTSClient{}
+
+	_, _, err := GenerateAuthTokenFromRole(Ctx, TestRegion, "arn:aws:iam::123456789012:role/kafka", "",
+		WithSTSClient(client), WithSourceIdentity("jane.doe"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jane.doe"}, client.sourceIdentities)
+}
+
+func TestGenerateAuthTokenFromRoleWithoutSourceIdentity(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	client := &mockSTSClient{}
+
+	_, _, err := GenerateAuthTokenFromRole(Ctx, TestRegion, "arn:aws:iam::123456789012:role/kafka", "",
+		WithSTSClient(client))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, client.sourceIdentities)
+}
+
+func TestGenerateAuthTokenFromRoleWithInvalidSourceIdentity(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	client := &mockSTSClient{}
+
+	_, _, err := GenerateAuthTokenFromRole(Ctx, TestRegion, "arn:aws:iam::123456789012:role/kafka", "",
+		WithSTSClient(client), WithSourceIdentity("jane doe"))
+
+	assert.ErrorIs(t, err, ErrInvalidSourceIdentity)
+	assert.Empty(t, client.roleARNs)
+}
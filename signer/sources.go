@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"sort"
+	"sync"
+)
+
+// ModulePath is the path of the core signer module.
+const ModulePath = "github.com/aws/aws-msk-iam-sasl-signer-go"
+
+// SourceInfo describes a credential source integration compiled into the binary.
+type SourceInfo struct {
+	// Name identifies the source, e.g. the Source reported on the credentials it retrieves.
+	Name string `json:"name"`
+
+	// Module is the path of the Go module providing the source.
+	Module string `json:"module"`
+
+	// Description tells what the source loads credentials from.
+	Description string `json:"description"`
+}
+
+// The credential sources built into the core module.
+var coreSources = []SourceInfo{
+	{Name: "DefaultCredentialChain", Module: ModulePath, Description: "SDK default credentials provider chain"},
+	{Name: "Profile", Module: ModulePath, Description: "named profile of the shared config files"},
+	{Name: "Role", Module: ModulePath, Description: "role assumed with sts AssumeRole"},
+	{Name: "CredentialsProvider", Module: ModulePath, Description: "caller supplied aws.CredentialsProvider"},
+	{Name: "WebIdentity", Module: ModulePath, Description: "role assumed with a fetched web identity token"},
+	{Name: "GitHubActions", Module: ModulePath, Description: "role assumed with a GitHub Actions OIDC token"},
+	{Name: SystemdCredentialsSource, Module: ModulePath, Description: "systemd LoadCredential files"},
+	{Name: RemoteSignerSource, Module: ModulePath, Description: "remote signing service"},
+	{Name: "ConfigSource", Module: ModulePath, Description: "signer configuration from a ConfigSource"},
+}
+
+var (
+	registeredSourcesMu sync.Mutex
+	registeredSources   []SourceInfo
+)
+
+// RegisterSource records a credential source integration provided by another module, so it is listed by
+// SupportedSources. Modules call it from an init function. Sources registered under an existing name are ignored.
+func RegisterSource(info SourceInfo) {
+	registeredSourcesMu.Lock()
+	defer registeredSourcesMu.Unlock()
+
+	for _, source := range append(coreSources, registeredSources...) {
+		if source.Name == info.Name {
+			return
+		}
+	}
+	registeredSources = append(registeredSources, info)
+}
+
+// SupportedSources returns the credential source integrations compiled into the binary: the core sources followed by
+// the sources registered by contrib modules, sorted by name. Orchestration tooling can use it to verify the
+// capabilities of a deployed binary.
+func SupportedSources() []SourceInfo {
+	registeredSourcesMu.Lock()
+	registered := append([]SourceInfo(nil), registeredSources...)
+	registeredSourcesMu.Unlock()
+
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Name < registered[j].Name
+	})
+	return append(append([]SourceInfo(nil), coreSources...), registered...)
+}
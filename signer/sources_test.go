@@ -0,0 +1,42 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the names of the sources.
+func sourceNames(sources []SourceInfo) []string {
+	var names []string
+	for _, source := range sources {
+		names = append(names, source.Name)
+	}
+	return names
+}
+
+func TestSupportedSourcesIncludesCoreSources(t *testing.T) {
+	sources := SupportedSources()
+
+	assert.Subset(t, sourceNames(sources), []string{"DefaultCredentialChain", "Role", SystemdCredentialsSource})
+	for _, source := range sources[:len(coreSources)] {
+		assert.Equal(t, ModulePath, source.Module)
+		assert.NotEmpty(t, source.Description)
+	}
+}
+
+func TestRegisterSource(t *testing.T) {
+	t.Cleanup(func() {
+		registeredSources = nil
+	})
+
+	RegisterSource(SourceInfo{Name: "Zeta", Module: ModulePath + "/contrib/zeta"})
+	RegisterSource(SourceInfo{Name: "Alpha", Module: ModulePath + "/contrib/alpha"})
+	RegisterSource(SourceInfo{Name: "Alpha", Module: "example.com/duplicate"})
+	RegisterSource(SourceInfo{Name: "Role", Module: "example.com/shadow"})
+
+	sources := SupportedSources()
+	assert.Len(t, sources, len(coreSources)+2)
+	assert.Equal(t, []string{"Alpha", "Zeta"}, sourceNames(sources[len(coreSources):]))
+	assert.Equal(t, ModulePath+"/contrib/alpha", sources[len(coreSources)].Module)
+}
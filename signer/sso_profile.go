@@ -0,0 +1,127 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+)
+
+// Longest source_profile chain followed when looking for the SSO profile credentials come from.
+const maxSourceProfileDepth = 8
+
+// SSOProfileError is returned when credentials cannot be loaded from a named profile backed by AWS IAM Identity
+// Center (SSO), either directly or through its source_profile chain. Its message tells how to refresh the SSO login.
+// Use errors.As to retrieve it.
+type SSOProfileError struct {
+	// Profile is the named profile credentials were requested from.
+	Profile string
+
+	// SSOProfile is the profile holding the SSO settings, Profile itself or one of its source profiles.
+	SSOProfile string
+
+	// SSOSession is the name of the sso-session section used by SSOProfile, empty for legacy SSO profiles.
+	SSOSession string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the underlying error followed by the refresh guidance.
+func (e *SSOProfileError) Error() string {
+	login := fmt.Sprintf("aws sso login --profile %s", e.SSOProfile)
+	if e.SSOSession != "" {
+		login = fmt.Sprintf("aws sso login --sso-session %s", e.SSOSession)
+	}
+
+	if e.LoginRequired() {
+		return fmt.Sprintf("profile %s uses SSO and its login has expired or is missing, run %q to refresh it: %v",
+			e.Profile, login, e.Err)
+	}
+	return fmt.Sprintf("profile %s uses SSO through profile %s: %v (if the SSO login expired, run %q)",
+		e.Profile, e.SSOProfile, e.Err, login)
+}
+
+// LoginRequired reports whether the SSO login has expired or no cached SSO token was found, so that running the
+// "aws sso login" command fixes the error.
+func (e *SSOProfileError) LoginRequired() bool {
+	var invalidToken *ssocreds.InvalidTokenError
+	return errors.As(e.Err, &invalidToken) || errors.Is(e.Err, fs.ErrNotExist)
+}
+
+// Unwrap returns the underlying error.
+func (e *SSOProfileError) Unwrap() error {
+	return e.Err
+}
+
+// Returns the shared config of the profile that credentials of the named profile come from when it uses SSO,
+// following source_profile references, or nil when no SSO profile is found or the shared config cannot be read. The
+// shared config files are located as the SDK does, honoring AWS_CONFIG_FILE and AWS_SHARED_CREDENTIALS_FILE.
+func resolveSSOProfile(ctx context.Context, profile string) *config.SharedConfig {
+	envConfig, err := config.NewEnvConfig()
+	if err != nil {
+		return nil
+	}
+	withSharedFiles := func(o *config.LoadSharedConfigOptions) {
+		if envConfig.SharedConfigFile != "" {
+			o.ConfigFiles = []string{envConfig.SharedConfigFile}
+		}
+		if envConfig.SharedCredentialsFile != "" {
+			o.CredentialsFiles = []string{envConfig.SharedCredentialsFile}
+		}
+	}
+
+	name := profile
+	for i := 0; i < maxSourceProfileDepth && name != ""; i++ {
+		sharedConfig, err := config.LoadSharedConfigProfile(ctx, name, withSharedFiles)
+		if err != nil {
+			return nil
+		}
+		if sharedConfig.SSOSessionName != "" || sharedConfig.SSOStartURL != "" {
+			return &sharedConfig
+		}
+		if sharedConfig.SourceProfileName == name {
+			return nil
+		}
+		name = sharedConfig.SourceProfileName
+	}
+	return nil
+}
+
+// Checks that an SSO profile names the account and role to get credentials for, which the SDK only reports once
+// the SSO portal rejects the request.
+func validateSSOProfile(sharedConfig *config.SharedConfig) error {
+	var missing []string
+	if sharedConfig.SSOAccountID == "" {
+		missing = append(missing, "sso_account_id")
+	}
+	if sharedConfig.SSORoleName == "" {
+		missing = append(missing, "sso_role_name")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("profile %s is configured to use SSO but is missing %v", sharedConfig.Profile, missing)
+	}
+	return nil
+}
+
+// Wraps the error of loading credentials from the named profile in an SSOProfileError when the profile is backed by
+// SSO, validating the SSO profile first.
+func annotateSSOProfileError(ctx context.Context, profile string, err error) error {
+	sharedConfig := resolveSSOProfile(ctx, profile)
+	if sharedConfig == nil {
+		return err
+	}
+
+	if validateErr := validateSSOProfile(sharedConfig); validateErr != nil {
+		err = validateErr
+	}
+	return &SSOProfileError{
+		Profile:    profile,
+		SSOProfile: sharedConfig.Profile,
+		SSOSession: sharedConfig.SSOSessionName,
+		Err:        err,
+	}
+}
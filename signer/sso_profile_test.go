@@ -0,0 +1,99 @@
+package signer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSSOConfig = `
+[profile sso-dev]
+sso_session = corp
+sso_account_id = 123456789012
+sso_role_name = Kafka
+
+[profile sso-incomplete]
+sso_session = corp
+sso_account_id = 123456789012
+
+[profile kafka-role]
+role_arn = arn:aws:iam::123456789012:role/kafka
+source_profile = sso-dev
+
+[profile static]
+aws_access_key_id = AKIDEXAMPLE
+aws_secret_access_key = wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY
+
+[sso-session corp]
+sso_region = us-east-1
+sso_start_url = https://corp.awsapps.com/start
+`
+
+// Points the shared config at the test SSO config, with an empty SSO token cache.
+func setSSOConfig(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	assert.NoError(t, os.WriteFile(configFile, []byte(testSSOConfig), 0o600))
+	t.Setenv("HOME", dir)
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+}
+
+func TestGenerateAuthTokenFromSSOProfileWithoutLogin(t *testing.T) {
+	setSSOConfig(t)
+
+	_, _, err := GenerateAuthTokenFromProfile(Ctx, TestRegion, "sso-dev")
+
+	var ssoErr *SSOProfileError
+	assert.ErrorAs(t, err, &ssoErr)
+	assert.Equal(t, "sso-dev", ssoErr.SSOProfile)
+	assert.Equal(t, "corp", ssoErr.SSOSession)
+	assert.True(t, ssoErr.LoginRequired())
+	assert.Contains(t, err.Error(), `"aws sso login --sso-session corp"`)
+}
+
+func TestGenerateAuthTokenFromSSOSourceProfile(t *testing.T) {
+	setSSOConfig(t)
+
+	_, _, err := GenerateAuthTokenFromProfile(Ctx, TestRegion, "kafka-role")
+
+	var ssoErr *SSOProfileError
+	assert.ErrorAs(t, err, &ssoErr)
+	assert.Equal(t, "kafka-role", ssoErr.Profile)
+	assert.Equal(t, "sso-dev", ssoErr.SSOProfile)
+	assert.Contains(t, err.Error(), `"aws sso login --sso-session corp"`)
+}
+
+func TestGenerateAuthTokenFromIncompleteSSOProfile(t *testing.T) {
+	setSSOConfig(t)
+
+	_, _, err := GenerateAuthTokenFromProfile(Ctx, TestRegion, "sso-incomplete")
+
+	var ssoErr *SSOProfileError
+	assert.ErrorAs(t, err, &ssoErr)
+	assert.Contains(t, err.Error(), "missing [sso_role_name]")
+	assert.False(t, ssoErr.LoginRequired())
+}
+
+func TestSSOProfileErrorLoginRequired(t *testing.T) {
+	err := &SSOProfileError{
+		Profile: "legacy", SSOProfile: "legacy", Err: &ssocreds.InvalidTokenError{Err: errors.New("token expired")},
+	}
+
+	assert.True(t, err.LoginRequired())
+	assert.Contains(t, err.Error(), `"aws sso login --profile legacy"`)
+}
+
+func TestResolveSSOProfileIgnoresOtherProfiles(t *testing.T) {
+	setSSOConfig(t)
+
+	assert.Nil(t, resolveSSOProfile(Ctx, "static"))
+	assert.Nil(t, resolveSSOProfile(Ctx, "missing"))
+}
@@ -0,0 +1,11 @@
+package signer
+
+import "context"
+
+// Reports that the cached token is returned past its refresh time because generating a new one failed.
+func (p *Provider) reportStaleToken(ctx context.Context, token *Token, err error) {
+	logStaleTokenServed(ctx, p.options.Logger, token, err)
+	if p.options.OnStaleToken != nil {
+		p.options.OnStaleToken(token, err)
+	}
+}
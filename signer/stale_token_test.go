@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns credentials on the first retrieval and fails every later one.
+type outageCredentialsProvider struct {
+	calls int
+}
+
+func (o *outageCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	o.calls++
+	if o.calls > 1 {
+		return aws.Credentials{}, errors.New("credentials outage")
+	}
+	return aws.Credentials{AccessKeyID: "TEST-STALE-ACCESS-KEY", SecretAccessKey: "TEST-STALE-SECRET-KEY"}, nil
+}
+
+func TestProviderStaleTokenFallback(t *testing.T) {
+	var stale *Token
+	var staleErr error
+	var logs bytes.Buffer
+	provider := NewProvider(TestRegion, &outageCredentialsProvider{}, WithJSONLogging(&logs),
+		WithStaleTokenFallback(func(token *Token, err error) {
+			stale, staleErr = token, err
+		}))
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	provider.refreshAt = time.Now().Add(-time.Second)
+
+	second, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Same(t, first, stale)
+	assert.ErrorContains(t, staleErr, "credentials outage")
+	assert.Contains(t, logs.String(), EventStaleTokenServed)
+}
+
+func TestProviderStaleTokenFallbackWithoutToken(t *testing.T) {
+	credentialsProvider := &outageCredentialsProvider{calls: 1}
+	provider := NewProvider(TestRegion, credentialsProvider, WithStaleTokenFallback(nil))
+
+	_, err := provider.Token(Ctx)
+	assert.ErrorContains(t, err, "credentials outage")
+}
+
+func TestProviderWithoutStaleTokenFallback(t *testing.T) {
+	provider := NewProvider(TestRegion, &outageCredentialsProvider{})
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	provider.refreshAt = time.Now().Add(-time.Second)
+
+	_, err = provider.Token(Ctx)
+	assert.ErrorContains(t, err, "credentials outage")
+}
@@ -0,0 +1,75 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// STSAPIClient is the subset of the sts API used to assume roles. *sts.Client implements it. Passing one with
+// WithSTSClient decouples role assumption from the sts client construction of the signer; the service/sts package is
+// still linked in, as the SDK config loader depends on it.
+type STSAPIClient interface {
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (
+		*sts.AssumeRoleOutput, error)
+	AssumeRoleWithWebIdentity(ctx context.Context, params *sts.AssumeRoleWithWebIdentityInput,
+		optFns ...func(*sts.Options)) (*sts.AssumeRoleWithWebIdentityOutput, error)
+}
+
+var _ STSAPIClient = (*sts.Client)(nil)
+
+// Validates that the role can be assumed to sign tokens for the region, then returns the sts client configured in the
+// options, or creates one from the SDK config loaded for the sts region of the options, defaulting to the region. The
+// client calls the fault injector of the options, if any, before assuming roles.
+func roleSTSClient(ctx context.Context, region string, roleArn string, options Options) (STSAPIClient, error) {
+	if err := validateRoleAssumption(region, roleArn, options); err != nil {
+		return nil, err
+	}
+	if options.STSClient != nil {
+		return withSTSFaults(options.STSClient, options), nil
+	}
+
+	cfg, err := loadConfig(ctx, stsRegion(region, options), options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SDK config: %w", err)
+	}
+
+	return withSTSFaults(sts.NewFromConfig(cfg, roleSTSOptionFns(options)...), options), nil
+}
+
+// Creates the sts client used for role assumption and caller identity lookups, applying the sts settings of the
+// options on top of the loaded config.
+func newSTSClient(cfg aws.Config, options Options) *sts.Client {
+	return sts.NewFromConfig(cfg, stsOptionFns(options)...)
+}
+
+// Builds the option functions applying the sts settings of the options to an sts client.
+func stsOptionFns(options Options) []func(*sts.Options) {
+	var optFns []func(*sts.Options)
+
+	if options.STSRetryer != nil {
+		optFns = append(optFns, func(o *sts.Options) {
+			o.Retryer = options.STSRetryer
+		})
+	}
+
+	return optFns
+}
+
+// Builds the option functions of sts clients assuming roles, pointing them to the sts endpoint of the options when set,
+// else to the FIPS sts endpoint when enabled, on top of the sts settings of the options.
+func roleSTSOptionFns(options Options) []func(*sts.Options) {
+	optFns := stsOptionFns(options)
+	if options.STSEndpoint != "" {
+		optFns = append(optFns, func(o *sts.Options) {
+			o.BaseEndpoint = aws.String(options.STSEndpoint)
+		})
+	} else if fipsState := fipsEndpointState(options); fipsState != aws.FIPSEndpointStateUnset {
+		optFns = append(optFns, func(o *sts.Options) {
+			o.EndpointOptions.UseFIPSEndpoint = fipsState
+		})
+	}
+	return optFns
+}
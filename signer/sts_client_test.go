@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSTSClientWithDefaultRetryer(t *testing.T) {
+	client := newSTSClient(aws.Config{Region: TestRegion}, resolveOptions(nil))
+
+	assert.NotNil(t, client.Options().Retryer)
+	assert.IsType(t, &retry.Standard{}, client.Options().Retryer)
+}
+
+func TestNewSTSClientWithCustomRetryer(t *testing.T) {
+	retryer := retry.NewAdaptiveMode()
+
+	client := newSTSClient(aws.Config{Region: TestRegion}, resolveOptions([]Option{WithSTSRetryer(retryer)}))
+
+	assert.Same(t, retryer, client.Options().Retryer)
+}
+
+// Assumes roles without calling sts, recording the requested role arns and source identities. No credentials are
+// returned when noCredentials is set.
+type mockSTSClient struct {
+	roleARNs         []string
+	sourceIdentities []string
+	noCredentials    bool
+}
+
+func (m *mockSTSClient) AssumeRole(
+	ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options),
+) (*sts.AssumeRoleOutput, error) {
+	m.roleARNs = append(m.roleARNs, aws.ToString(params.RoleArn))
+	m.sourceIdentities = append(m.sourceIdentities, aws.ToString(params.SourceIdentity))
+	if m.noCredentials {
+		return &sts.AssumeRoleOutput{}, nil
+	}
+	return &sts.AssumeRoleOutput{Credentials: &types.Credentials{
+		AccessKeyId:     aws.String("TEST-STS-CLIENT-ACCESS-KEY"),
+		SecretAccessKey: aws.String("TEST-STS-CLIENT-SECRET-KEY"),
+		SessionToken:    aws.String("TEST-STS-CLIENT-SESSION-TOKEN"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}}, nil
+}
+
+func (m *mockSTSClient) AssumeRoleWithWebIdentity(
+	ctx context.Context, params *sts.AssumeRoleWithWebIdentityInput, optFns ...func(*sts.Options),
+) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	m.roleARNs = append(m.roleARNs, aws.ToString(params.RoleArn))
+	return &sts.AssumeRoleWithWebIdentityOutput{Credentials: &types.Credentials{
+		AccessKeyId:     aws.String("TEST-STS-CLIENT-ACCESS-KEY"),
+		SecretAccessKey: aws.String("TEST-STS-CLIENT-SECRET-KEY"),
+		SessionToken:    aws.String("TEST-STS-CLIENT-SESSION-TOKEN"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}}, nil
+}
+
+func TestGenerateAuthTokenFromRoleWithSTSClient(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	roleARN := "arn:aws:iam::123456789012:role/kafka"
+	client := &mockSTSClient{}
+
+	token, _, err := GenerateAuthTokenFromRole(Ctx, TestRegion, roleARN, "", WithSTSClient(client))
+
+	assert.NoError(t, err)
+	assert.Contains(t, decodeTokenParams(t, token).Get("X-Amz-Credential"), "TEST-STS-CLIENT-ACCESS-KEY")
+	assert.Equal(t, "TEST-STS-CLIENT-SESSION-TOKEN", decodeTokenParams(t, token).Get("X-Amz-Security-Token"))
+	assert.Equal(t, []string{roleARN}, client.roleARNs)
+}
+
+func TestGenerateAuthTokenFromRoleWithoutCredentials(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	client := &mockSTSClient{noCredentials: true}
+
+	_, _, err := GenerateAuthTokenFromRole(Ctx, TestRegion, "arn:aws:iam::123456789012:role/kafka", "",
+		WithSTSClient(client))
+
+	assert.ErrorContains(t, err, "no credentials returned")
+}
+
+func TestGenerateAuthTokenFromWebIdentityWithSTSClient(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	roleARN := "arn:aws:iam::123456789012:role/kafka-web"
+	client := &mockSTSClient{}
+
+	_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, roleARN, "", func(ctx context.Context) (string, error) {
+		return "web-identity-token", nil
+	}, WithSTSClient(client))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{roleARN}, client.roleARNs)
+}
+
+func TestNewProviderWithRoleARN(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL_STS", "http://127.0.0.1:0")
+	roleARN := "arn:aws:iam::123456789012:role/kafka"
+	client := &mockSTSClient{}
+
+	provider := NewProvider(TestRegion, nil, WithRoleARN(roleARN, ""), WithSTSClient(client))
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-STS-CLIENT-SESSION-TOKEN", decodeTokenParams(t, token.Value).Get("X-Amz-Security-Token"))
+	assert.Equal(t, []string{roleARN}, client.roleARNs)
+	assert.Equal(t, "assumed role "+roleARN, provider.DebugSnapshot().CredentialsProvider)
+}
@@ -0,0 +1,48 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// ErrPartitionMismatch is returned when a role cannot be assumed with the configured sts endpoint because the role,
+// the sts region and the signing region are not in a valid combination of partitions.
+var ErrPartitionMismatch = errors.New("partition mismatch")
+
+// Returns the region of the sts endpoint roles are assumed with: the sts region of the options, else the signing
+// region.
+func stsRegion(region string, options Options) string {
+	if options.STSRegion != "" {
+		return options.STSRegion
+	}
+	return region
+}
+
+// Validates that the role can be assumed with the sts endpoint of the options to sign tokens for the region. The role
+// must be in the partition of the sts region. Without an sts region the role is assumed in the signing partition, so a
+// role of another partition is only accepted when the sts region is configured explicitly, decoupling role assumption
+// from signing, e.g. to assume an aws-us-gov role and sign for an aws cluster. Role ARNs that do not parse are left for
+// sts to reject.
+func validateRoleAssumption(region string, roleArn string, options Options) error {
+	parsed, err := arn.Parse(roleArn)
+	if err != nil {
+		return nil
+	}
+
+	if options.STSRegion == "" {
+		if signingPartition := partitionOf(region).id; parsed.Partition != signingPartition {
+			return fmt.Errorf("%w: role %s is in partition %s but tokens are signed in partition %s, configure the "+
+				"sts region of the role partition with WithSTSRegion", ErrPartitionMismatch, roleArn, parsed.Partition,
+				signingPartition)
+		}
+		return nil
+	}
+
+	if stsPartition := partitionOf(options.STSRegion).id; parsed.Partition != stsPartition {
+		return fmt.Errorf("%w: role %s is in partition %s but sts region %s is in partition %s",
+			ErrPartitionMismatch, roleArn, parsed.Partition, options.STSRegion, stsPartition)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package signer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRoleAssumption(t *testing.T) {
+	tests := []struct {
+		name      string
+		region    string
+		roleArn   string
+		stsRegion string
+		wantErr   string
+	}{
+		{name: "same partition", region: "us-east-1", roleArn: "arn:aws:iam::123456789012:role/Kafka"},
+		{name: "govcloud", region: "us-gov-west-1", roleArn: "arn:aws-us-gov:iam::123456789012:role/Kafka"},
+		{name: "unparsed role", region: "us-east-1", roleArn: "Kafka"},
+		{
+			name: "cross partition", region: "us-east-1", roleArn: "arn:aws-us-gov:iam::123456789012:role/Kafka",
+			stsRegion: "us-gov-west-1",
+		},
+		{
+			name: "role outside the signing partition", region: "us-east-1",
+			roleArn: "arn:aws-us-gov:iam::123456789012:role/Kafka",
+			wantErr: "role arn:aws-us-gov:iam::123456789012:role/Kafka is in partition aws-us-gov but tokens are " +
+				"signed in partition aws",
+		},
+		{
+			name: "role outside the sts partition", region: "us-east-1",
+			roleArn: "arn:aws:iam::123456789012:role/Kafka", stsRegion: "cn-north-1",
+			wantErr: "role arn:aws:iam::123456789012:role/Kafka is in partition aws but sts region cn-north-1 is in " +
+				"partition aws-cn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := resolveOptions([]Option{WithSTSRegion(tt.stsRegion, "")})
+
+			err := validateRoleAssumption(tt.region, tt.roleArn, options)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrPartitionMismatch)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSTSRegion(t *testing.T) {
+	assert.Equal(t, "us-east-1", stsRegion("us-east-1", resolveOptions(nil)))
+	assert.Equal(t, "us-gov-west-1", stsRegion("us-east-1", resolveOptions([]Option{WithSTSRegion("us-gov-west-1", "")})))
+}
+
+func TestGenerateAuthTokenFromWebIdentityWithSTSEndpoint(t *testing.T) {
+	withSTSWebIdentityServer(t, "TEST-OIDC-TOKEN")
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "arn:aws-us-gov:iam::123456789012:role/Kafka", r.Form.Get("RoleArn"))
+		fmt.Fprint(w, assumeRoleWithWebIdentityResponse)
+	}))
+	t.Cleanup(server.Close)
+
+	token, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, "arn:aws-us-gov:iam::123456789012:role/Kafka",
+		"", fetchTestWebIdentityToken, WithSTSRegion("us-gov-west-1", server.URL))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), calls.Load())
+	credential := QueryKeyCredential.Get(decodeTokenParams(t, token))
+	assert.True(t, strings.HasPrefix(credential, "TEST-WEB-IDENTITY-ACCESS-KEY/"))
+	assert.Contains(t, credential, "/"+TestRegion+"/kafka-cluster/")
+}
+
+func TestGenerateAuthTokenFromRolePartitionMismatch(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromRole(Ctx, TestRegion, "arn:aws-cn:iam::123456789012:role/Kafka", "",
+		WithSTSRegion("us-gov-west-1", ""))
+
+	assert.ErrorIs(t, err, ErrPartitionMismatch)
+}
+
+func TestNewIRSAProviderPartitionMismatch(t *testing.T) {
+	setIRSAEnv(t, "arn:aws-us-gov:iam::123456789012:role/Kafka")
+
+	_, err := NewIRSAProvider(TestRegion)
+
+	assert.ErrorIs(t, err, ErrPartitionMismatch)
+}
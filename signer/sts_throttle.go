@@ -0,0 +1,141 @@
+package signer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	stsThrottleOperationID = "MSKIAMAuthSTSThrottleOperation" // Middleware tracking an sts operation across attempts.
+	stsThrottleAttemptID   = "MSKIAMAuthSTSThrottleAttempt"   // Middleware recording the outcome of each attempt.
+	retryMiddlewareID      = "Retry"                          // SDK middleware retrying failed attempts.
+)
+
+// STSThrottleStats is a snapshot of the sts throttling seen while retrieving credentials.
+type STSThrottleStats struct {
+	// Throttles is the number of sts call attempts rejected with a throttling error.
+	Throttles int64
+
+	// ConsecutiveThrottles is the number of attempts throttled since the last successful sts call.
+	ConsecutiveThrottles int64
+
+	// CurrentBackoff is how long the SDK backed off before retrying the last throttled attempt, zero once an sts call
+	// succeeded.
+	CurrentBackoff time.Duration
+
+	// LastThrottleTime is when the last throttled attempt completed, zero when none was throttled.
+	LastThrottleTime time.Time
+}
+
+// STSThrottleMonitor tracks throttled sts calls, so operators can tell when sts is the bottleneck during scale-out
+// events. It is safe for concurrent use.
+type STSThrottleMonitor struct {
+	mu    sync.Mutex
+	stats STSThrottleStats
+}
+
+// NewSTSThrottleMonitor returns a monitor that has not seen any sts call.
+func NewSTSThrottleMonitor() *STSThrottleMonitor {
+	return &STSThrottleMonitor{}
+}
+
+// Stats returns a snapshot of the sts throttling seen so far.
+func (m *STSThrottleMonitor) Stats() STSThrottleStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.stats
+}
+
+// AddMiddleware adds the middleware reporting throttled attempts to the monitor around the SDK retry middleware of
+// the stack. Append it to the APIOptions of sts clients created outside the signer, e.g. for stscreds providers.
+// Stacks without retry middleware are left unchanged.
+func (m *STSThrottleMonitor) AddMiddleware(stack *middleware.Stack) error {
+	if _, ok := stack.Finalize.Get(retryMiddlewareID); !ok {
+		return nil
+	}
+
+	operation := middleware.FinalizeMiddlewareFunc(stsThrottleOperationID, m.handleOperation)
+	if err := stack.Finalize.Insert(operation, retryMiddlewareID, middleware.Before); err != nil {
+		return err
+	}
+	attempt := middleware.FinalizeMiddlewareFunc(stsThrottleAttemptID, m.handleAttempt)
+	return stack.Finalize.Insert(attempt, retryMiddlewareID, middleware.After)
+}
+
+// Throttling state of an operation, shared by its attempts.
+type throttledOperation struct {
+	throttledAt time.Time
+}
+
+type throttledOperationKey struct{}
+
+func (m *STSThrottleMonitor) handleOperation(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	return next.HandleFinalize(context.WithValue(ctx, throttledOperationKey{}, &throttledOperation{}), in)
+}
+
+func (m *STSThrottleMonitor) handleAttempt(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	operation, _ := ctx.Value(throttledOperationKey{}).(*throttledOperation)
+	if operation != nil && !operation.throttledAt.IsZero() {
+		m.recordBackoff(time.Since(operation.throttledAt))
+	}
+
+	out, metadata, err := next.HandleFinalize(ctx, in)
+
+	throttled := m.recordAttempt(err)
+	if operation != nil {
+		operation.throttledAt = time.Time{}
+		if throttled {
+			operation.throttledAt = time.Now()
+		}
+	}
+	return out, metadata, err
+}
+
+// Records the outcome of an sts call attempt, reporting whether it was throttled.
+func (m *STSThrottleMonitor) recordAttempt(err error) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case err == nil:
+		m.stats.ConsecutiveThrottles = 0
+		m.stats.CurrentBackoff = 0
+	case isThrottleError(err):
+		m.stats.Throttles++
+		m.stats.ConsecutiveThrottles++
+		m.stats.LastThrottleTime = time.Now()
+		return true
+	}
+	return false
+}
+
+// Records how long the SDK backed off before retrying a throttled attempt.
+func (m *STSThrottleMonitor) recordBackoff(backoff time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats.CurrentBackoff = backoff
+}
+
+// Reports whether the error is one the SDK treats as throttling.
+func isThrottleError(err error) bool {
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
+
+// Returns the API options adding the throttle monitor middleware, or nil when no monitor is configured.
+func stsThrottleAPIOptions(options Options) []func(*middleware.Stack) error {
+	if options.STSThrottleMonitor == nil {
+		return nil
+	}
+	return []func(*middleware.Stack) error{options.STSThrottleMonitor.AddMiddleware}
+}
@@ -0,0 +1,95 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+const stsThrottlingResponse = `<ErrorResponse>
+  <Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error>
+  <RequestId>TEST-REQUEST-ID</RequestId>
+</ErrorResponse>`
+
+// Points the sts clients created by the signer at a local server throttling the first requests.
+func withThrottlingSTSServer(t *testing.T, throttledRequests int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= throttledRequests {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, stsThrottlingResponse)
+			return
+		}
+		fmt.Fprint(w, assumeRoleWithWebIdentityResponse)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+}
+
+// Retries throttled sts calls after a short fixed backoff.
+func fixedBackoffRetryer() *retry.Standard {
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) {
+			return 20 * time.Millisecond, nil
+		})
+	})
+}
+
+func fetchTestWebIdentityToken(ctx context.Context) (string, error) {
+	return "TEST-OIDC-TOKEN", nil
+}
+
+func TestSTSThrottleMonitorCountsThrottles(t *testing.T) {
+	withThrottlingSTSServer(t, 2)
+	monitor := NewSTSThrottleMonitor()
+
+	_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, "arn:aws:iam::123456789012:role/TestRole", "",
+		fetchTestWebIdentityToken, WithSTSThrottleMonitor(monitor), WithSTSRetryer(fixedBackoffRetryer()))
+	assert.NoError(t, err)
+
+	stats := monitor.Stats()
+	assert.Equal(t, int64(2), stats.Throttles)
+	assert.Equal(t, int64(0), stats.ConsecutiveThrottles)
+	assert.Equal(t, time.Duration(0), stats.CurrentBackoff)
+	assert.WithinDuration(t, time.Now(), stats.LastThrottleTime, 5*time.Second)
+}
+
+func TestSTSThrottleMonitorExposesBackoffWhileThrottled(t *testing.T) {
+	withThrottlingSTSServer(t, 10)
+	monitor := NewSTSThrottleMonitor()
+	retryer := retry.AddWithMaxAttempts(fixedBackoffRetryer(), 3)
+
+	_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, "arn:aws:iam::123456789012:role/TestRole", "",
+		fetchTestWebIdentityToken, WithSTSThrottleMonitor(monitor), WithSTSRetryer(retryer))
+	assert.Error(t, err)
+
+	stats := monitor.Stats()
+	assert.Equal(t, int64(3), stats.Throttles)
+	assert.Equal(t, int64(3), stats.ConsecutiveThrottles)
+	assert.GreaterOrEqual(t, stats.CurrentBackoff, 20*time.Millisecond)
+}
+
+func TestProviderSTSThrottleStats(t *testing.T) {
+	provider, _ := newCountingProvider()
+
+	_, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, STSThrottleStats{}, provider.STSThrottleStats())
+}
+
+func TestSTSThrottleMonitorSkipsStacksWithoutRetry(t *testing.T) {
+	stack := middleware.NewStack("TestOperation", nil)
+
+	assert.NoError(t, NewSTSThrottleMonitor().AddMiddleware(stack))
+	assert.Empty(t, stack.Finalize.List())
+}
@@ -0,0 +1,84 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	// SystemdCredentialsDirectoryEnvVar is set by systemd to the directory holding the service credentials.
+	SystemdCredentialsDirectoryEnvVar = "CREDENTIALS_DIRECTORY"
+
+	// SystemdAccessKeyIDCredential is the name of the credential holding the access key id.
+	SystemdAccessKeyIDCredential = "aws_access_key_id"
+
+	// SystemdSecretAccessKeyCredential is the name of the credential holding the secret access key.
+	SystemdSecretAccessKeyCredential = "aws_secret_access_key"
+
+	// SystemdSessionTokenCredential is the name of the optional credential holding the session token.
+	SystemdSessionTokenCredential = "aws_session_token"
+
+	// SystemdCredentialsSource is the source of the credentials retrieved by SystemdCredentialsProvider.
+	SystemdCredentialsSource = "SystemdCredentialsProvider"
+)
+
+// SystemdCredentialsProvider retrieves AWS credentials from the credentials systemd delivers to a service with
+// LoadCredential= or SetCredential=, e.g.
+//
+//	LoadCredential=aws_access_key_id:/etc/kafka-consumer/access-key-id
+//	LoadCredential=aws_secret_access_key:/etc/kafka-consumer/secret-access-key
+//
+// The credential files are read on every retrieval, so wrap the provider in aws.NewCredentialsCache to read them once.
+type SystemdCredentialsProvider struct {
+	// Directory holding the credential files. The CREDENTIALS_DIRECTORY environment variable is used when empty.
+	Directory string
+}
+
+// Retrieve reads the access key id, secret access key and optional session token from the credentials directory.
+func (p SystemdCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	dir := p.Directory
+	if dir == "" {
+		dir = os.Getenv(SystemdCredentialsDirectoryEnvVar)
+	}
+	if dir == "" {
+		return aws.Credentials{}, fmt.Errorf("systemd credentials directory not set, %s is empty",
+			SystemdCredentialsDirectoryEnvVar)
+	}
+
+	accessKeyID, err := readSystemdCredential(dir, SystemdAccessKeyIDCredential)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+
+	secretAccessKey, err := readSystemdCredential(dir, SystemdSecretAccessKeyCredential)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+
+	sessionToken, err := readSystemdCredential(dir, SystemdSessionTokenCredential)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return aws.Credentials{}, err
+	}
+
+	return aws.Credentials{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    sessionToken,
+		Source:          SystemdCredentialsSource,
+	}, nil
+}
+
+// Reads a credential file, trimming the trailing newline editors tend to add.
+func readSystemdCredential(dir string, name string) (string, error) {
+	value, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to read systemd credential %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Writes the credential files into a temporary credentials directory.
+func writeSystemdCredentials(t *testing.T, credentials map[string]string) string {
+	dir := t.TempDir()
+	for name, value := range credentials {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(value), 0o600))
+	}
+	return dir
+}
+
+func TestSystemdCredentialsProvider(t *testing.T) {
+	dir := writeSystemdCredentials(t, map[string]string{
+		SystemdAccessKeyIDCredential:     "TEST-SYSTEMD-ACCESS-KEY\n",
+		SystemdSecretAccessKeyCredential: "TEST-SYSTEMD-SECRET-KEY\n",
+		SystemdSessionTokenCredential:    "TEST-SYSTEMD-SESSION-TOKEN",
+	})
+	t.Setenv(SystemdCredentialsDirectoryEnvVar, dir)
+
+	credentials, err := SystemdCredentialsProvider{}.Retrieve(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-SYSTEMD-ACCESS-KEY", credentials.AccessKeyID)
+	assert.Equal(t, "TEST-SYSTEMD-SECRET-KEY", credentials.SecretAccessKey)
+	assert.Equal(t, "TEST-SYSTEMD-SESSION-TOKEN", credentials.SessionToken)
+	assert.Equal(t, SystemdCredentialsSource, credentials.Source)
+}
+
+func TestSystemdCredentialsProviderWithoutSessionToken(t *testing.T) {
+	dir := writeSystemdCredentials(t, map[string]string{
+		SystemdAccessKeyIDCredential:     "TEST-SYSTEMD-ACCESS-KEY",
+		SystemdSecretAccessKeyCredential: "TEST-SYSTEMD-SECRET-KEY",
+	})
+
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion,
+		SystemdCredentialsProvider{Directory: dir})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+}
+
+func TestSystemdCredentialsProviderMissingSecret(t *testing.T) {
+	dir := writeSystemdCredentials(t, map[string]string{
+		SystemdAccessKeyIDCredential: "TEST-SYSTEMD-ACCESS-KEY",
+	})
+
+	_, err := SystemdCredentialsProvider{Directory: dir}.Retrieve(Ctx)
+
+	assert.ErrorContains(t, err, SystemdSecretAccessKeyCredential)
+}
+
+func TestSystemdCredentialsProviderWithoutDirectory(t *testing.T) {
+	t.Setenv(SystemdCredentialsDirectoryEnvVar, "")
+
+	_, err := SystemdCredentialsProvider{}.Retrieve(Ctx)
+
+	assert.ErrorContains(t, err, SystemdCredentialsDirectoryEnvVar)
+}
@@ -0,0 +1,55 @@
+package signer
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Token is an MSK IAM auth token along with details about its generation.
+type Token struct {
+	// Value is the base64 encoded signed url presented to the broker.
+	Value string
+
+	// ExpirationTimeMs is the expiration time of the token in epoch millis.
+	ExpirationTimeMs int64
+
+	// CredentialFetchDuration is how long it took to retrieve the credentials the token was signed with.
+	CredentialFetchDuration time.Duration
+
+	// SignDuration is how long it took to sign and encode the token.
+	SignDuration time.Duration
+
+	// Region is the region the token was signed for.
+	Region string
+
+	// KeyID is the access key id the token was signed with. It is empty for tokens signed by a RemoteSigner.
+	KeyID string
+
+	// Source is the source of the credentials the token was signed with, as reported by the credentials provider.
+	Source string
+}
+
+// GenerateToken generates an auth token by loading IAM credentials from the credentials provider, or from the default
+// credentials provider chain when credentialsProvider is nil, and returns it with the details of its generation.
+func GenerateToken(
+	ctx context.Context, region string, credentialsProvider aws.CredentialsProvider, optFns ...Option,
+) (*Token, error) {
+	options := resolveOptions(optFns)
+
+	loadCredentials := defaultCredentialsLoader(region, options)
+	if credentialsProvider != nil {
+		loadCredentials = credentialsProviderLoader(credentialsProvider)
+	}
+
+	return generateAuthToken(ctx, region, options, loadCredentials)
+}
+
+// Unpacks the token into the token value and expiration returned by the GenerateAuthToken functions.
+func unpackToken(token *Token, err error) (string, int64, error) {
+	if err != nil {
+		return "", 0, err
+	}
+	return token.Value, token.ExpirationTimeMs, nil
+}
@@ -0,0 +1,78 @@
+package signer
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// TokenEncoding selects the base64 variant the presigned url is encoded with into the auth token.
+type TokenEncoding int
+
+const (
+	// TokenEncodingRawURL encodes the token with the unpadded URL-safe alphabet, the encoding MSK brokers expect.
+	TokenEncodingRawURL TokenEncoding = iota
+
+	// TokenEncodingURL encodes the token with the padded URL-safe alphabet.
+	TokenEncodingURL
+
+	// TokenEncodingStd encodes the token with the padded standard alphabet, as expected by some downstream systems.
+	TokenEncodingStd
+
+	// TokenEncodingRawStd encodes the token with the unpadded standard alphabet.
+	TokenEncodingRawStd
+)
+
+// String returns the name of the encoding.
+func (e TokenEncoding) String() string {
+	switch e {
+	case TokenEncodingRawURL:
+		return "raw-url"
+	case TokenEncodingURL:
+		return "url"
+	case TokenEncodingStd:
+		return "std"
+	case TokenEncodingRawStd:
+		return "raw-std"
+	default:
+		return fmt.Sprintf("TokenEncoding(%d)", int(e))
+	}
+}
+
+// Returns the base64 encoding of the variant, the unpadded URL-safe encoding for unknown variants.
+func (e TokenEncoding) base64() *base64.Encoding {
+	switch e {
+	case TokenEncodingURL:
+		return base64.URLEncoding
+	case TokenEncodingStd:
+		return base64.StdEncoding
+	case TokenEncodingRawStd:
+		return base64.RawStdEncoding
+	default:
+		return base64.RawURLEncoding
+	}
+}
+
+// EventNonDefaultTokenEncoding is logged once per process when tokens are encoded with another variant than
+// TokenEncodingRawURL.
+const EventNonDefaultTokenEncoding = "nondefault_token_encoding"
+
+var warnTokenEncodingOnce sync.Once
+
+// Warns once per process, through the configured logger, that tokens encoded with a non-default variant are not
+// accepted by MSK brokers as is.
+func warnNonDefaultTokenEncoding(ctx context.Context, options Options) {
+	if options.TokenEncoding == TokenEncodingRawURL || options.Logger == nil {
+		return
+	}
+
+	warnTokenEncodingOnce.Do(func() {
+		options.Logger.LogAttrs(ctx, slog.LevelWarn,
+			"msk auth tokens are not raw url base64 encoded, MSK brokers reject them unless re-encoded",
+			slog.String(LogKeyEvent, EventNonDefaultTokenEncoding),
+			slog.String("encoding", options.TokenEncoding.String()),
+		)
+	})
+}
@@ -0,0 +1,57 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAuthTokenWithTokenEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		encoding TokenEncoding
+		decoder  *base64.Encoding
+	}{
+		{TokenEncodingRawURL, base64.RawURLEncoding},
+		{TokenEncodingURL, base64.URLEncoding},
+		{TokenEncodingStd, base64.StdEncoding},
+		{TokenEncodingRawStd, base64.RawStdEncoding},
+	} {
+		t.Run(tc.encoding.String(), func(t *testing.T) {
+			token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+				WithTokenEncoding(tc.encoding))
+			assert.NoError(t, err)
+
+			decoded, err := tc.decoder.DecodeString(token)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(string(decoded), "https://kafka."+TestRegion+".amazonaws.com/"))
+		})
+	}
+}
+
+func TestNonDefaultTokenEncodingWarnsOnce(t *testing.T) {
+	warnTokenEncodingOnce = sync.Once{}
+	var buf bytes.Buffer
+
+	for i := 0; i < 2; i++ {
+		_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+			WithTokenEncoding(TokenEncodingStd), WithJSONLogging(&buf))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, strings.Count(buf.String(), EventNonDefaultTokenEncoding))
+}
+
+func TestDefaultTokenEncodingDoesNotWarn(t *testing.T) {
+	warnTokenEncodingOnce = sync.Once{}
+	var buf bytes.Buffer
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithJSONLogging(&buf))
+
+	assert.NoError(t, err)
+	assert.NotContains(t, buf.String(), EventNonDefaultTokenEncoding)
+}
@@ -0,0 +1,26 @@
+package signer
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Returns the host the auth token is signed for: the endpoint of the options, or the kafka endpoint of the region in
+// the DNS domain of its partition, e.g. kafka.cn-north-1.amazonaws.com.cn, or its FIPS variant when enabled.
+func tokenEndpoint(region string, options Options) (string, error) {
+	if options.Endpoint == "" {
+		template := endpointURLTemplate
+		if fipsEndpointState(options) == aws.FIPSEndpointStateEnabled {
+			template = fipsEndpointURLTemplate
+		}
+		return fmt.Sprintf(template, region, dnsSuffix(region)), nil
+	}
+
+	parsed, err := url.Parse("https://" + options.Endpoint)
+	if err != nil || parsed.Host != options.Endpoint || parsed.Hostname() == "" {
+		return "", fmt.Errorf("invalid endpoint %q, must be a host name optionally followed by a port", options.Endpoint)
+	}
+	return options.Endpoint, nil
+}
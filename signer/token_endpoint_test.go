@@ -0,0 +1,51 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAuthTokenWithEndpoint(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithEndpoint("kafka.my-proxy.internal:8443"))
+	assert.NoError(t, err)
+
+	params, err := ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka.my-proxy.internal:8443", params.Host)
+	assert.Equal(t, TestRegion, params.Region)
+}
+
+func TestTokenEndpointInOtherPartitions(t *testing.T) {
+	for region, expected := range map[string]string{
+		"cn-north-1":     "kafka.cn-north-1.amazonaws.com.cn",
+		"us-gov-west-1":  "kafka.us-gov-west-1.amazonaws.com",
+		"us-iso-east-1":  "kafka.us-iso-east-1.c2s.ic.gov",
+		"us-isob-east-1": "kafka.us-isob-east-1.sc2s.sgov.gov",
+	} {
+		endpoint, err := tokenEndpoint(region, resolveOptions(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, endpoint, region)
+	}
+}
+
+func TestGenerateAuthTokenInChinaPartition(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, "cn-north-1", testQueryCredentialsProvider)
+	assert.NoError(t, err)
+
+	params, err := ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka.cn-north-1.amazonaws.com.cn", params.Host)
+}
+
+func TestTokenEndpoint(t *testing.T) {
+	endpoint, err := tokenEndpoint(TestRegion, resolveOptions(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, TestEndpoint, endpoint)
+
+	for _, invalid := range []string{"https://kafka.internal", "kafka.internal/path", "user@kafka.internal", ":9098"} {
+		_, err := tokenEndpoint(TestRegion, resolveOptions([]Option{WithEndpoint(invalid)}))
+		assert.ErrorContains(t, err, "invalid endpoint", invalid)
+	}
+}
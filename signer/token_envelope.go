@@ -0,0 +1,32 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+)
+
+// TokenEnvelope wraps auth tokens for gateways that expect them carried inside another format, such as a signed JWT
+// with metadata claims, and unwraps them back into the raw token presented to MSK brokers. Auth tokens are not wrapped
+// unless an envelope is set with WithTokenEnvelope.
+type TokenEnvelope interface {
+	// Wrap returns the envelope carrying the raw auth token. The token holds the raw value and its details.
+	Wrap(ctx context.Context, token *Token) (string, error)
+
+	// Unwrap returns the raw auth token carried by the envelope, verifying the envelope as the format requires.
+	Unwrap(ctx context.Context, envelope string) (string, error)
+}
+
+// Wraps the value of the generated token in the envelope of the options, if any. Token validators have already seen
+// the raw value.
+func wrapToken(ctx context.Context, token *Token, options Options) error {
+	if options.TokenEnvelope == nil {
+		return nil
+	}
+
+	wrapped, err := options.TokenEnvelope.Wrap(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to wrap auth token: %w", err)
+	}
+	token.Value = wrapped
+	return nil
+}
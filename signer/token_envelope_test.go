@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Wraps tokens by prefixing them with the region.
+type prefixEnvelope struct{}
+
+func (prefixEnvelope) Wrap(ctx context.Context, token *Token) (string, error) {
+	return token.Region + "|" + token.Value, nil
+}
+
+func (prefixEnvelope) Unwrap(ctx context.Context, envelope string) (string, error) {
+	_, token, ok := strings.Cut(envelope, "|")
+	if !ok {
+		return "", errors.New("not an envelope")
+	}
+	return token, nil
+}
+
+// Fails to wrap tokens.
+type failingEnvelope struct {
+	prefixEnvelope
+}
+
+func (failingEnvelope) Wrap(ctx context.Context, token *Token) (string, error) {
+	return "", errors.New("signing key unavailable")
+}
+
+func TestGenerateAuthTokenWithTokenEnvelope(t *testing.T) {
+	var validated string
+	wrapped, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithTokenEnvelope(prefixEnvelope{}), WithTokenValidators(func(token *Token, _ TokenParams) error {
+			validated = token.Value
+			return nil
+		}))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(wrapped, TestRegion+"|"))
+
+	token, err := prefixEnvelope{}.Unwrap(Ctx, wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, validated, token)
+	params, err := ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, TestRegion, params.Region)
+}
+
+func TestGenerateAuthTokenWithFailingTokenEnvelope(t *testing.T) {
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithTokenEnvelope(failingEnvelope{}))
+
+	assert.ErrorContains(t, err, "failed to wrap auth token: signing key unavailable")
+	assert.Empty(t, token)
+}
@@ -0,0 +1,36 @@
+package signer
+
+import "time"
+
+// Equal reports whether t and other are the same token: the same value and expiration time, signed for the same region
+// with the same access key id. The generation durations and the credentials source are not compared.
+func (t Token) Equal(other Token) bool {
+	return t.Value == other.Value && t.ExpirationTimeMs == other.ExpirationTimeMs && t.Region == other.Region &&
+		t.KeyID == other.KeyID
+}
+
+// Reports whether replacing the previous token with next changes nothing for consumers: the tokens are equal, or were
+// signed for the same region with the same credentials and lifetime within the same minute. Writing out such a token
+// again only churns file-based integrations.
+func isNoOpRefresh(previous *Token, next *Token) bool {
+	if previous == nil || next == nil {
+		return false
+	}
+	if previous.Equal(*next) {
+		return true
+	}
+	if previous.KeyID == "" || previous.KeyID != next.KeyID || previous.Region != next.Region {
+		return false
+	}
+
+	previousParams, err := ParseToken(previous.Value)
+	if err != nil {
+		return false
+	}
+	nextParams, err := ParseToken(next.Value)
+	if err != nil {
+		return false
+	}
+	return previousParams.Expires == nextParams.Expires &&
+		previousParams.SigningTime.Truncate(time.Minute).Equal(nextParams.SigningTime.Truncate(time.Minute))
+}
@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenEqual(t *testing.T) {
+	token := Token{Value: "token", ExpirationTimeMs: 1000, Region: TestRegion, KeyID: "key", Source: "a"}
+
+	assert.True(t, token.Equal(token))
+	assert.True(t, token.Equal(Token{Value: "token", ExpirationTimeMs: 1000, Region: TestRegion, KeyID: "key",
+		Source: "b", SignDuration: time.Second}))
+	assert.False(t, token.Equal(Token{Value: "other", ExpirationTimeMs: 1000, Region: TestRegion, KeyID: "key"}))
+	assert.False(t, token.Equal(Token{Value: "token", ExpirationTimeMs: 2000, Region: TestRegion, KeyID: "key"}))
+}
+
+// Builds a token signed at signingTime with the access key id and the lifetime.
+func tokenSignedAt(signingTime time.Time, keyID string, expires time.Duration) *Token {
+	signedURL := fmt.Sprintf("https://kafka.%s.amazonaws.com/?Action=kafka-cluster%%3AConnect"+
+		"&X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=%s%%2F%s%%2F%s%%2Fkafka-cluster%%2Faws4_request"+
+		"&X-Amz-Date=%s&X-Amz-Expires=%d&X-Amz-SignedHeaders=host&X-Amz-Signature=%x", TestRegion, keyID,
+		signingTime.Format("20060102"), TestRegion, signingTime.Format(SigningTimeFormat), int(expires.Seconds()),
+		signingTime.UnixNano())
+	return &Token{
+		Value:            base64.RawURLEncoding.EncodeToString([]byte(signedURL)),
+		ExpirationTimeMs: signingTime.Add(expires).UnixMilli(),
+		Region:           TestRegion,
+		KeyID:            keyID,
+	}
+}
+
+func TestIsNoOpRefresh(t *testing.T) {
+	signedAt := time.Date(2024, 1, 1, 0, 0, 10, 0, time.UTC)
+	previous := tokenSignedAt(signedAt, "key", 15*time.Minute)
+	sameMinute := tokenSignedAt(signedAt.Add(30*time.Second), "key", 15*time.Minute)
+	nextMinute := tokenSignedAt(signedAt.Add(time.Minute), "key", 15*time.Minute)
+	rotated := tokenSignedAt(signedAt, "rotated", 15*time.Minute)
+	// Signed in the same minute with a lifetime ending in the next minute.
+	longer := tokenSignedAt(signedAt, "key", 15*time.Minute+55*time.Second)
+	// Expiring in the same minute as previous, while signed in the previous minute.
+	earlier := tokenSignedAt(signedAt.Add(-20*time.Second), "key", 15*time.Minute+20*time.Second)
+
+	assert.True(t, isNoOpRefresh(previous, previous))
+	assert.True(t, isNoOpRefresh(previous, sameMinute))
+	assert.False(t, isNoOpRefresh(previous, nextMinute))
+	assert.False(t, isNoOpRefresh(previous, rotated))
+	assert.False(t, isNoOpRefresh(previous, longer))
+	assert.False(t, isNoOpRefresh(previous, earlier))
+	assert.False(t, isNoOpRefresh(previous, &Token{Value: "not a token", Region: TestRegion, KeyID: "key"}))
+	assert.False(t, isNoOpRefresh(nil, previous))
+}
+
+// Returns how many writes the refresh from first to second should have produced, one unless they straddle a minute.
+func expectedWrites(t *testing.T, first *Token, second *Token) int {
+	firstParams, err := ParseToken(first.Value)
+	assert.NoError(t, err)
+	secondParams, err := ParseToken(second.Value)
+	assert.NoError(t, err)
+
+	if !firstParams.SigningTime.Truncate(time.Minute).Equal(secondParams.SigningTime.Truncate(time.Minute)) {
+		return 2
+	}
+	return 1
+}
+
+func TestProviderSkipsStreamingNoOpRefresh(t *testing.T) {
+	var buf bytes.Buffer
+	provider, _ := newCountingProvider(WithTokenWriter(&buf))
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	second, err := provider.ForceRefresh(Ctx)
+	assert.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, expectedWrites(t, first, second))
+}
+
+func TestRefreshOnSignalSkipsNoOpTokenFileWrite(t *testing.T) {
+	provider, _ := newCountingProvider()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	ctx, cancel := context.WithCancel(Ctx)
+	received := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		refreshOnSignals(ctx, provider, tokenFile, received)
+	}()
+
+	start := time.Now()
+	received <- syscall.SIGHUP
+	received <- syscall.SIGHUP
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("replaced"), 0o600))
+	received <- syscall.SIGHUP
+	cancel()
+	<-done
+
+	if !time.Now().Truncate(time.Minute).Equal(start.Truncate(time.Minute)) {
+		t.Skip("refreshes straddled a minute")
+	}
+	written, err := os.ReadFile(tokenFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "replaced", string(written))
+}
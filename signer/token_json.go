@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// JSON document of a token, described by the token.schema.json JSON schema.
+type tokenJSON struct {
+	Value     string `json:"value"`
+	ExpiresAt string `json:"expiresAt"`
+	Region    string `json:"region,omitempty"`
+	KeyID     string `json:"keyId,omitempty"`
+	Source    string `json:"source,omitempty"`
+}
+
+// MarshalJSON encodes the token as the stable JSON document emitted by every component of the signer, described by
+// the token.schema.json JSON schema:
+//
+//	{"value": "...", "expiresAt": "2024-01-01T00:15:00Z", "region": "us-west-2", "keyId": "ASIA...", "source": "..."}
+//
+// The generation durations are not part of the document.
+func (t Token) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tokenJSON{
+		Value:     t.Value,
+		ExpiresAt: t.Expiry(0).UTC().Format(time.RFC3339),
+		Region:    t.Region,
+		KeyID:     t.KeyID,
+		Source:    t.Source,
+	})
+}
+
+// UnmarshalJSON decodes the JSON document produced by MarshalJSON.
+func (t *Token) UnmarshalJSON(data []byte) error {
+	var doc tokenJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, doc.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("invalid token expiresAt: %w", err)
+	}
+
+	*t = Token{
+		Value:            doc.Value,
+		ExpirationTimeMs: expiresAt.UnixMilli(),
+		Region:           doc.Region,
+		KeyID:            doc.KeyID,
+		Source:           doc.Source,
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package signer
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenMarshalJSON(t *testing.T) {
+	token := Token{
+		Value:            "TEST-TOKEN-VALUE",
+		ExpirationTimeMs: time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC).UnixMilli(),
+		SignDuration:     time.Millisecond,
+		Region:           TestRegion,
+		KeyID:            "TEST-ACCESS-KEY",
+		Source:           "EnvConfigCredentials",
+	}
+
+	data, err := json.Marshal(token)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"value": "TEST-TOKEN-VALUE",
+		"expiresAt": "2024-01-01T00:15:00Z",
+		"region": "us-west-2",
+		"keyId": "TEST-ACCESS-KEY",
+		"source": "EnvConfigCredentials"
+	}`, string(data))
+}
+
+func TestTokenUnmarshalJSON(t *testing.T) {
+	var token Token
+
+	err := json.Unmarshal([]byte(`{"value":"TEST-TOKEN-VALUE","expiresAt":"2024-01-01T00:15:00Z","region":"us-west-2"}`),
+		&token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-TOKEN-VALUE", token.Value)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC).UnixMilli(), token.ExpirationTimeMs)
+	assert.Equal(t, TestRegion, token.Region)
+}
+
+func TestTokenUnmarshalJSONInvalidExpiry(t *testing.T) {
+	var token Token
+
+	err := json.Unmarshal([]byte(`{"value":"TEST-TOKEN-VALUE","expiresAt":"tomorrow"}`), &token)
+
+	assert.ErrorContains(t, err, "invalid token expiresAt")
+}
+
+func TestTokenSchemaMatchesJSON(t *testing.T) {
+	data, err := os.ReadFile("token.schema.json")
+	assert.NoError(t, err)
+	var schema struct {
+		Properties map[string]interface{} `json:"properties"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &schema))
+
+	tokenJSON, err := json.Marshal(Token{Value: "v", Region: "r", KeyID: "k", Source: "s"})
+	assert.NoError(t, err)
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(tokenJSON, &doc))
+
+	assert.Equal(t, sortedKeys(schema.Properties), sortedKeys(doc))
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestGeneratedTokenDetails(t *testing.T) {
+	provider, _ := newCountingProvider()
+
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, TestRegion, token.Region)
+	assert.Equal(t, "TEST-PROVIDER-ACCESS-KEY", token.KeyID)
+}
@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// ID of the middleware setting the auth token header.
+const tokenMiddlewareID = "MSKIAMAuthToken"
+
+// TokenMiddleware authenticates the requests of aws-sdk-go-v2 clients, e.g. clients generated for REST based Kafka
+// proxies fronting MSK, with the auth token of a TokenProvider. It is the smithy middleware counterpart of
+// TokenTransport, sharing the token cache and refresh of a Provider with the Kafka clients of the service:
+//
+//	client := kafkarest.NewFromConfig(cfg, func(o *kafkarest.Options) {
+//		o.APIOptions = append(o.APIOptions, (&signer.TokenMiddleware{Provider: provider}).AddMiddleware)
+//	})
+type TokenMiddleware struct {
+	// Provider supplies the token of every request attempt. It is required.
+	Provider TokenProvider
+
+	// Header is the request header carrying the token. DefaultTokenHeader is used when empty.
+	Header string
+
+	// Scheme prefixes the token in the header, separated by a space. DefaultTokenScheme is used when empty, and "-"
+	// sets the bare token.
+	Scheme string
+}
+
+// AddMiddleware adds the middleware setting the token header at the end of the finalize step of the stack, after the
+// retry and signing middleware, so every attempt carries a current token. Clients authenticating with the default
+// Authorization header should use anonymous credentials, the header would otherwise replace their SigV4 signature.
+func (m *TokenMiddleware) AddMiddleware(stack *middleware.Stack) error {
+	return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc(tokenMiddlewareID, m.handleFinalize), middleware.After)
+}
+
+// Sets the token header of the request attempt.
+func (m *TokenMiddleware) handleFinalize(
+	ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+) (middleware.FinalizeOutput, middleware.Metadata, error) {
+	req, ok := in.Request.(*smithyhttp.Request)
+	if !ok {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, fmt.Errorf("unexpected request type %T", in.Request)
+	}
+	if m.Provider == nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, errors.New("token middleware provider cannot be nil")
+	}
+
+	token, err := m.Provider.Token(ctx)
+	if err != nil {
+		return middleware.FinalizeOutput{}, middleware.Metadata{}, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set(tokenHeader(m.Header), tokenHeaderValue(m.Scheme, token.Value))
+	return next.HandleFinalize(ctx, in)
+}
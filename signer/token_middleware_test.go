@@ -0,0 +1,69 @@
+package signer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns an sts client with anonymous credentials sending its requests to the server, authenticated by the middleware.
+func newTokenMiddlewareClient(url string, m *TokenMiddleware) *sts.Client {
+	return sts.NewFromConfig(aws.Config{
+		Region:       TestRegion,
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(url),
+		APIOptions:   []func(*middleware.Stack) error{m.AddMiddleware},
+	})
+}
+
+func TestTokenMiddleware(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(DefaultTokenHeader))
+		fmt.Fprint(w, `<GetCallerIdentityResponse><GetCallerIdentityResult><Account>123456789012</Account>`+
+			`</GetCallerIdentityResult></GetCallerIdentityResponse>`)
+	}))
+	t.Cleanup(server.Close)
+	client := newTokenMiddlewareClient(server.URL, &TokenMiddleware{
+		Provider: StaticTokenProvider("test-token", time.Now().Add(time.Hour)),
+	})
+
+	_, err := client.GetCallerIdentity(Ctx, &sts.GetCallerIdentityInput{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bearer test-token"}, received)
+}
+
+func TestTokenMiddlewareWithCustomHeader(t *testing.T) {
+	server, received := newHeaderRecordingServer(t, "X-MSK-Token")
+	client := newTokenMiddlewareClient(server.URL, &TokenMiddleware{
+		Provider: StaticTokenProvider("test-token", time.Now().Add(time.Hour)),
+		Header:   "X-MSK-Token",
+		Scheme:   "-",
+	})
+
+	_, _ = client.GetCallerIdentity(Ctx, &sts.GetCallerIdentityInput{})
+
+	assert.Equal(t, "test-token", *received)
+}
+
+func TestTokenMiddlewareProviderError(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	t.Cleanup(server.Close)
+	client := newTokenMiddlewareClient(server.URL, &TokenMiddleware{Provider: failingTokenProvider{}})
+
+	_, err := client.GetCallerIdentity(Ctx, &sts.GetCallerIdentityInput{})
+
+	assert.ErrorContains(t, err, "failed to get auth token: no credentials")
+	assert.Zero(t, requests)
+}
@@ -0,0 +1,68 @@
+package signer
+
+import (
+	"context"
+	"time"
+)
+
+// TokenPair is the cached token of a Provider along with the next token generated during the overlap window.
+type TokenPair struct {
+	// Current is the cached token, which is used until the refresh strategy replaces it.
+	Current *Token
+
+	// Next is the token replacing Current, generated during the overlap window. It is nil outside the window.
+	Next *Token
+}
+
+// Newest returns the next token when one was generated, otherwise the current one.
+func (p TokenPair) Newest() *Token {
+	if p.Next != nil {
+		return p.Next
+	}
+	return p.Current
+}
+
+// Tokens returns the cached token and, within the overlap window configured with WithTokenOverlap, the next token
+// that replaces it. The next token is generated once on the first call in the window. Failing to generate it leaves
+// Next nil, the current token remains usable and generation is retried on the next call.
+func (p *Provider) Tokens(ctx context.Context) (TokenPair, error) {
+	if err := p.acquireGeneration(ctx); err != nil {
+		return TokenPair{}, err
+	}
+	current, err := p.cachedOrNewTokenGenerating(ctx)
+	if err != nil {
+		p.releaseGeneration()
+		return TokenPair{}, err
+	}
+
+	pair := TokenPair{Current: current, Next: p.nextToken(ctx)}
+	p.releaseGeneration()
+
+	p.observeTokenTTL(pair.Newest())
+	return pair, nil
+}
+
+// Returns the next token, generating it once the overlap window before the cached token is replaced has started. The
+// caller holds the token generation.
+func (p *Provider) nextToken(ctx context.Context) *Token {
+	p.mu.Lock()
+	inWindow := p.options.TokenOverlap > 0 && !time.Now().Before(p.refreshAt.Add(-p.options.TokenOverlap))
+	next := p.next
+	p.mu.Unlock()
+	if !inWindow {
+		return nil
+	}
+	if next != nil {
+		return next
+	}
+
+	next, refreshAt, err := p.generateToken(ctx, p.loadCredentials)
+	if err != nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	p.next, p.nextRefreshAt = next, refreshAt
+	p.mu.Unlock()
+	return next
+}
@@ -0,0 +1,74 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderTokensOutsideOverlapWindow(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider(WithTokenOverlap(time.Minute))
+
+	pair, err := provider.Tokens(Ctx)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, pair.Current)
+	assert.Nil(t, pair.Next)
+	assert.Same(t, pair.Current, pair.Newest())
+	assert.Equal(t, 1, credentialsProvider.calls)
+}
+
+func TestProviderTokensWithinOverlapWindow(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider(WithTokenOverlap(time.Minute))
+
+	current, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	provider.refreshAt = time.Now().Add(30 * time.Second)
+
+	pair, err := provider.Tokens(Ctx)
+	assert.NoError(t, err)
+	assert.Same(t, current, pair.Current)
+	assert.NotNil(t, pair.Next)
+	assert.Same(t, pair.Next, pair.Newest())
+
+	again, err := provider.Tokens(Ctx)
+	assert.NoError(t, err)
+	assert.Same(t, pair.Next, again.Next)
+	assert.Equal(t, 2, credentialsProvider.calls)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.Same(t, current, token)
+}
+
+func TestProviderPromotesNextToken(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider(WithTokenOverlap(time.Minute))
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	provider.refreshAt = time.Now().Add(30 * time.Second)
+	pair, err := provider.Tokens(Ctx)
+	assert.NoError(t, err)
+
+	provider.refreshAt = time.Now().Add(-time.Second)
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Same(t, pair.Next, token)
+	assert.Nil(t, provider.next)
+	assert.Equal(t, 2, credentialsProvider.calls)
+}
+
+func TestProviderTokensWithoutOverlap(t *testing.T) {
+	provider, credentialsProvider := newCountingProvider()
+
+	_, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	provider.refreshAt = time.Now().Add(time.Second)
+
+	pair, err := provider.Tokens(Ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, pair.Next)
+	assert.Equal(t, 1, credentialsProvider.calls)
+}
@@ -0,0 +1,162 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueryKey is a query parameter of the presigned url carried by an auth token.
+type QueryKey string
+
+// The query parameters the signer emits. The X-Amz-* keys are set by sig v4 presigning.
+const (
+	QueryKeyAction        QueryKey = ActionType             // QueryKeyAction holds the kafka-cluster:Connect action.
+	QueryKeyUserAgent     QueryKey = UserAgentKey           // QueryKeyUserAgent holds the user agent of the signer.
+	QueryKeyAlgorithm     QueryKey = "X-Amz-Algorithm"      // QueryKeyAlgorithm holds the signing algorithm.
+	QueryKeyCredential    QueryKey = "X-Amz-Credential"     // QueryKeyCredential holds the access key id and scope.
+	QueryKeyDate          QueryKey = "X-Amz-Date"           // QueryKeyDate holds the signing time.
+	QueryKeyExpires       QueryKey = ExpiresQueryKey        // QueryKeyExpires holds the lifetime in seconds.
+	QueryKeySecurityToken QueryKey = "X-Amz-Security-Token" // QueryKeySecurityToken holds the session token.
+	QueryKeySignedHeaders QueryKey = "X-Amz-SignedHeaders"  // QueryKeySignedHeaders holds the signed header names.
+	QueryKeySignature     QueryKey = "X-Amz-Signature"      // QueryKeySignature holds the signature.
+)
+
+// SigningTimeFormat is the layout of the signing time in QueryKeyDate.
+const SigningTimeFormat = "20060102T150405Z"
+
+// Get returns the first value of the query parameter in the query, or an empty string.
+func (k QueryKey) Get(query url.Values) string {
+	return query.Get(string(k))
+}
+
+// Matches reports whether the query parameter key is k, ignoring case.
+func (k QueryKey) Matches(key string) bool {
+	return strings.EqualFold(key, string(k))
+}
+
+// TokenParams are the query parameters of an auth token, parsed into their typed values.
+type TokenParams struct {
+	// Host is the host the url was signed for, e.g. kafka.us-west-2.amazonaws.com.
+	Host string
+
+	// Action is the action the token grants, kafka-cluster:Connect.
+	Action string
+
+	// UserAgent is the user agent of the signer that generated the token.
+	UserAgent string
+
+	// Algorithm is the signing algorithm, AWS4-HMAC-SHA256.
+	Algorithm string
+
+	// AccessKeyID is the access key id the token was signed with.
+	AccessKeyID string
+
+	// CredentialDate is the date of the credential scope, formatted as 20060102.
+	CredentialDate string
+
+	// Region is the region of the credential scope.
+	Region string
+
+	// Service is the signing name of the credential scope, kafka-cluster.
+	Service string
+
+	// SigningTime is the time the token was signed at.
+	SigningTime time.Time
+
+	// Expires is the lifetime of the token.
+	Expires time.Duration
+
+	// SecurityToken is the session token of temporary credentials, empty for long-term credentials.
+	SecurityToken string
+
+	// SignedHeaders are the names of the signed headers.
+	SignedHeaders []string
+
+	// Signature is the hex encoded signature.
+	Signature string
+
+	// Extra holds the query parameters that are not listed above, such as the parameters added with
+	// WithSignedQueryParameter.
+	Extra url.Values
+}
+
+// ExpiresAt returns the time the token expires at.
+func (p TokenParams) ExpiresAt() time.Time {
+	return ComputeExpiry(p.SigningTime, p.Expires, 0)
+}
+
+// ParseToken decodes the auth token, in any of the TokenEncoding variants, and parses the query parameters of its
+// presigned url.
+func ParseToken(token string) (TokenParams, error) {
+	encodings := []TokenEncoding{TokenEncodingRawURL, TokenEncodingURL, TokenEncodingStd, TokenEncodingRawStd}
+	for _, encoding := range encodings {
+		decoded, err := encoding.base64().DecodeString(token)
+		if err == nil {
+			return ParseSignedURL(string(decoded))
+		}
+	}
+	return TokenParams{}, errors.New("auth token is not base64 encoded")
+}
+
+// ParseSignedURL parses the query parameters of the presigned url of an auth token. The sig v4 parameters are
+// required, the others are left empty when missing.
+func ParseSignedURL(signedURL string) (TokenParams, error) {
+	parsedURL, err := url.Parse(signedURL)
+	if err != nil {
+		return TokenParams{}, fmt.Errorf("failed to parse the signed url: %w", err)
+	}
+
+	query := parsedURL.Query()
+	params := TokenParams{
+		Host:          parsedURL.Host,
+		Action:        QueryKeyAction.Get(query),
+		UserAgent:     QueryKeyUserAgent.Get(query),
+		Algorithm:     QueryKeyAlgorithm.Get(query),
+		SecurityToken: QueryKeySecurityToken.Get(query),
+		Signature:     QueryKeySignature.Get(query),
+		Extra:         url.Values{},
+	}
+
+	credential := strings.Split(QueryKeyCredential.Get(query), "/")
+	if len(credential) != 5 {
+		return TokenParams{}, fmt.Errorf("malformed '%s' param in signed url", QueryKeyCredential)
+	}
+	params.AccessKeyID, params.CredentialDate, params.Region, params.Service =
+		credential[0], credential[1], credential[2], credential[3]
+
+	params.SigningTime, err = time.Parse(SigningTimeFormat, QueryKeyDate.Get(query))
+	if err != nil {
+		return TokenParams{}, fmt.Errorf("failed to parse the '%s' param from signed url: %w", QueryKeyDate, err)
+	}
+
+	expires, err := strconv.ParseInt(QueryKeyExpires.Get(query), 10, 64)
+	if err != nil {
+		return TokenParams{}, fmt.Errorf("failed to parse the '%s' param from signed url: %w", QueryKeyExpires, err)
+	}
+	params.Expires = time.Duration(expires) * time.Second
+
+	if signedHeaders := QueryKeySignedHeaders.Get(query); signedHeaders != "" {
+		params.SignedHeaders = strings.Split(signedHeaders, ";")
+	}
+
+	for key, values := range query {
+		if !isTokenParamsKey(key) {
+			params.Extra[key] = values
+		}
+	}
+	return params, nil
+}
+
+// Reports whether the query parameter is parsed into a field of TokenParams.
+func isTokenParamsKey(key string) bool {
+	switch QueryKey(key) {
+	case QueryKeyAction, QueryKeyUserAgent, QueryKeyAlgorithm, QueryKeyCredential, QueryKeyDate, QueryKeyExpires,
+		QueryKeySecurityToken, QueryKeySignedHeaders, QueryKeySignature:
+		return true
+	}
+	return false
+}
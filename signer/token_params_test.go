@@ -0,0 +1,87 @@
+package signer
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseToken(t *testing.T) {
+	credentialsProvider := MockCredentialsProvider{credentials: aws.Credentials{
+		AccessKeyID:     "TEST-PARSE-ACCESS-KEY",
+		SecretAccessKey: "TEST-PARSE-SECRET-KEY",
+		SessionToken:    "TEST-PARSE-SESSION-TOKEN",
+	}}
+	token, err := GenerateToken(Ctx, TestRegion, credentialsProvider,
+		WithSignedQueryParameter("team", "payments"))
+	assert.NoError(t, err)
+
+	params, err := ParseToken(token.Value)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka.us-west-2.amazonaws.com", params.Host)
+	assert.Equal(t, ActionName, params.Action)
+	assert.Contains(t, params.UserAgent, LibName)
+	assert.Equal(t, "AWS4-HMAC-SHA256", params.Algorithm)
+	assert.Equal(t, "TEST-PARSE-ACCESS-KEY", params.AccessKeyID)
+	assert.Equal(t, params.SigningTime.Format("20060102"), params.CredentialDate)
+	assert.Equal(t, TestRegion, params.Region)
+	assert.Equal(t, SigningName, params.Service)
+	assert.Equal(t, DefaultExpirySeconds*time.Second, params.Expires)
+	assert.Equal(t, "TEST-PARSE-SESSION-TOKEN", params.SecurityToken)
+	assert.Equal(t, []string{"host"}, params.SignedHeaders)
+	assert.Len(t, params.Signature, 64)
+	assert.Equal(t, url.Values{"team": {"payments"}}, params.Extra)
+	assert.Equal(t, token.ExpirationTimeMs, params.ExpiresAt().UnixMilli())
+}
+
+func TestParseTokenAcceptsEncodings(t *testing.T) {
+	token, err := GenerateToken(Ctx, TestRegion, testQueryCredentialsProvider, WithTokenEncoding(TokenEncodingStd))
+	assert.NoError(t, err)
+
+	params, err := ParseToken(token.Value)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-QUERY-ACCESS-KEY", params.AccessKeyID)
+}
+
+func TestParseTokenFailures(t *testing.T) {
+	_, err := ParseToken("not base64!")
+	assert.ErrorContains(t, err, "not base64 encoded")
+
+	encode := func(signedURL string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(signedURL))
+	}
+	_, err = ParseToken(encode("https://kafka.us-west-2.amazonaws.com/?X-Amz-Credential=key"))
+	assert.ErrorContains(t, err, "malformed 'X-Amz-Credential' param")
+
+	_, err = ParseToken(encode("https://kafka.us-west-2.amazonaws.com/" +
+		"?X-Amz-Credential=key%2F20240101%2Fus-west-2%2Fkafka-cluster%2Faws4_request&X-Amz-Date=2024"))
+	assert.ErrorContains(t, err, "failed to parse the 'X-Amz-Date' param")
+}
+
+func TestQueryKey(t *testing.T) {
+	query := url.Values{"X-Amz-Date": {"20240101T000000Z"}}
+
+	assert.Equal(t, "20240101T000000Z", QueryKeyDate.Get(query))
+	assert.Empty(t, QueryKeySignature.Get(query))
+	assert.True(t, QueryKeySignature.Matches("x-amz-signature"))
+	assert.False(t, QueryKeySignature.Matches("X-Amz-Signed"))
+}
+
+func TestQueryKeyWireNames(t *testing.T) {
+	assert.Equal(t, QueryKey("Action"), QueryKeyAction)
+	assert.Equal(t, QueryKey("User-Agent"), QueryKeyUserAgent)
+	assert.Equal(t, QueryKey("X-Amz-Algorithm"), QueryKeyAlgorithm)
+	assert.Equal(t, QueryKey("X-Amz-Credential"), QueryKeyCredential)
+	assert.Equal(t, QueryKey("X-Amz-Date"), QueryKeyDate)
+	assert.Equal(t, QueryKey("X-Amz-Expires"), QueryKeyExpires)
+	assert.Equal(t, QueryKey("X-Amz-Security-Token"), QueryKeySecurityToken)
+	assert.Equal(t, QueryKey("X-Amz-SignedHeaders"), QueryKeySignedHeaders)
+	assert.Equal(t, QueryKey("X-Amz-Signature"), QueryKeySignature)
+	assert.Equal(t, "20060102T150405Z", SigningTimeFormat)
+}
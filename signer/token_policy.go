@@ -0,0 +1,68 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrTokenPolicyViolation is returned when a TokenValidator rejects a generated auth token.
+var ErrTokenPolicyViolation = errors.New("auth token violates policy")
+
+// TokenValidator inspects a generated auth token and its decoded parameters before it is returned, rejecting it with
+// a non-nil error, e.g. to enforce organization policies on the token lifetime or the signing keys.
+type TokenValidator func(token *Token, params TokenParams) error
+
+// MaxTokenLifetime returns a validator rejecting tokens signed for longer than maxLifetime.
+func MaxTokenLifetime(maxLifetime time.Duration) TokenValidator {
+	return func(_ *Token, params TokenParams) error {
+		if params.Expires > maxLifetime {
+			return fmt.Errorf("token lifetime %s exceeds %s", params.Expires, maxLifetime)
+		}
+		return nil
+	}
+}
+
+// AccessKeyIDPrefix returns a validator rejecting tokens signed with an access key id not starting with one of the
+// prefixes, e.g. "ASIA" to only accept temporary credentials issued by sts.
+func AccessKeyIDPrefix(prefixes ...string) TokenValidator {
+	return func(_ *Token, params TokenParams) error {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(params.AccessKeyID, prefix) {
+				return nil
+			}
+		}
+		return fmt.Errorf("access key %s does not start with any of %v", shortenKeyID(params.AccessKeyID), prefixes)
+	}
+}
+
+// AccessKeyIDMatching returns a validator rejecting tokens signed with an access key id not matching the pattern.
+// The access key id is the only principal identifier carried by the token.
+func AccessKeyIDMatching(pattern *regexp.Regexp) TokenValidator {
+	return func(_ *Token, params TokenParams) error {
+		if !pattern.MatchString(params.AccessKeyID) {
+			return fmt.Errorf("access key %s does not match %s", shortenKeyID(params.AccessKeyID), pattern)
+		}
+		return nil
+	}
+}
+
+// Runs the validators of the options on the generated token, in order, stopping at the first rejection.
+func validateToken(token *Token, options Options) error {
+	if len(options.TokenValidators) == 0 {
+		return nil
+	}
+
+	params, err := ParseToken(token.Value)
+	if err != nil {
+		return fmt.Errorf("failed to decode auth token for validation: %w", err)
+	}
+	for i, validate := range options.TokenValidators {
+		if err := validate(token, params); err != nil {
+			return fmt.Errorf("%w: validator %d: %w", ErrTokenPolicyViolation, i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package signer
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenValidatorsAcceptToken(t *testing.T) {
+	var validated TokenParams
+	token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithTokenValidators(MaxTokenLifetime(15*time.Minute), AccessKeyIDPrefix("TEST-"),
+			AccessKeyIDMatching(regexp.MustCompile(`^TEST-[A-Z-]+$`)),
+			func(token *Token, params TokenParams) error {
+				validated = params
+				return nil
+			}))
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, "TEST-QUERY-ACCESS-KEY", validated.AccessKeyID)
+	assert.Equal(t, TestRegion, validated.Region)
+}
+
+func TestTokenValidatorsRejectToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator TokenValidator
+		message   string
+	}{
+		{"lifetime", MaxTokenLifetime(5 * time.Minute), "validator 0: token lifetime 15m0s exceeds 5m0s"},
+		{"prefix", AccessKeyIDPrefix("ASIA"), "validator 0: access key TEST...-KEY does not start with any of [ASIA]"},
+		{"pattern", AccessKeyIDMatching(regexp.MustCompile(`^AKIA`)), "validator 0: access key TEST...-KEY does not match"},
+		{"custom", func(*Token, TokenParams) error { return errors.New("denied") }, "validator 0: denied"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			token, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+				WithTokenValidators(test.validator))
+
+			assert.ErrorIs(t, err, ErrTokenPolicyViolation)
+			assert.ErrorContains(t, err, test.message)
+			assert.Empty(t, token)
+		})
+	}
+}
+
+func TestTokenValidatorsStopAtFirstRejection(t *testing.T) {
+	called := false
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, testQueryCredentialsProvider,
+		WithTokenValidators(MaxTokenLifetime(time.Hour), AccessKeyIDPrefix("ASIA")),
+		WithTokenValidators(func(*Token, TokenParams) error {
+			called = true
+			return nil
+		}))
+
+	assert.ErrorContains(t, err, "validator 1:")
+	assert.False(t, called)
+}
+
+func TestProviderDoesNotCacheRejectedTokens(t *testing.T) {
+	rejections := 1
+	provider, credentials := newCountingProvider(WithTokenValidators(func(*Token, TokenParams) error {
+		if rejections > 0 {
+			rejections--
+			return errors.New("denied")
+		}
+		return nil
+	}))
+
+	_, err := provider.Token(Ctx)
+	assert.ErrorIs(t, err, ErrTokenPolicyViolation)
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.Equal(t, 2, credentials.calls)
+}
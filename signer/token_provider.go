@@ -0,0 +1,36 @@
+package signer
+
+import (
+	"context"
+	"time"
+)
+
+// TokenProvider supplies auth tokens to Kafka clients. Provider implements it, and NopProvider, StaticTokenProvider
+// and the providers of NewDryRunProvider stand in for it in tests and dry-run modes.
+type TokenProvider interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+var _ TokenProvider = (*Provider)(nil)
+
+// NopProvider is a TokenProvider returning an empty token without signing anything or loading credentials.
+type NopProvider struct{}
+
+// Token returns an empty token.
+func (NopProvider) Token(ctx context.Context) (*Token, error) {
+	return &Token{}, nil
+}
+
+// StaticTokenProvider returns a TokenProvider always returning the given token value and expiry.
+func StaticTokenProvider(value string, expiry time.Time) TokenProvider {
+	return staticTokenProvider{value: value, expirationTimeMs: expiry.UnixMilli()}
+}
+
+type staticTokenProvider struct {
+	value            string
+	expirationTimeMs int64
+}
+
+func (s staticTokenProvider) Token(ctx context.Context) (*Token, error) {
+	return &Token{Value: s.value, ExpirationTimeMs: s.expirationTimeMs}, nil
+}
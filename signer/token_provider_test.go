@@ -0,0 +1,33 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNopProvider(t *testing.T) {
+	var provider TokenProvider = NopProvider{}
+
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.Empty(t, token.Value)
+	assert.Zero(t, token.ExpirationTimeMs)
+}
+
+func TestStaticTokenProvider(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	provider := StaticTokenProvider("TEST-STATIC-TOKEN", expiry)
+
+	first, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-STATIC-TOKEN", first.Value)
+	assert.Equal(t, expiry.UnixMilli(), first.ExpirationTimeMs)
+
+	first.Value = "TEST-MODIFIED-TOKEN"
+	second, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-STATIC-TOKEN", second.Value)
+}
@@ -0,0 +1,71 @@
+//go:build go1.23
+
+package signer
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+const (
+	// How long Rotations waits before retrying after a failed generation or a stale token.
+	rotationRetryInterval = 5 * time.Second
+
+	// Shortest wait between two checks of Rotations, guarding against busy loops on tokens already due for refresh.
+	minRotationWait = 100 * time.Millisecond
+)
+
+// Rotations returns an iterator over the tokens of the provider: the current token first, then every new token as
+// the refresh strategy replaces it, until ctx is done or the loop breaks. Generation errors are yielded with a nil
+// token and retried after a few seconds; a stale token served by the fallback is not yielded again. Tokens come from
+// the cache shared with Token, so ranging over rotations generates no extra tokens:
+//
+//	for token, err := range provider.Rotations(ctx) {
+//		if err != nil {
+//			log.Print(err)
+//			continue
+//		}
+//		reauthenticate(token)
+//	}
+func (p *Provider) Rotations(ctx context.Context) iter.Seq2[*Token, error] {
+	return func(yield func(*Token, error) bool) {
+		var last *Token
+		for ctx.Err() == nil {
+			wait := rotationRetryInterval
+			token, err := p.Token(ctx)
+			switch {
+			case err != nil:
+				if ctx.Err() != nil || !yield(nil, err) {
+					return
+				}
+			case token != last:
+				if !yield(token, nil) {
+					return
+				}
+				last = token
+				fallthrough
+			default:
+				if refreshIn := time.Until(p.refreshTime()); refreshIn > 0 {
+					wait = refreshIn
+				}
+			}
+
+			timer := time.NewTimer(max(wait, minRotationWait))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// Returns when the cached token is due to be replaced.
+func (p *Provider) refreshTime() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.refreshAt
+}
@@ -0,0 +1,71 @@
+//go:build go1.23
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Replaces tokens a fixed time after they were issued.
+type fixedRefreshStrategy struct {
+	after time.Duration
+}
+
+func (s fixedRefreshStrategy) RefreshAt(issuedAt time.Time, token *Token) time.Time {
+	return issuedAt.Add(s.after)
+}
+
+func TestProviderRotations(t *testing.T) {
+	provider, _ := newCountingProvider(WithRefreshStrategy(fixedRefreshStrategy{after: 100 * time.Millisecond}))
+
+	var tokens []*Token
+	start := time.Now()
+	for token, err := range provider.Rotations(Ctx) {
+		assert.NoError(t, err)
+		tokens = append(tokens, token)
+		if len(tokens) == 3 {
+			break
+		}
+	}
+
+	assert.Len(t, tokens, 3)
+	assert.NotSame(t, tokens[0], tokens[1])
+	assert.NotSame(t, tokens[1], tokens[2])
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.Equal(t, int64(3), provider.Stats().Refreshes)
+}
+
+func TestProviderRotationsYieldsErrors(t *testing.T) {
+	provider, _ := newCountingProvider(WithTokenValidators(func(*Token, TokenParams) error {
+		return errors.New("denied")
+	}))
+
+	next, stop := iter.Pull2(provider.Rotations(Ctx))
+	defer stop()
+	token, err, ok := next()
+
+	assert.True(t, ok)
+	assert.Nil(t, token)
+	assert.ErrorIs(t, err, ErrTokenPolicyViolation)
+}
+
+func TestProviderRotationsEndWithContext(t *testing.T) {
+	provider, _ := newCountingProvider()
+	ctx, cancel := context.WithCancel(Ctx)
+	defer cancel()
+
+	yielded := 0
+	for _, err := range provider.Rotations(ctx) {
+		assert.NoError(t, err)
+		yielded++
+		cancel()
+	}
+
+	assert.Equal(t, 1, yielded)
+}
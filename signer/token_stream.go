@@ -0,0 +1,22 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Writes the new token to the configured token writer as a JSON line in the format of Token.MarshalJSON. Failures are
+// logged rather than returned, so a closed pipe doesn't keep the Kafka client from getting its token.
+func writeTokenToStream(ctx context.Context, options Options, token *Token) {
+	if options.TokenWriter == nil {
+		return
+	}
+
+	line, err := json.Marshal(token)
+	if err == nil {
+		_, err = options.TokenWriter.Write(append(line, '\n'))
+	}
+	if err != nil {
+		logTokenWriteFailed(ctx, options.Logger, err)
+	}
+}
@@ -0,0 +1,47 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Writer failing every write, like a pipe whose reader went away.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestProviderWritesNewTokensToStream(t *testing.T) {
+	var buf bytes.Buffer
+	provider, _ := newCountingProvider(WithTokenWriter(&buf))
+
+	token, err := provider.Token(Ctx)
+	assert.NoError(t, err)
+	_, err = provider.Token(Ctx)
+	assert.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 1)
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(lines[0], &line))
+	assert.Equal(t, token.Value, line["value"])
+	assert.Equal(t, TestRegion, line["region"])
+}
+
+func TestProviderTokenStreamFailureIsLogged(t *testing.T) {
+	var logs bytes.Buffer
+	provider, _ := newCountingProvider(WithTokenWriter(failingWriter{}), WithJSONLogging(&logs))
+
+	token, err := provider.Token(Ctx)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.Contains(t, logs.String(), EventTokenWriteFailed)
+	assert.Contains(t, logs.String(), "broken pipe")
+}
@@ -0,0 +1,47 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTokenWithCredentialsProvider(t *testing.T) {
+	mockCreds := aws.Credentials{
+		AccessKeyID:     "TEST-TOKEN-ACCESS-KEY",
+		SecretAccessKey: "TEST-TOKEN-SECRET-KEY",
+	}
+
+	slowProvider := aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		time.Sleep(10 * time.Millisecond)
+		return mockCreds, nil
+	})
+
+	token, err := GenerateToken(Ctx, TestRegion, slowProvider)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+	assert.NotEqual(t, int64(0), token.ExpirationTimeMs)
+	assert.GreaterOrEqual(t, token.CredentialFetchDuration, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, token.SignDuration, time.Duration(0))
+}
+
+func TestGenerateTokenWithDefaultCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "TEST-TOKEN-ACCESS-KEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "TEST-TOKEN-SECRET-KEY")
+
+	token, err := GenerateToken(Ctx, TestRegion, nil)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.Value)
+}
+
+func TestGenerateTokenWithFailingCredentialsProvider(t *testing.T) {
+	token, err := GenerateToken(Ctx, TestRegion, aws.AnonymousCredentials{})
+
+	assert.Error(t, err)
+	assert.Nil(t, token)
+}
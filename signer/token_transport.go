@@ -0,0 +1,97 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	DefaultTokenHeader = "Authorization" // DefaultTokenHeader is the request header TokenTransport sets by default.
+	DefaultTokenScheme = "Bearer"        // DefaultTokenScheme is the scheme TokenTransport prefixes tokens with.
+)
+
+// TokenTransport is an http.RoundTripper authenticating requests to REST based Kafka proxies fronting MSK with the auth
+// token of a TokenProvider, e.g. a Provider sharing its token cache and refresh with the Kafka clients of the service:
+//
+//	client := &http.Client{Transport: &signer.TokenTransport{Provider: provider}}
+type TokenTransport struct {
+	// Provider supplies the token of every request. It is required.
+	Provider TokenProvider
+
+	// Base sends the authenticated requests. http.DefaultTransport is used when nil.
+	Base http.RoundTripper
+
+	// Header is the request header carrying the token. DefaultTokenHeader is used when empty.
+	Header string
+
+	// Scheme prefixes the token in the header, separated by a space. DefaultTokenScheme is used when empty, and "-"
+	// sets the bare token.
+	Scheme string
+}
+
+var _ http.RoundTripper = (*TokenTransport)(nil)
+
+// RoundTrip sends a copy of the request carrying a current auth token. The original request is not modified.
+func (t *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Provider == nil {
+		closeRequestBody(req)
+		return nil, errors.New("token transport provider cannot be nil")
+	}
+
+	token, err := t.Provider.Token(req.Context())
+	if err != nil {
+		closeRequestBody(req)
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	authenticated := req.Clone(req.Context())
+	authenticated.Header.Set(t.header(), t.headerValue(token.Value))
+	return t.base().RoundTrip(authenticated)
+}
+
+// Returns the name of the header carrying the token.
+func (t *TokenTransport) header() string {
+	return tokenHeader(t.Header)
+}
+
+// Returns the header value carrying the token.
+func (t *TokenTransport) headerValue(token string) string {
+	return tokenHeaderValue(t.Scheme, token)
+}
+
+// Returns the round tripper sending the authenticated requests.
+func (t *TokenTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// Returns the name of the header carrying the token, DefaultTokenHeader when empty.
+func tokenHeader(header string) string {
+	if header == "" {
+		return DefaultTokenHeader
+	}
+	return header
+}
+
+// Returns the header value carrying the token prefixed with the scheme, DefaultTokenScheme when empty, or the bare
+// token for "-".
+func tokenHeaderValue(scheme string, token string) string {
+	switch scheme {
+	case "":
+		return DefaultTokenScheme + " " + token
+	case "-":
+		return token
+	default:
+		return scheme + " " + token
+	}
+}
+
+// Closes the request body, as round trippers must even when they fail.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
@@ -0,0 +1,73 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Starts a server recording the value of the header of the last request.
+func newHeaderRecordingServer(t *testing.T, header string) (*httptest.Server, *string) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(header)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestTokenTransport(t *testing.T) {
+	server, received := newHeaderRecordingServer(t, DefaultTokenHeader)
+	client := &http.Client{Transport: &TokenTransport{
+		Provider: StaticTokenProvider("test-token", time.Now().Add(time.Hour)),
+	}}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/topics", nil)
+	assert.NoError(t, err)
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "Bearer test-token", *received)
+	assert.Empty(t, req.Header.Get(DefaultTokenHeader))
+}
+
+func TestTokenTransportWithCustomHeader(t *testing.T) {
+	server, received := newHeaderRecordingServer(t, "X-MSK-Token")
+	client := &http.Client{Transport: &TokenTransport{
+		Provider: StaticTokenProvider("test-token", time.Now().Add(time.Hour)),
+		Header:   "X-MSK-Token",
+		Scheme:   "-",
+	}}
+
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "test-token", *received)
+}
+
+// Fails to supply tokens.
+type failingTokenProvider struct{}
+
+func (failingTokenProvider) Token(ctx context.Context) (*Token, error) {
+	return nil, errors.New("no credentials")
+}
+
+func TestTokenTransportFailsWithoutToken(t *testing.T) {
+	server, _ := newHeaderRecordingServer(t, DefaultTokenHeader)
+	client := &http.Client{Transport: &TokenTransport{Provider: failingTokenProvider{}}}
+
+	_, err := client.Get(server.URL)
+	assert.ErrorContains(t, err, "failed to get auth token: no credentials")
+
+	client.Transport = &TokenTransport{}
+	_, err = client.Get(server.URL)
+	assert.ErrorContains(t, err, "token transport provider cannot be nil")
+}
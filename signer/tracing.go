@@ -0,0 +1,23 @@
+package signer
+
+import "context"
+
+const (
+	SpanLoadCredentials = "LoadCredentials" // SpanLoadCredentials names the span around credential retrieval.
+	SpanSignToken       = "SignToken"       // SpanSignToken names the span around signing the auth token.
+)
+
+// Tracer starts spans around the steps of token generation so their latency is visible in tracing backends.
+type Tracer interface {
+	// StartSpan starts a span with the given name as a child of any span carried by ctx. It returns the context to use
+	// within the span and a function that ends the span, recording err when it is not nil.
+	StartSpan(ctx context.Context, name string) (context.Context, func(err error))
+}
+
+// Starts a span with the configured tracer, falling back to a no-op span when tracing is disabled.
+func startSpan(ctx context.Context, tracer Tracer, name string) (context.Context, func(err error)) {
+	if tracer == nil {
+		return ctx, func(error) {}
+	}
+	return tracer.StartSpan(ctx, name)
+}
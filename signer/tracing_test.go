@@ -0,0 +1,49 @@
+package signer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+// Records the spans started and the errors they ended with.
+type recordingTracer struct {
+	spans  []string
+	errors []error
+}
+
+func (r *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, func(err error)) {
+	r.spans = append(r.spans, name)
+	return ctx, func(err error) {
+		r.errors = append(r.errors, err)
+	}
+}
+
+func TestTracerSpansAroundGeneration(t *testing.T) {
+	tracer := &recordingTracer{}
+	mockCreds := aws.Credentials{
+		AccessKeyID:     "TEST-TRACE-ACCESS-KEY",
+		SecretAccessKey: "TEST-TRACE-SECRET-KEY",
+	}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion,
+		MockCredentialsProvider{credentials: mockCreds}, WithTracer(tracer))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{SpanLoadCredentials, SpanSignToken}, tracer.spans)
+	assert.Equal(t, []error{nil, nil}, tracer.errors)
+}
+
+func TestTracerRecordsCredentialsFailure(t *testing.T) {
+	tracer := &recordingTracer{}
+
+	_, _, err := GenerateAuthTokenFromCredentialsProvider(Ctx, TestRegion, aws.AnonymousCredentials{},
+		WithTracer(tracer))
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{SpanLoadCredentials}, tracer.spans)
+	assert.Len(t, tracer.errors, 1)
+	assert.Error(t, tracer.errors[0])
+}
@@ -0,0 +1,77 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// WebIdentityTokenFetcher returns the OIDC token of a federated workload identity, such as a GCP service account ID
+// token or an Azure workload identity token, exchanged for AWS credentials with AssumeRoleWithWebIdentity. It is called
+// on every credential retrieval and must return a fresh token.
+type WebIdentityTokenFetcher func(ctx context.Context) (string, error)
+
+// GenerateAuthTokenFromWebIdentity generates base64 encoded signed url as auth token by assuming the role with the OIDC
+// token returned by fetchToken, letting workloads running outside AWS authenticate without static keys.
+func GenerateAuthTokenFromWebIdentity(
+	ctx context.Context, region string, roleArn string, stsSessionName string, fetchToken WebIdentityTokenFetcher,
+	optFns ...Option,
+) (string, int64, error) {
+	if stsSessionName == "" {
+		stsSessionName = DefaultSessionName
+	}
+
+	options := resolveOptions(optFns)
+	loadCredentials := func(ctx context.Context, _ bool) (*aws.Credentials, error) {
+		return loadCredentialsFromWebIdentity(ctx, region, roleArn, stsSessionName, fetchToken, options)
+	}
+	return unpackToken(generateAuthToken(ctx, region, options, loadCredentials))
+}
+
+// Loads credentials by assuming the role with the fetched web identity token.
+func loadCredentialsFromWebIdentity(
+	ctx context.Context, region string, roleArn string, stsSessionName string, fetchToken WebIdentityTokenFetcher,
+	options Options,
+) (*aws.Credentials, error) {
+	if fetchToken == nil {
+		return nil, errors.New("web identity token fetcher cannot be nil")
+	}
+
+	webIdentityToken, err := fetchToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch web identity token: %w", err)
+	}
+
+	stsClient, err := roleSTSClient(ctx, region, roleArn, options)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := stsClient.AssumeRoleWithWebIdentity(ctx, &sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(roleArn),
+		RoleSessionName:  aws.String(stsSessionName),
+		WebIdentityToken: aws.String(webIdentityToken),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to assume role with web identity, %s: %w", roleArn, err)
+	}
+
+	if output.Credentials == nil {
+		return nil, fmt.Errorf("unable to assume role with web identity, %s: no credentials returned", roleArn)
+	}
+
+	creds := aws.Credentials{
+		AccessKeyID:     aws.ToString(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.ToString(output.Credentials.SessionToken),
+	}
+	if output.Credentials.Expiration != nil {
+		creds.CanExpire = true
+		creds.Expires = *output.Credentials.Expiration
+	}
+
+	return &creds, nil
+}
@@ -0,0 +1,89 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const assumeRoleWithWebIdentityResponse = `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>TEST-WEB-IDENTITY-ACCESS-KEY</AccessKeyId>
+      <SecretAccessKey>TEST-WEB-IDENTITY-SECRET-KEY</SecretAccessKey>
+      <SessionToken>TEST-WEB-IDENTITY-SESSION-TOKEN</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`
+
+// Points the sts clients created by the signer at a local server exchanging the expected web identity token.
+func withSTSWebIdentityServer(t *testing.T, expectedToken string) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+		if r.Form.Get("WebIdentityToken") != expectedToken {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<ErrorResponse><Error><Code>InvalidIdentityToken</Code></Error></ErrorResponse>`)
+			return
+		}
+		fmt.Fprint(w, assumeRoleWithWebIdentityResponse)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+}
+
+func TestGenerateAuthTokenFromWebIdentity(t *testing.T) {
+	withSTSWebIdentityServer(t, "TEST-OIDC-TOKEN")
+	fetchToken := func(ctx context.Context) (string, error) {
+		return "TEST-OIDC-TOKEN", nil
+	}
+
+	token, expiryMs, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion,
+		"arn:aws:iam::123456789012:role/TestRole", "", fetchToken)
+
+	assert.NoError(t, err)
+	assert.NotZero(t, expiryMs)
+	assert.True(t, strings.HasPrefix(decodeTokenParams(t, token).Get("X-Amz-Credential"), "TEST-WEB-IDENTITY-ACCESS-KEY/"))
+}
+
+func TestGenerateAuthTokenFromWebIdentityFetchFailure(t *testing.T) {
+	fetchToken := func(ctx context.Context) (string, error) {
+		return "", errors.New("metadata server unavailable")
+	}
+
+	_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion,
+		"arn:aws:iam::123456789012:role/TestRole", "", fetchToken)
+
+	assert.ErrorContains(t, err, "metadata server unavailable")
+}
+
+func TestGenerateAuthTokenFromWebIdentityNilFetcher(t *testing.T) {
+	_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion, "arn:aws:iam::123456789012:role/TestRole", "", nil)
+
+	assert.ErrorContains(t, err, "fetcher cannot be nil")
+}
+
+func TestGenerateAuthTokenFromWebIdentityWithoutCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">`+
+			`<AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+	fetchToken := func(ctx context.Context) (string, error) {
+		return "TEST-OIDC-TOKEN", nil
+	}
+
+	_, _, err := GenerateAuthTokenFromWebIdentity(Ctx, TestRegion,
+		"arn:aws:iam::123456789012:role/TestRole", "", fetchToken)
+
+	assert.ErrorContains(t, err, "no credentials returned")
+}